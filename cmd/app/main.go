@@ -5,12 +5,18 @@ import (
 	"go.uber.org/fx/fxevent"
 	"go.uber.org/zap"
 
+	"github.com/dupreehkuda/avito-segments/internal/auth"
 	"github.com/dupreehkuda/avito-segments/internal/config"
 	"github.com/dupreehkuda/avito-segments/internal/handlers"
+	"github.com/dupreehkuda/avito-segments/internal/idempotency"
 	"github.com/dupreehkuda/avito-segments/internal/logger"
+	"github.com/dupreehkuda/avito-segments/internal/publisher"
 	"github.com/dupreehkuda/avito-segments/internal/repository"
 	"github.com/dupreehkuda/avito-segments/internal/server"
 	"github.com/dupreehkuda/avito-segments/internal/service"
+	"github.com/dupreehkuda/avito-segments/internal/storage"
+	"github.com/dupreehkuda/avito-segments/internal/webhook"
+	"github.com/dupreehkuda/avito-segments/internal/worker"
 )
 
 func main() {
@@ -24,15 +30,54 @@ func main() {
 			repository.New,
 			fx.As(new(service.UserRepository)),
 			fx.As(new(service.SegmentRepository)),
+			fx.As(new(service.JobRepository)),
+			fx.As(new(worker.ExpiryRepository)),
+			fx.As(new(worker.RelayRepository)),
+			fx.As(new(service.WebhookRepository)),
+			fx.As(new(service.EventRepository)),
+			fx.As(new(worker.WebhookRepository)),
+			fx.As(new(service.APIKeyRepository)),
+			fx.As(new(auth.KeyRepository)),
+			fx.As(new(service.AuditRepository)),
+			fx.As(new(idempotency.Repository)),
+		)),
+		fx.Provide(fx.Annotate(
+			publisher.New,
+			fx.As(new(worker.Publisher)),
+		)),
+		fx.Provide(fx.Annotate(
+			webhook.New,
+			fx.As(new(worker.WebhookSender)),
+		)),
+		fx.Provide(fx.Annotate(
+			storage.New,
+			fx.As(new(service.ReportStorage)),
+		)),
+		fx.Provide(fx.Annotate(
+			worker.RegisterPool,
+			fx.As(new(service.Scheduler)),
 		)),
 		fx.Provide(fx.Annotate(
 			service.New,
 			fx.As(new(handlers.Service)),
+			fx.As(new(worker.JobKeeper)),
+		)),
+		fx.Provide(fx.Annotate(
+			auth.New,
+			fx.As(new(server.Authenticator)),
+		)),
+		fx.Provide(fx.Annotate(
+			idempotency.Register,
+			fx.As(new(server.Idempotency)),
 		)),
 		fx.Provide(fx.Annotate(
 			handlers.New,
 			fx.As(new(server.Handlers)),
 		)),
 		fx.Invoke(server.RegisterServer),
+		fx.Invoke(worker.RegisterExpiry),
+		fx.Invoke(worker.RegisterRelay),
+		fx.Invoke(worker.RegisterDispatcher),
+		fx.Invoke(worker.RegisterJobs),
 	).Run()
 }
@@ -0,0 +1,255 @@
+// Package auth authenticates api clients and checks the scopes they were granted.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/problem"
+)
+
+//go:generate mockgen -source=auth.go -destination=mock_test.go -package=auth_test
+
+// HeaderAPIKey is the header holding client's api key.
+const HeaderAPIKey = "X-API-Key"
+
+const (
+	// KeyPrefix starts every generated api key, so leaked keys are easy to find.
+	KeyPrefix = "seg_"
+	// PrefixLength is the length of key's beginning kept to tell keys apart.
+	PrefixLength = len(KeyPrefix) + 8
+
+	principalKey = "principal"
+)
+
+// Principal ids are namespaced by the way principal was authenticated,
+// so ids of api keys and token subjects never collide in audit log and idempotency keys.
+const (
+	KeyPrincipalPrefix = "key:"
+	JWTPrincipalPrefix = "jwt:"
+)
+
+// Principals which are not stored as api keys.
+const (
+	AdminName   = "admin"
+	AdminID     = KeyPrincipalPrefix + AdminName
+	AnonymousID = "anonymous"
+)
+
+// ErrNoAdmin is returned when authentication is enabled, but no one can get admin scope to create the first api key.
+var ErrNoAdmin = errors.New("auth is enabled, but neither admin key nor jwt role with admin scope is configured")
+
+type principalContextKey struct{}
+
+type KeyRepository interface {
+	APIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error)
+}
+
+// Auth authenticates requests by api keys or bearer tokens.
+type Auth struct {
+	repo     KeyRepository
+	jwt      *JWT
+	enabled  bool
+	adminKey string
+	logger   *zap.Logger
+}
+
+// New creates new instance of Auth. Admin key from config is accepted along with stored keys and grants all scopes.
+// Bearer tokens are accepted if key set to verify them is configured.
+// With authentication enabled either admin key or jwt role granting admin scope is required,
+// otherwise api keys could never be created.
+func New(repo KeyRepository, config *config.Config, logger *zap.Logger) (*Auth, error) {
+	a := &Auth{
+		repo:     repo,
+		jwt:      NewJWT(config),
+		enabled:  config.Auth.Enabled,
+		adminKey: config.Auth.AdminKey,
+		logger:   logger,
+	}
+
+	if a.enabled && a.adminKey == "" && !a.jwt.grants(models.ScopeAdmin) {
+		return nil, ErrNoAdmin
+	}
+
+	return a, nil
+}
+
+// Authenticate puts the principal identified by bearer token or api key on echo and request contexts.
+// With authentication disabled every request is made by anonymous principal with all scopes.
+func (a *Auth) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !a.enabled {
+			return next(WithPrincipal(c, &models.Principal{ID: AnonymousID, Scopes: models.Scopes}))
+		}
+
+		if token, ok := bearerToken(c.Request().Header.Get(echo.HeaderAuthorization)); ok {
+			return a.authenticateToken(c, next, token)
+		}
+
+		key := c.Request().Header.Get(HeaderAPIKey)
+		if key == "" {
+			return unauthorized("missing api key or bearer token")
+		}
+
+		if a.adminKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(a.adminKey)) == 1 {
+			return next(WithPrincipal(c, &models.Principal{ID: AdminID, Name: AdminName, Scopes: models.Scopes}))
+		}
+
+		stored, err := a.repo.APIKeyByHash(c.Request().Context(), HashKey(key))
+		if err != nil {
+			a.logger.Error("Unable to look up api key", zap.Error(err))
+			return problem.New(http.StatusInternalServerError, "internal_server_error", "internal server error")
+		}
+
+		if stored == nil {
+			return unauthorized("invalid api key")
+		}
+
+		return next(WithPrincipal(c, &models.Principal{ID: KeyPrincipalID(stored.ID), Name: stored.Name, Scopes: stored.Scopes}))
+	}
+}
+
+func (a *Auth) authenticateToken(c echo.Context, next echo.HandlerFunc, token string) error {
+	if a.jwt == nil {
+		return unauthorized("bearer tokens are not accepted")
+	}
+
+	principal, err := a.jwt.Verify(c.Request().Context(), token)
+	if err != nil {
+		a.logger.Info("Bearer token rejected", zap.Error(err))
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+
+		return unauthorized("invalid bearer token")
+	}
+
+	return next(WithPrincipal(c, principal))
+}
+
+// Require rejects requests of principals without the scope.
+func Require(scope string) echo.MiddlewareFunc {
+	return RequireReadWrite(scope, scope)
+}
+
+// RequireReadWrite rejects reading requests of principals without read scope and others without write scope.
+func RequireReadWrite(read, write string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scope := write
+
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				scope = read
+			}
+
+			if err := Authorize(c, scope); err != nil {
+				return err
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Authorize rejects request of principal without the scope.
+// Used by handlers when the scope depends on the request itself.
+func Authorize(c echo.Context, scope string) error {
+	principal := Principal(c)
+	if principal == nil {
+		return unauthorized("not authenticated")
+	}
+
+	if !HasScope(principal, scope) {
+		return problem.New(http.StatusForbidden, "missing_scope", "missing scope "+scope)
+	}
+
+	return nil
+}
+
+// Principal returns principal authenticated for the request or nil.
+func Principal(c echo.Context) *models.Principal {
+	principal, _ := c.Get(principalKey).(*models.Principal)
+	return principal
+}
+
+// FromContext returns principal authenticated for the request the context belongs to or nil.
+func FromContext(ctx context.Context) *models.Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*models.Principal)
+	return principal
+}
+
+// NewContext returns context carrying the principal.
+func NewContext(ctx context.Context, principal *models.Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// HasScope checks that principal was granted the scope.
+func HasScope(principal *models.Principal, scope string) bool {
+	for _, granted := range principal.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// KeyPrincipalID returns id of principal authenticated by stored api key.
+func KeyPrincipalID(id string) string {
+	return KeyPrincipalPrefix + id
+}
+
+// JWTPrincipalID returns id of principal authenticated by token of the issuer issued to the subject.
+func JWTPrincipalID(issuer, subject string) string {
+	return JWTPrincipalPrefix + issuer + ":" + subject
+}
+
+// GenerateKey returns new random api key.
+func GenerateKey() (string, error) {
+	key := make([]byte, 24)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return KeyPrefix + hex.EncodeToString(key), nil
+}
+
+// HashKey returns hash api key is stored by. Keys are random, so plain SHA-256 is enough to keep them secret.
+func HashKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// WithPrincipal authenticates request of the context as the principal.
+func WithPrincipal(c echo.Context, principal *models.Principal) echo.Context {
+	c.Set(principalKey, principal)
+	c.SetRequest(c.Request().WithContext(NewContext(c.Request().Context(), principal)))
+
+	return c
+}
+
+// bearerToken extracts token from Authorization header of Bearer scheme.
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+
+	return token, token != ""
+}
+
+func unauthorized(message string) *echo.HTTPError {
+	return problem.New(http.StatusUnauthorized, "unauthorized", message)
+}
@@ -0,0 +1,235 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/auth"
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+const (
+	testAdminKey = "seg_admin0123456789abcdef"
+	testKey      = "seg_0123456789abcdef0123456789abcdef0123456789abcdef"
+)
+
+func TestAuth_Authenticate(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name     string
+		disabled bool
+		method   string
+		key      string
+
+		storedKey   *models.APIKey
+		lookupError error
+
+		expectedStatusCode int
+		expectedPrincipal  string
+		expectingLookup    bool
+	}{
+		{
+			name:               "Authentication disabled",
+			disabled:           true,
+			method:             http.MethodPost,
+			expectedStatusCode: http.StatusOK,
+			expectedPrincipal:  auth.AnonymousID,
+		},
+		{
+			name:               "Missing key",
+			method:             http.MethodGet,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "Admin key",
+			method:             http.MethodPost,
+			key:                testAdminKey,
+			expectedStatusCode: http.StatusOK,
+			expectedPrincipal:  auth.AdminID,
+		},
+		{
+			name:               "Read scope",
+			method:             http.MethodGet,
+			key:                testKey,
+			storedKey:          &models.APIKey{ID: "reader", Scopes: []string{models.ScopeUsersRead}},
+			expectedStatusCode: http.StatusOK,
+			expectedPrincipal:  "key:reader",
+			expectingLookup:    true,
+		},
+		{
+			name:               "Write without write scope",
+			method:             http.MethodPost,
+			key:                testKey,
+			storedKey:          &models.APIKey{ID: "reader", Scopes: []string{models.ScopeUsersRead}},
+			expectedStatusCode: http.StatusForbidden,
+			expectingLookup:    true,
+		},
+		{
+			name:               "Write scope",
+			method:             http.MethodDelete,
+			key:                testKey,
+			storedKey:          &models.APIKey{ID: "writer", Scopes: []string{models.ScopeUsersWrite}},
+			expectedStatusCode: http.StatusOK,
+			expectedPrincipal:  "key:writer",
+			expectingLookup:    true,
+		},
+		{
+			name:               "Unknown or revoked key",
+			method:             http.MethodGet,
+			key:                testKey,
+			expectedStatusCode: http.StatusUnauthorized,
+			expectingLookup:    true,
+		},
+		{
+			name:               "Some internal error",
+			method:             http.MethodGet,
+			key:                testKey,
+			lookupError:        os.ErrInvalid,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectingLookup:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := NewMockKeyRepository(ctrl)
+
+			if tc.expectingLookup {
+				repo.EXPECT().APIKeyByHash(gomock.Any(), auth.HashKey(tc.key)).Return(tc.storedKey, tc.lookupError)
+			}
+
+			cfg := &config.Config{}
+			cfg.Auth.Enabled = !tc.disabled
+			cfg.Auth.AdminKey = testAdminKey
+
+			zp, _ := zap.NewDevelopment()
+			authenticator, err := auth.New(repo, cfg, zp)
+			a.NoError(err)
+
+			var principal string
+
+			handler := func(c echo.Context) error {
+				a.Equal(auth.Principal(c), auth.FromContext(c.Request().Context()))
+				principal = auth.Principal(c).ID
+
+				return c.NoContent(http.StatusOK)
+			}
+
+			e := echo.New()
+			users := e.Group("/user", authenticator.Authenticate,
+				auth.RequireReadWrite(models.ScopeUsersRead, models.ScopeUsersWrite))
+			users.GET("", handler)
+			users.POST("", handler)
+			users.DELETE("", handler)
+
+			req := httptest.NewRequest(tc.method, "/user", nil)
+			if tc.key != "" {
+				req.Header.Set(auth.HeaderAPIKey, tc.key)
+			}
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+			a.Equal(tc.expectedPrincipal, principal)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name     string
+		disabled bool
+		adminKey string
+		jwks     string
+		roles    map[string][]string
+
+		expectedError error
+	}{
+		{
+			name:     "Authentication disabled",
+			disabled: true,
+		},
+		{
+			name:     "Admin key",
+			adminKey: testAdminKey,
+		},
+		{
+			name:  "Jwt role with admin scope",
+			jwks:  "jwks.json",
+			roles: map[string][]string{"segments-admin": {models.ScopeAdmin}},
+		},
+		{
+			name:          "Jwt roles without admin scope",
+			jwks:          "jwks.json",
+			roles:         map[string][]string{"segments-analyst": {models.ScopeSegmentsRead}},
+			expectedError: auth.ErrNoAdmin,
+		},
+		{
+			name:          "Admin role without jwt",
+			roles:         map[string][]string{"segments-admin": {models.ScopeAdmin}},
+			expectedError: auth.ErrNoAdmin,
+		},
+		{
+			name:          "No admin",
+			expectedError: auth.ErrNoAdmin,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Auth.Enabled = !tc.disabled
+			cfg.Auth.AdminKey = tc.adminKey
+			cfg.Auth.JWT.JWKSFile = tc.jwks
+			cfg.Auth.JWT.Roles = tc.roles
+
+			zp, _ := zap.NewDevelopment()
+			authenticator, err := auth.New(nil, cfg, zp)
+
+			a.ErrorIs(err, tc.expectedError)
+			a.Equal(tc.expectedError == nil, authenticator != nil)
+		})
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	a := assert.New(t)
+
+	key, err := auth.GenerateKey()
+	a.NoError(err)
+
+	other, err := auth.GenerateKey()
+	a.NoError(err)
+
+	a.Len(key, len(auth.KeyPrefix)+48)
+	a.Equal(auth.KeyPrefix, key[:len(auth.KeyPrefix)])
+	a.NotEqual(key, other)
+	a.NotEqual(auth.HashKey(key), auth.HashKey(other))
+	a.Len(auth.HashKey(key), 64)
+}
+
+func TestFromContext(t *testing.T) {
+	a := assert.New(t)
+
+	principal := &models.Principal{ID: "reader"}
+
+	a.Nil(auth.FromContext(context.Background()))
+	a.Equal(principal, auth.FromContext(auth.NewContext(context.Background(), principal)))
+}
@@ -0,0 +1,312 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+const (
+	defaultScopeClaim  = "scope"
+	defaultJWKSRefresh = time.Hour
+	// minJWKSReload is the shortest pause between key set reloads caused by unknown key ids,
+	// so tokens with made up key ids can not flood the provider.
+	minJWKSReload = time.Minute
+	jwksTimeout   = 10 * time.Second
+	maxJWKSSize   = 1 << 20
+	tokenLeeway   = 30 * time.Second
+)
+
+// ErrUnknownKey is returned for token signed by a key which is not in the key set.
+var ErrUnknownKey = errors.New("token is signed by unknown key")
+
+// signingMethods are the asymmetric algorithms accepted in tokens.
+var signingMethods = []string{
+	"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA",
+}
+
+// JWT verifies bearer tokens signed by keys of JSON Web Key Set from a file or url.
+// Key set is reloaded when it gets older than refresh interval or a token is signed by unknown key.
+type JWT struct {
+	file       string
+	url        string
+	refresh    time.Duration
+	scopeClaim string
+	roles      map[string][]string
+	parser     *jwt.Parser
+	client     *http.Client
+
+	// reloads lets concurrent requests wait for the same key set reload.
+	reloads  singleflight.Group
+	mu       sync.RWMutex
+	keys     map[string]crypto.PublicKey
+	loadedAt time.Time
+}
+
+// NewJWT creates verifier of bearer tokens or returns nil if no key set is configured.
+func NewJWT(config *config.Config) *JWT {
+	cfg := config.Auth.JWT
+
+	if cfg.JWKSFile == "" && cfg.JWKSURL == "" {
+		return nil
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(tokenLeeway),
+	}
+
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+
+	j := &JWT{
+		file:       cfg.JWKSFile,
+		url:        cfg.JWKSURL,
+		refresh:    cfg.Refresh,
+		scopeClaim: cfg.ScopeClaim,
+		roles:      cfg.Roles,
+		parser:     jwt.NewParser(options...),
+		client:     &http.Client{Timeout: jwksTimeout},
+	}
+
+	if j.refresh <= 0 {
+		j.refresh = defaultJWKSRefresh
+	}
+
+	if j.scopeClaim == "" {
+		j.scopeClaim = defaultScopeClaim
+	}
+
+	return j
+}
+
+// Verify checks token's signature, expiry, issuer and audience and returns the principal it was issued to.
+// Principal is identified by the issuer and subject and granted the scopes mapped from scope claim.
+func (j *JWT) Verify(ctx context.Context, token string) (*models.Principal, error) {
+	claims := jwt.MapClaims{}
+
+	_, err := j.parser.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return j.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return nil, err
+	}
+
+	if subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	issuer, err := claims.GetIssuer()
+	if err != nil {
+		return nil, err
+	}
+
+	principal := &models.Principal{
+		ID:     JWTPrincipalID(issuer, subject),
+		Name:   subject,
+		Scopes: j.scopes(claims[j.scopeClaim]),
+	}
+
+	for _, claim := range []string{"preferred_username", "email", "name"} {
+		if name, ok := claims[claim].(string); ok && name != "" {
+			principal.Name = name
+			break
+		}
+	}
+
+	return principal, nil
+}
+
+// grants checks that some role grants the scope. Nil verifier accepts no tokens and grants nothing.
+func (j *JWT) grants(scope string) bool {
+	if j == nil {
+		return false
+	}
+
+	for _, scopes := range j.roles {
+		for _, granted := range scopes {
+			if granted == scope {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// key returns the key token is signed by. Token without key id may be signed by the only key of the set.
+// Key set is reloaded without holding the lock, so requests with known keys are not held up by the provider.
+func (j *JWT) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	key, found, loadedAt := j.lookup(kid)
+
+	age := time.Since(loadedAt)
+	if age > j.refresh || (!found && age > minJWKSReload) {
+		if err := j.reload(ctx, loadedAt); err != nil && !found {
+			return nil, err
+		}
+
+		key, found, _ = j.lookup(kid)
+	}
+
+	if !found {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+// lookup returns the key by its id and the moment key set was loaded.
+func (j *JWT) lookup(kid string) (crypto.PublicKey, bool, time.Time) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if kid == "" && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true, j.loadedAt
+		}
+	}
+
+	key, found := j.keys[kid]
+
+	return key, found, j.loadedAt
+}
+
+// reload loads key set once for all requests waiting for it, unless it was loaded after the moment they saw.
+// Loading is detached from the requests, so one of them going away does not fail the others.
+func (j *JWT) reload(ctx context.Context, seen time.Time) error {
+	result := j.reloads.DoChan("", func() (interface{}, error) {
+		j.mu.RLock()
+		loadedAt := j.loadedAt
+		j.mu.RUnlock()
+
+		if loadedAt.After(seen) {
+			return nil, nil
+		}
+
+		return nil, j.load(context.Background())
+	})
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res := <-result:
+		return res.Err
+	}
+}
+
+// load reads key set from file or url and swaps it in. Previous keys are kept if it fails.
+func (j *JWT) load(ctx context.Context) error {
+	var keys map[string]crypto.PublicKey
+
+	data, err := j.read(ctx)
+	if err == nil {
+		keys, err = ParseJWKS(data)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err == nil {
+		j.keys = keys
+	}
+
+	// Failed attempt counts too, so unavailable provider is not asked on every request.
+	j.loadedAt = time.Now()
+
+	if err != nil {
+		return fmt.Errorf("unable to load key set: %w", err)
+	}
+
+	return nil
+}
+
+func (j *JWT) read(ctx context.Context) ([]byte, error) {
+	if j.file != "" {
+		return os.ReadFile(j.file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+// scopes maps scope claim to scopes. Claim is either space separated string as in OAuth 2.0 or a list,
+// its values are taken as is if they are scopes and replaced by configured scopes if they are roles.
+func (j *JWT) scopes(claim interface{}) []string {
+	var values []string
+
+	switch claim := claim.(type) {
+	case string:
+		values = strings.Fields(claim)
+	case []interface{}:
+		for _, value := range claim {
+			if value, ok := value.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+
+	var (
+		scopes  []string
+		granted = make(map[string]bool)
+	)
+
+	grant := func(scope string) {
+		if !granted[scope] {
+			granted[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	for _, value := range values {
+		for _, scope := range models.Scopes {
+			if value == scope {
+				grant(scope)
+			}
+		}
+
+		for _, scope := range j.roles[value] {
+			grant(scope)
+		}
+	}
+
+	return scopes
+}
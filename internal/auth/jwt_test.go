@@ -0,0 +1,376 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/auth"
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+const (
+	testIssuer   = "https://id.example.com"
+	testAudience = "segments"
+)
+
+// testKeySet is a locally generated key set along with its JWKS document.
+type testKeySet struct {
+	rsa     *rsa.PrivateKey
+	ecdsa   *ecdsa.PrivateKey
+	ed25519 ed25519.PrivateKey
+	jwks    []byte
+}
+
+func newTestKeySet(t *testing.T) *testKeySet {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA", "kid": "rsa", "use": "sig",
+				"n": encode(rsaKey.N.Bytes()), "e": encode(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "EC", "kid": "ec", "crv": "P-256",
+				"x": encode(ecKey.X.Bytes()), "y": encode(ecKey.Y.Bytes()),
+			},
+			{
+				"kty": "OKP", "kid": "ed", "crv": "Ed25519",
+				"x": encode(edKey.Public().(ed25519.PublicKey)),
+			},
+			{
+				"kty": "RSA", "kid": "enc", "use": "enc",
+				"n": encode(rsaKey.N.Bytes()), "e": encode(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	return &testKeySet{rsa: rsaKey, ecdsa: ecKey, ed25519: edKey, jwks: jwks}
+}
+
+func (s *testKeySet) sign(t *testing.T, method jwt.SigningMethod, kid string, claims jwt.MapClaims) string {
+	var key interface{}
+
+	switch method.(type) {
+	case *jwt.SigningMethodRSA:
+		key = s.rsa
+	case *jwt.SigningMethodECDSA:
+		key = s.ecdsa
+	default:
+		key = s.ed25519
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func testClaims(scope interface{}) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "staff-42",
+		"email": "staff@example.com",
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": scope,
+	}
+}
+
+func testJWTConfig(jwksFile, jwksURL string) *config.Config {
+	cfg := &config.Config{}
+	cfg.Auth.Enabled = true
+	cfg.Auth.JWT.JWKSFile = jwksFile
+	cfg.Auth.JWT.JWKSURL = jwksURL
+	cfg.Auth.JWT.Issuer = testIssuer
+	cfg.Auth.JWT.Audience = testAudience
+	cfg.Auth.JWT.Roles = map[string][]string{
+		"segments-analyst": {models.ScopeSegmentsRead, models.ScopeReportsRead},
+	}
+
+	return cfg
+}
+
+func TestJWT_Verify(t *testing.T) {
+	a := assert.New(t)
+
+	keys := newTestKeySet(t)
+
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksFile, keys.jwks, 0o600))
+
+	expired := testClaims("users:read")
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+
+	foreign := testClaims("users:read")
+	foreign["aud"] = "billing"
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims("users:read")).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	hmac, err := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims("users:read")).SignedString(keys.jwks)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		token          string
+		expectedScopes []string
+		expectingError bool
+	}{
+		{
+			name:           "RSA token with scopes",
+			token:          keys.sign(t, jwt.SigningMethodRS256, "rsa", testClaims("users:read users:write openid")),
+			expectedScopes: []string{models.ScopeUsersRead, models.ScopeUsersWrite},
+		},
+		{
+			name:           "EC token with role",
+			token:          keys.sign(t, jwt.SigningMethodES256, "ec", testClaims([]interface{}{"segments-analyst", "users:read"})),
+			expectedScopes: []string{models.ScopeSegmentsRead, models.ScopeReportsRead, models.ScopeUsersRead},
+		},
+		{
+			name:           "Ed25519 token without scopes",
+			token:          keys.sign(t, jwt.SigningMethodEdDSA, "ed", testClaims(nil)),
+			expectedScopes: nil,
+		},
+		{
+			name:           "Unknown key id",
+			token:          keys.sign(t, jwt.SigningMethodRS256, "rotated", testClaims("users:read")),
+			expectingError: true,
+		},
+		{
+			name:           "Encryption key",
+			token:          keys.sign(t, jwt.SigningMethodRS256, "enc", testClaims("users:read")),
+			expectingError: true,
+		},
+		{
+			name:           "Algorithm does not match key",
+			token:          keys.sign(t, jwt.SigningMethodES256, "rsa", testClaims("users:read")),
+			expectingError: true,
+		},
+		{
+			name:           "Expired",
+			token:          keys.sign(t, jwt.SigningMethodRS256, "rsa", expired),
+			expectingError: true,
+		},
+		{
+			name:           "Other audience",
+			token:          keys.sign(t, jwt.SigningMethodRS256, "rsa", foreign),
+			expectingError: true,
+		},
+		{
+			name:           "Unsigned",
+			token:          unsigned,
+			expectingError: true,
+		},
+		{
+			name:           "Symmetric",
+			token:          hmac,
+			expectingError: true,
+		},
+	}
+
+	verifier := auth.NewJWT(testJWTConfig(jwksFile, ""))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			principal, err := verifier.Verify(context.Background(), tc.token)
+
+			a.Equal(tc.expectingError, err != nil, err)
+
+			if !tc.expectingError {
+				a.Equal("jwt:"+testIssuer+":staff-42", principal.ID)
+				a.Equal("staff@example.com", principal.Name)
+				a.Equal(tc.expectedScopes, principal.Scopes)
+			}
+		})
+	}
+}
+
+func TestJWT_VerifyFromURL(t *testing.T) {
+	a := assert.New(t)
+
+	keys := newTestKeySet(t)
+
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		_, _ = w.Write(keys.jwks)
+	}))
+	defer server.Close()
+
+	verifier := auth.NewJWT(testJWTConfig("", server.URL))
+
+	for i := 0; i < 3; i++ {
+		principal, err := verifier.Verify(context.Background(),
+			keys.sign(t, jwt.SigningMethodRS256, "rsa", testClaims("reports:read")))
+
+		a.NoError(err)
+		a.Equal([]string{models.ScopeReportsRead}, principal.Scopes)
+	}
+
+	// Unknown key right after loading does not cause another request.
+	_, err := verifier.Verify(context.Background(), keys.sign(t, jwt.SigningMethodRS256, "rotated", testClaims("reports:read")))
+
+	a.ErrorIs(err, auth.ErrUnknownKey)
+	a.Equal(1, requests)
+}
+
+func TestJWT_VerifyWhileLoading(t *testing.T) {
+	a := assert.New(t)
+
+	keys := newTestKeySet(t)
+
+	var (
+		requests atomic.Int32
+		loading  = make(chan struct{})
+		release  = make(chan struct{})
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			close(loading)
+		}
+
+		<-release
+
+		_, _ = w.Write(keys.jwks)
+	}))
+	defer server.Close()
+
+	verifier := auth.NewJWT(testJWTConfig("", server.URL))
+	token := keys.sign(t, jwt.SigningMethodRS256, "rsa", testClaims("reports:read"))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := verifier.Verify(context.Background(), token)
+			a.NoError(err)
+		}()
+	}
+
+	<-loading
+
+	// Request going away stops waiting for the provider without failing the others.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := verifier.Verify(ctx, token)
+	a.ErrorIs(err, context.Canceled)
+
+	close(release)
+	wg.Wait()
+
+	a.Equal(int32(1), requests.Load(), "Key set should be loaded once for all waiting requests")
+}
+
+func TestAuth_AuthenticateBearer(t *testing.T) {
+	a := assert.New(t)
+
+	keys := newTestKeySet(t)
+
+	jwksFile := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksFile, keys.jwks, 0o600))
+
+	testCases := []struct {
+		name               string
+		keySet             string
+		header             string
+		expectedStatusCode int
+	}{
+		{
+			name:               "Token with scope",
+			keySet:             jwksFile,
+			header:             "Bearer " + keys.sign(t, jwt.SigningMethodRS256, "rsa", testClaims("users:read")),
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Token without scope",
+			keySet:             jwksFile,
+			header:             "Bearer " + keys.sign(t, jwt.SigningMethodRS256, "rsa", testClaims("reports:read")),
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Invalid token",
+			keySet:             jwksFile,
+			header:             "Bearer token",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "Bearer tokens not configured",
+			header:             "Bearer " + keys.sign(t, jwt.SigningMethodRS256, "rsa", testClaims("users:read")),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			zp, _ := zap.NewDevelopment()
+			cfg := testJWTConfig(tc.keySet, "")
+			cfg.Auth.AdminKey = testAdminKey
+
+			authenticator, err := auth.New(nil, cfg, zp)
+			a.NoError(err)
+
+			e := echo.New()
+			users := e.Group("/user", authenticator.Authenticate, auth.Require(models.ScopeUsersRead))
+			users.GET("", func(c echo.Context) error {
+				a.Equal(auth.JWTPrincipalID(testIssuer, "staff-42"), auth.FromContext(c.Request().Context()).ID)
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/user", nil)
+			req.Header.Set(echo.HeaderAuthorization, tc.header)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: auth.go
+
+// Package auth_test is a generated GoMock package.
+package auth_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/dupreehkuda/avito-segments/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockKeyRepository is a mock of KeyRepository interface.
+type MockKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyRepositoryMockRecorder
+}
+
+// MockKeyRepositoryMockRecorder is the mock recorder for MockKeyRepository.
+type MockKeyRepositoryMockRecorder struct {
+	mock *MockKeyRepository
+}
+
+// NewMockKeyRepository creates a new mock instance.
+func NewMockKeyRepository(ctrl *gomock.Controller) *MockKeyRepository {
+	mock := &MockKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyRepository) EXPECT() *MockKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// APIKeyByHash mocks base method.
+func (m *MockKeyRepository) APIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// APIKeyByHash indicates an expected call of APIKeyByHash.
+func (mr *MockKeyRepositoryMockRecorder) APIKeyByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIKeyByHash", reflect.TypeOf((*MockKeyRepository)(nil).APIKeyByHash), ctx, hash)
+}
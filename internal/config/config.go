@@ -3,10 +3,17 @@ package config
 import (
 	"flag"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// Secrets are not kept in config files, environment variables override them.
+const (
+	EnvAdminKey    = "AUTH_ADMIN_KEY"
+	EnvS3SecretKey = "S3_SECRET_KEY"
+)
+
 type Config struct {
 	Common struct {
 		Logger string `yaml:"logger"`
@@ -24,6 +31,62 @@ type Config struct {
 		Settings      string `yaml:"settings"`
 		MigrationPath string `yaml:"migrationPath"`
 	} `yaml:"database"`
+	Workers struct {
+		ExpiryInterval time.Duration `yaml:"expiryInterval"`
+		PoolSize       int           `yaml:"poolSize"`
+		QueueSize      int           `yaml:"queueSize"`
+	} `yaml:"workers"`
+	Storage struct {
+		Type string `yaml:"type"`
+		Root string `yaml:"root"`
+		S3   struct {
+			Endpoint  string        `yaml:"endpoint"`
+			Region    string        `yaml:"region"`
+			AccessKey string        `yaml:"accessKey"`
+			SecretKey string        `yaml:"secretKey"`
+			Bucket    string        `yaml:"bucket"`
+			UseSSL    bool          `yaml:"useSSL"`
+			Redirect  bool          `yaml:"redirect"`
+			URLExpiry time.Duration `yaml:"urlExpiry"`
+		} `yaml:"s3"`
+	} `yaml:"storage"`
+	Outbox struct {
+		Publisher string        `yaml:"publisher"`
+		Interval  time.Duration `yaml:"interval"`
+		BatchSize int           `yaml:"batchSize"`
+		Retention time.Duration `yaml:"retention"`
+		Kafka     struct {
+			Brokers []string `yaml:"brokers"`
+			Topic   string   `yaml:"topic"`
+		} `yaml:"kafka"`
+	} `yaml:"outbox"`
+	Webhooks struct {
+		Interval    time.Duration `yaml:"interval"`
+		Timeout     time.Duration `yaml:"timeout"`
+		BatchSize   int           `yaml:"batchSize"`
+		MaxAttempts int           `yaml:"maxAttempts"`
+		Backoff     time.Duration `yaml:"backoff"`
+		MaxBackoff  time.Duration `yaml:"maxBackoff"`
+		// AllowPrivate lets webhooks point to loopback, link-local and private addresses, for local development only.
+		AllowPrivate bool `yaml:"allowPrivate"`
+	} `yaml:"webhooks"`
+	Auth struct {
+		Enabled  bool   `yaml:"enabled"`
+		AdminKey string `yaml:"adminKey"`
+		JWT      struct {
+			JWKSFile   string              `yaml:"jwksFile"`
+			JWKSURL    string              `yaml:"jwksURL"`
+			Refresh    time.Duration       `yaml:"refresh"`
+			Issuer     string              `yaml:"issuer"`
+			Audience   string              `yaml:"audience"`
+			ScopeClaim string              `yaml:"scopeClaim"`
+			Roles      map[string][]string `yaml:"roles"`
+		} `yaml:"jwt"`
+	} `yaml:"auth"`
+	Idempotency struct {
+		TTL   time.Duration `yaml:"ttl"`
+		Lease time.Duration `yaml:"lease"`
+	} `yaml:"idempotency"`
 }
 
 func New() *Config {
@@ -48,5 +111,15 @@ func New() *Config {
 		}
 	}
 
+	fromEnv(&config.Auth.AdminKey, EnvAdminKey)
+	fromEnv(&config.Storage.S3.SecretKey, EnvS3SecretKey)
+
 	return &config
 }
+
+// fromEnv replaces value with environment variable if it is set.
+func fromEnv(value *string, key string) {
+	if env := os.Getenv(key); env != "" {
+		*value = env
+	}
+}
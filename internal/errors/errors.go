@@ -8,13 +8,70 @@ var (
 	ErrSegmentNotFound    = errors.New("segment not found")
 	ErrAlreadyDeleted     = errors.New("segment had been already deleted")
 	ErrNoSegmentsProvided = errors.New("no segments provided in request")
+	ErrInvalidPercentage  = errors.New("invalid segment percentage")
+	ErrNotDeleted         = errors.New("segment is not deleted")
+	ErrNothingToUpdate    = errors.New("nothing to update")
 
 	ErrInvalidUserID    = errors.New("invalid user id")
 	ErrUserNotFound     = errors.New("user not found")
 	ErrSegmentsNotFound = errors.New("segment(s) not found")
 	ErrAlreadyExpired   = errors.New("provided segment expired")
+	ErrConflictingSlugs = errors.New("segment(s) both added and removed")
+	ErrInvalidEntry     = errors.New("invalid entry")
+	ErrBatchTooLarge    = errors.New("too many entries in batch")
 
-	ErrDataNotFound   = errors.New("no data found")
-	ErrInvalidPeriod  = errors.New("provided invalid period")
-	ErrReportNotFound = errors.New("requested report not found")
+	ErrInvalidImportFile = errors.New("invalid import file")
+	ErrInvalidExpiry     = errors.New("invalid expiry")
+	ErrImportNotFound    = errors.New("import job not found")
+	ErrQueueFull         = errors.New("job queue is full")
+	ErrJobInterrupted    = errors.New("job interrupted and can not be resumed")
+
+	ErrInvalidFilter     = errors.New("invalid filter")
+	ErrInvalidPagination = errors.New("invalid pagination")
+	ErrInvalidFormat     = errors.New("unsupported format")
+	ErrNotAcceptable     = errors.New("none of accepted formats is supported")
+
+	ErrInvalidWebhook   = errors.New("invalid webhook")
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrDeliveryPending  = errors.New("webhook delivery is still pending")
+
+	ErrInvalidEventID = errors.New("invalid last event id")
+
+	ErrInvalidAPIKey  = errors.New("invalid api key name or scopes")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	ErrInvalidIdempotencyKey    = errors.New("invalid idempotency key")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was used with another request")
+	ErrIdempotencyKeyInProgress = errors.New("request with the idempotency key is in progress")
+
+	ErrDataNotFound      = errors.New("no data found")
+	ErrInvalidPeriod     = errors.New("provided invalid period")
+	ErrReportNotFound    = errors.New("requested report not found")
+	ErrReportJobNotFound = errors.New("report job not found")
 )
+
+// DetailedError is a domain error along with the slugs and user ids it was caused by.
+type DetailedError struct {
+	Err     error
+	Slugs   []string
+	UserIDs []string
+}
+
+func (e *DetailedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DetailedError) Unwrap() error {
+	return e.Err
+}
+
+// WithSlugs returns the error along with slugs it was caused by.
+func WithSlugs(err error, slugs ...string) error {
+	return &DetailedError{Err: err, Slugs: slugs}
+}
+
+// WithUserIDs returns the error along with user ids it was caused by.
+func WithUserIDs(err error, userIDs ...string) error {
+	return &DetailedError{Err: err, UserIDs: userIDs}
+}
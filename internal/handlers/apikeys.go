@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mailru/easyjson"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+func (h Handlers) APIKeyCreate(c echo.Context) error {
+	var req models.APIKeyRequest
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.logger.Error("Unable to read body", zap.Error(err))
+		return h.ErrorHandler(err)
+	}
+
+	err = easyjson.Unmarshal(body, &req)
+	if err != nil {
+		h.logger.Error("Unable to decode JSON", zap.Error(err))
+		return h.ErrorHandler(err)
+	}
+
+	resp, err := h.service.APIKeyCreate(c.Request().Context(), &req)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h Handlers) APIKeyList(c echo.Context) error {
+	resp, err := h.service.APIKeyList(c.Request().Context())
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h Handlers) APIKeyRevoke(c echo.Context) error {
+	if err := h.service.APIKeyRevoke(c.Request().Context(), c.Param("id")); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,136 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/handlers"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+const testKeyID = "0d7e6c2a-9b1f-4e57-8c3d-6a2b1f0e9d84"
+
+func TestHandlers_APIKeyCreate(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		body                 string
+		expectedReq          *models.APIKeyRequest
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+	}{
+		{
+			name:                 "Key created",
+			body:                 `{"name":"reporting","scopes":["reports:read"]}`,
+			expectedReq:          &models.APIKeyRequest{Name: "reporting", Scopes: []string{models.ScopeReportsRead}},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusCreated,
+		},
+		{
+			name:                 "Unknown scope",
+			body:                 `{"name":"reporting","scopes":["reports:write"]}`,
+			expectedReq:          &models.APIKeyRequest{Name: "reporting", Scopes: []string{"reports:write"}},
+			serviceError:         errors.ErrInvalidAPIKey,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:               "Invalid JSON",
+			body:               `{"name":`,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+
+			if tc.expectingServiceCall {
+				service.EXPECT().APIKeyCreate(context.Background(), tc.expectedReq).
+					Return(&models.APIKey{ID: testKeyID, Key: "seg_0123"}, tc.serviceError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/keys")
+
+			err := server.APIKeyCreate(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_APIKeyRevoke(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name               string
+		serviceError       error
+		expectedStatusCode int
+	}{
+		{
+			name:               "Key revoked",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Key not found",
+			serviceError:       errors.ErrAPIKeyNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Some internal error",
+			serviceError:       os.ErrInvalid,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			service.EXPECT().APIKeyRevoke(context.Background(), testKeyID).Return(tc.serviceError)
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/keys/:id")
+			c.SetParamNames("id")
+			c.SetParamValues(testKeyID)
+
+			err := server.APIKeyRevoke(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	errs "github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// AuditLog returns a page of audit log filtered by actor, slug, user and period [from, to).
+func (h Handlers) AuditLog(c echo.Context) error {
+	filter := models.AuditFilter{
+		Actor:  c.QueryParam("actor"),
+		Slug:   c.QueryParam("slug"),
+		UserID: c.QueryParam("userID"),
+	}
+
+	var err error
+
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		return h.ErrorHandler(errs.ErrInvalidPeriod)
+	}
+
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		return h.ErrorHandler(errs.ErrInvalidPeriod)
+	}
+
+	if filter.Limit, err = QueryInt(c, "limit", defaultPageLimit); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	if filter.Offset, err = QueryInt(c, "offset", 0); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	resp, err := h.service.AuditLog(c.Request().Context(), &filter)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
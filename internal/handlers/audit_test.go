@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/handlers"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+func TestHandlers_AuditLog(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		query                string
+		expectedFilter       *models.AuditFilter
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+	}{
+		{
+			name:  "Filtered page",
+			query: "?actor=staff-42&slug=AVITO_TEST&userID=d9b4e1c4-3b1f-4f1e-9d4b-6c3f0a2e7b1a&from=2023-08-01T00:00:00Z&to=2023-09-01T00:00:00Z&limit=10&offset=20",
+			expectedFilter: &models.AuditFilter{
+				Actor:  "staff-42",
+				Slug:   "AVITO_TEST",
+				UserID: "d9b4e1c4-3b1f-4f1e-9d4b-6c3f0a2e7b1a",
+				From:   time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
+				To:     time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+				Limit:  10,
+				Offset: 20,
+			},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:                 "Default page",
+			expectedFilter:       &models.AuditFilter{Limit: 50},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:               "Invalid time",
+			query:              "?from=yesterday",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Invalid limit",
+			query:              "?limit=ten",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:                 "Invalid user id",
+			query:                "?userID=user",
+			expectedFilter:       &models.AuditFilter{UserID: "user", Limit: 50},
+			serviceError:         errors.ErrInvalidUserID,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:                 "Some internal error",
+			expectedFilter:       &models.AuditFilter{Limit: 50},
+			serviceError:         os.ErrInvalid,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+
+			if tc.expectingServiceCall {
+				service.EXPECT().AuditLog(context.Background(), tc.expectedFilter).
+					Return(&models.AuditLogResponse{Entries: []models.AuditEntry{}}, tc.serviceError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/audit")
+
+			err := server.AuditLog(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mailru/easyjson"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+const (
+	mimeEventStream = "text/event-stream"
+
+	// eventHeartbeat is the longest silence on event stream, so proxies do not close idle connection.
+	eventHeartbeat = 15 * time.Second
+	// eventRetry is the reconnection delay suggested to clients, in milliseconds.
+	eventRetry = 3000
+)
+
+// EventStream sends membership and segment catalog changes as server-sent events.
+// Events may be filtered by slugs and user ids, stream is resumed after Last-Event-ID header
+// or lastEventID parameter for clients which can not set headers.
+func (h Handlers) EventStream(c echo.Context) error {
+	params := c.QueryParams()
+
+	filter := &models.EventFilter{
+		Slugs:   queryList(params["slugs"]),
+		UserIDs: queryList(params["userIDs"]),
+	}
+
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("lastEventID")
+	}
+
+	var (
+		resp      = c.Response()
+		started   bool
+		lastWrite time.Time
+	)
+
+	// Headers are committed on the first poll, so validation errors still get proper status code.
+	send := func(events []models.StreamEvent) error {
+		if !started {
+			started = true
+
+			resp.Header().Set(echo.HeaderContentType, mimeEventStream)
+			resp.Header().Set(echo.HeaderCacheControl, "no-cache")
+			resp.Header().Set("X-Accel-Buffering", "no")
+			resp.WriteHeader(http.StatusOK)
+
+			if _, err := fmt.Fprintf(resp, "retry: %d\n\n", eventRetry); err != nil {
+				return err
+			}
+		} else if len(events) == 0 {
+			if time.Since(lastWrite) < eventHeartbeat {
+				return nil
+			}
+
+			if _, err := resp.Write([]byte(": ping\n\n")); err != nil {
+				return err
+			}
+		}
+
+		for _, event := range events {
+			if _, err := fmt.Fprintf(resp, "id: %s\nevent: %s\ndata: ", event.ID, event.Event.Kind); err != nil {
+				return err
+			}
+
+			if _, err := easyjson.MarshalToWriter(event.Event, resp); err != nil {
+				return err
+			}
+
+			if _, err := resp.Write([]byte("\n\n")); err != nil {
+				return err
+			}
+		}
+
+		resp.Flush()
+		lastWrite = time.Now()
+
+		return nil
+	}
+
+	err := h.service.StreamEvents(c.Request().Context(), filter, lastEventID, send)
+	if err != nil {
+		if !started {
+			return h.ErrorHandler(err)
+		}
+
+		// Client is reconnected with Last-Event-ID, so cutting the stream loses nothing.
+		h.logger.Error("Event stream interrupted", zap.Error(err))
+	}
+
+	return nil
+}
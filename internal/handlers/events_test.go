@@ -0,0 +1,138 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/handlers"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+func TestHandlers_EventStream(t *testing.T) {
+	a := assert.New(t)
+
+	event := models.StreamEvent{
+		ID: "101-7",
+		Event: models.OutboxEvent{
+			ID:     7,
+			Kind:   models.EventMembership,
+			UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			Slug:   "AVITO_TEST",
+			Method: models.MethodAdded,
+		},
+	}
+
+	testCases := []struct {
+		name                string
+		query               string
+		lastEventID         string
+		expectedFilter      *models.EventFilter
+		expectedLastEventID string
+		serviceEvents       []models.StreamEvent
+		serviceError        error
+		expectedStatusCode  int
+		expectedBody        string
+	}{
+		{
+			name:               "Events streamed",
+			query:              "slugs=AVITO_TEST,AVITO_VOICE",
+			expectedFilter:     &models.EventFilter{Slugs: []string{"AVITO_TEST", "AVITO_VOICE"}},
+			serviceEvents:      []models.StreamEvent{event},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: "retry: 3000\n\n" +
+				"id: 101-7\nevent: membership\n" +
+				`data: {"id":7,"kind":"membership","userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002",` +
+				`"slug":"AVITO_TEST","method":"added","timestamp":"0001-01-01T00:00:00Z"}` + "\n\n",
+		},
+		{
+			name:                "Resumed with header",
+			query:               "userIDs=80b0b88d-379e-11ee-8bf7-0242c0a80002&lastEventID=100-1",
+			lastEventID:         "101-6",
+			expectedFilter:      &models.EventFilter{UserIDs: []string{"80b0b88d-379e-11ee-8bf7-0242c0a80002"}},
+			expectedLastEventID: "101-6",
+			expectedStatusCode:  http.StatusOK,
+			expectedBody:        "retry: 3000\n\n",
+		},
+		{
+			name:                "Resumed with parameter",
+			query:               "lastEventID=100-1",
+			expectedFilter:      &models.EventFilter{},
+			expectedLastEventID: "100-1",
+			expectedStatusCode:  http.StatusOK,
+			expectedBody:        "retry: 3000\n\n",
+		},
+		{
+			name:                "Invalid last event id",
+			lastEventID:         "last",
+			expectedFilter:      &models.EventFilter{},
+			expectedLastEventID: "last",
+			serviceError:        errors.ErrInvalidEventID,
+			expectedStatusCode:  http.StatusBadRequest,
+		},
+		{
+			name:               "Invalid user id",
+			query:              "userIDs=123",
+			expectedFilter:     &models.EventFilter{UserIDs: []string{"123"}},
+			serviceError:       errors.ErrInvalidUserID,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Stream interrupted",
+			expectedFilter:     &models.EventFilter{},
+			serviceEvents:      []models.StreamEvent{},
+			serviceError:       os.ErrInvalid,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "retry: 3000\n\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			service.EXPECT().StreamEvents(context.Background(), tc.expectedFilter, tc.expectedLastEventID, gomock.Any()).
+				DoAndReturn(func(ctx context.Context, filter *models.EventFilter, lastEventID string, fn func([]models.StreamEvent) error) error {
+					if tc.serviceError == nil || tc.serviceEvents != nil {
+						a.NoError(fn(tc.serviceEvents))
+					}
+
+					return tc.serviceError
+				})
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+			if tc.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tc.lastEventID)
+			}
+
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/events/stream")
+
+			err := server.EventStream(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+
+			if tc.expectedStatusCode == http.StatusOK {
+				a.Equal("text/event-stream", rec.Header().Get(echo.HeaderContentType))
+				a.Equal(tc.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -2,14 +2,17 @@ package handlers
 
 import (
 	"context"
-	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 
 	errs "github.com/dupreehkuda/avito-segments/internal/errors"
 	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/problem"
 )
 
 //go:generate mockgen -source=handlers.go -destination=mock_test.go -package=handlers_test
@@ -18,13 +21,53 @@ import (
 type Service interface {
 	SegmentAdd(ctx context.Context, segment *models.Segment) error
 	SegmentDelete(ctx context.Context, slug string) error
-	CreateReport(ctx context.Context, year, month int) (string, error)
+	SegmentUpdate(ctx context.Context, slug string, req *models.SegmentUpdateRequest) error
+	SegmentRestore(ctx context.Context, slug string, restoreMembers bool) error
+	SegmentList(ctx context.Context, filter *models.SegmentFilter) (*models.SegmentListResponse, error)
+	SegmentStats(ctx context.Context, slug string, from, to time.Time) (*models.SegmentStats, error)
+	SegmentRanking(ctx context.Context, limit, offset int) (*models.SegmentListResponse, error)
+	SegmentGet(ctx context.Context, slug string) (*models.SegmentInfo, error)
+	CreateReport(ctx context.Context, filter *models.ReportFilter, format string, force bool) (*models.ReportJob, error)
+	StreamReport(ctx context.Context, filter *models.ReportFilter, format string, w io.Writer) error
+	ReportJob(ctx context.Context, id string) (*models.ReportJob, error)
+	ReportFile(ctx context.Context, name string) (*models.ReportFile, error)
 
 	UserSetSegments(ctx context.Context, segments *models.UserSetRequest) error
 	UserDeleteSegments(ctx context.Context, segments *models.UserDeleteRequest) error
+	UserUpdateSegments(ctx context.Context, req *models.UserUpdateRequest) error
+	UserBulkSetSegments(ctx context.Context, reqs []models.UserSetRequest) ([]error, error)
+	SegmentMembers(ctx context.Context, slug, cursor string, limit int) (*models.SegmentMembersResponse, error)
+	SegmentExport(ctx context.Context, slug string, fn func(member models.SegmentMember) error) error
+	SegmentImport(ctx context.Context, slug string, file io.Reader) (*models.ImportJob, error)
+	SegmentImportGet(ctx context.Context, slug, id string, limit, offset int) (*models.ImportJob, error)
 	UserGetSegments(ctx context.Context, userID string) (*models.UserResponse, error)
+	UserGetSegmentsAt(ctx context.Context, userID string, at time.Time) (*models.UserResponse, error)
+
+	WebhookCreate(ctx context.Context, req *models.WebhookRequest) (*models.Webhook, error)
+	WebhookList(ctx context.Context) (*models.WebhookListResponse, error)
+	WebhookGet(ctx context.Context, id string) (*models.Webhook, error)
+	WebhookDelete(ctx context.Context, id string) error
+	WebhookDeliveries(ctx context.Context, id string, filter *models.DeliveryFilter) (*models.WebhookDeliveriesResponse, error)
+	WebhookRedeliver(ctx context.Context, id string, deliveryID int64) error
+
+	StreamEvents(ctx context.Context, filter *models.EventFilter, lastEventID string, fn func(events []models.StreamEvent) error) error
+
+	APIKeyCreate(ctx context.Context, req *models.APIKeyRequest) (*models.APIKey, error)
+	APIKeyList(ctx context.Context) (*models.APIKeyListResponse, error)
+	APIKeyRevoke(ctx context.Context, id string) error
+
+	AuditLog(ctx context.Context, filter *models.AuditFilter) (*models.AuditLogResponse, error)
 }
 
+const (
+	defaultPageLimit = 50
+	maxNDJSONLine    = 1 << 20
+	exportFlushEvery = 1000
+
+	mimeNDJSON  = "application/ndjson"
+	mimeXNDJSON = "application/x-ndjson"
+)
+
 // Handlers provide access to service.
 type Handlers struct {
 	service Service
@@ -39,32 +82,28 @@ func New(service Service, logger *zap.Logger) *Handlers {
 	}
 }
 
+// ErrorHandler maps domain error to http error carrying its problem with stable code.
 func (h Handlers) ErrorHandler(err error) *echo.HTTPError {
-	switch {
-	case errors.Is(err, errs.ErrInvalidPeriod):
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid time period provided")
-	case errors.Is(err, errs.ErrDataNotFound):
-		return echo.NewHTTPError(http.StatusNotFound, "no data for report")
-	case errors.Is(err, errs.ErrInvalidSegmentSlug):
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid slug naming")
-	case errors.Is(err, errs.ErrInvalidUserID):
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid userID")
-	case errors.Is(err, errs.ErrNoSegmentsProvided):
-		return echo.NewHTTPError(http.StatusBadRequest, "no segments provided")
-	case errors.Is(err, errs.ErrReportNotFound):
-		return echo.NewHTTPError(http.StatusNotFound, "requested report not found")
-	case errors.Is(err, errs.ErrSegmentsNotFound):
-		return echo.NewHTTPError(http.StatusBadRequest, "segment(s) not found")
-	case errors.Is(err, errs.ErrAlreadyExpired):
-		return echo.NewHTTPError(http.StatusBadRequest, "segment operation expired")
-	case errors.Is(err, errs.ErrUserNotFound):
-		return echo.NewHTTPError(http.StatusNotFound, "user not found")
-	case errors.Is(err, errs.ErrSegmentNotFound):
-		return echo.NewHTTPError(http.StatusNotFound, "slug not found")
-	case errors.Is(err, errs.ErrAlreadyDeleted):
-		return echo.NewHTTPError(http.StatusGone, "slug has been already deleted")
-	default:
-		h.logger.Error("Error occurred creating report", zap.Error(err))
-		return echo.NewHTTPError(http.StatusInternalServerError, "internal server error")
+	if he := problem.Of(err); he != nil {
+		return he
 	}
+
+	h.logger.Error("Error occurred creating report", zap.Error(err))
+
+	return problem.New(http.StatusInternalServerError, "internal_server_error", "internal server error")
+}
+
+// QueryInt parses integer query parameter, falling back to def if it is not set.
+func QueryInt(c echo.Context, name string, def int) (int, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return def, nil
+	}
+
+	res, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errs.ErrInvalidPagination
+	}
+
+	return res, nil
 }
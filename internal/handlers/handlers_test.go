@@ -0,0 +1,71 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/handlers"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+func TestHandlers_ErrorHandler(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+		expectedCode       string
+		expectedDetails    *models.ProblemDetails
+	}{
+		{
+			name:               "Missing segments",
+			err:                errors.WithSlugs(errors.ErrSegmentsNotFound, "AVITO_TEST", "AVITO_VOICE"),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       "segments_not_found",
+			expectedDetails:    &models.ProblemDetails{Slugs: []string{"AVITO_TEST", "AVITO_VOICE"}},
+		},
+		{
+			name:               "Invalid user id",
+			err:                fmt.Errorf("check: %w", errors.WithUserIDs(errors.ErrInvalidUserID, "user")),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedCode:       "invalid_user_id",
+			expectedDetails:    &models.ProblemDetails{UserIDs: []string{"user"}},
+		},
+		{
+			name:               "Segment not found",
+			err:                errors.ErrSegmentNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedCode:       "segment_not_found",
+		},
+		{
+			name:               "Some internal error",
+			err:                os.ErrInvalid,
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedCode:       "internal_server_error",
+		},
+	}
+
+	zp, _ := zap.NewDevelopment()
+	server := handlers.New(nil, zp)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			he := server.ErrorHandler(tc.err)
+
+			problem, ok := he.Message.(*models.Problem)
+			a.True(ok)
+
+			a.Equal(tc.expectedStatusCode, he.Code)
+			a.Equal(tc.expectedStatusCode, problem.Status)
+			a.Equal(tc.expectedCode, problem.Code)
+			a.Equal(tc.expectedDetails, problem.Details)
+		})
+	}
+}
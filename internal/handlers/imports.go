@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	errs "github.com/dupreehkuda/avito-segments/internal/errors"
+)
+
+// importFileField is the multipart field holding CSV with user ids.
+const importFileField = "file"
+
+// SegmentImport streams uploaded file to the service without parsing or buffering it,
+// rows are read and checked by the import job.
+func (h Handlers) SegmentImport(c echo.Context) error {
+	file, err := importFile(c.Request())
+	if err != nil {
+		h.logger.Error("Unable to get import file", zap.Error(err))
+		return h.ErrorHandler(errs.ErrInvalidImportFile)
+	}
+
+	job, err := h.service.SegmentImport(c.Request().Context(), c.Param("slug"), file)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusAccepted, job)
+}
+
+func (h Handlers) SegmentImportGet(c echo.Context) error {
+	limit, err := QueryInt(c, "limit", defaultPageLimit)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	offset, err := QueryInt(c, "offset", 0)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	job, err := h.service.SegmentImportGet(c.Request().Context(), c.Param("slug"), c.Param("id"), limit, offset)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusOK, job)
+}
+
+// importFile returns contents of the import file field read straight from request body.
+func importFile(r *http.Request) (io.Reader, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, err
+		}
+
+		if part.FormName() == importFileField {
+			return part, nil
+		}
+	}
+}
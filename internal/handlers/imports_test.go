@@ -0,0 +1,205 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/handlers"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+func TestHandlers_SegmentImport(t *testing.T) {
+	a := assert.New(t)
+
+	const file = "user_id,expire\n" +
+		"80b0b88d-379e-11ee-8bf7-0242c0a80002\n" +
+		"80b0b88d-379e-11ee-8bf7-0242c0a80003,2099-08-26T19:00:00Z\n"
+
+	testCases := []struct {
+		name                 string
+		withoutFile          bool
+		notMultipart         bool
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+	}{
+		{
+			name:                 "Import queued",
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusAccepted,
+		},
+		{
+			name:               "No file",
+			withoutFile:        true,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Not a multipart request",
+			notMultipart:       true,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:                 "Empty file",
+			serviceError:         errors.ErrInvalidImportFile,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:                 "Segment not found",
+			serviceError:         errors.ErrSegmentNotFound,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusNotFound,
+		},
+		{
+			name:                 "Queue is full",
+			serviceError:         errors.ErrQueueFull,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusServiceUnavailable,
+		},
+		{
+			name:                 "Some internal error",
+			serviceError:         os.ErrInvalid,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			if tc.expectingServiceCall {
+				var job *models.ImportJob
+				if tc.serviceError == nil {
+					job = &models.ImportJob{Slug: "TEST_SLUG", Status: models.JobQueued}
+				}
+
+				service.EXPECT().SegmentImport(context.Background(), "TEST_SLUG", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, r io.Reader) (*models.ImportJob, error) {
+						// File is passed as is, parsing it is left to the import job.
+						data, err := io.ReadAll(r)
+						a.NoError(err)
+						a.Equal(file, string(data))
+
+						return job, tc.serviceError
+					})
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+
+			_ = writer.WriteField("comment", "fields before the file are skipped")
+
+			if !tc.withoutFile {
+				part, _ := writer.CreateFormFile("file", "import.csv")
+				_, _ = part.Write([]byte(file))
+			}
+
+			_ = writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/", body)
+			req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+
+			if tc.notMultipart {
+				req.Header.Set(echo.HeaderContentType, "text/csv")
+			}
+
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/segment/:slug/import")
+			c.SetParamNames("slug")
+			c.SetParamValues("TEST_SLUG")
+
+			err := server.SegmentImport(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_SegmentImportGet(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		query                string
+		serviceReturn        *models.ImportJob
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+	}{
+		{
+			name:                 "Job found",
+			serviceReturn:        &models.ImportJob{Slug: "TEST_SLUG", Status: models.JobRunning},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:               "Invalid pagination",
+			query:              "?limit=abc",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:                 "Job not found",
+			serviceError:         errors.ErrImportNotFound,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusNotFound,
+		},
+		{
+			name:                 "Some internal error",
+			serviceError:         os.ErrInvalid,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			if tc.expectingServiceCall {
+				service.EXPECT().SegmentImportGet(context.Background(), "TEST_SLUG", "80b0b88d-379e-11ee-8bf7-0242c0a80002", 50, 0).
+					Return(tc.serviceReturn, tc.serviceError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/segment/:slug/import/:id")
+			c.SetParamNames("slug", "id")
+			c.SetParamValues("TEST_SLUG", "80b0b88d-379e-11ee-8bf7-0242c0a80002")
+
+			err := server.SegmentImportGet(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
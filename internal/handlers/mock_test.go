@@ -6,7 +6,9 @@ package handlers_test
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/dupreehkuda/avito-segments/internal/models"
 	gomock "go.uber.org/mock/gomock"
@@ -35,19 +37,108 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// APIKeyCreate mocks base method.
+func (m *MockService) APIKeyCreate(ctx context.Context, req *models.APIKeyRequest) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIKeyCreate", ctx, req)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// APIKeyCreate indicates an expected call of APIKeyCreate.
+func (mr *MockServiceMockRecorder) APIKeyCreate(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIKeyCreate", reflect.TypeOf((*MockService)(nil).APIKeyCreate), ctx, req)
+}
+
+// APIKeyList mocks base method.
+func (m *MockService) APIKeyList(ctx context.Context) (*models.APIKeyListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIKeyList", ctx)
+	ret0, _ := ret[0].(*models.APIKeyListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// APIKeyList indicates an expected call of APIKeyList.
+func (mr *MockServiceMockRecorder) APIKeyList(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIKeyList", reflect.TypeOf((*MockService)(nil).APIKeyList), ctx)
+}
+
+// APIKeyRevoke mocks base method.
+func (m *MockService) APIKeyRevoke(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "APIKeyRevoke", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// APIKeyRevoke indicates an expected call of APIKeyRevoke.
+func (mr *MockServiceMockRecorder) APIKeyRevoke(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "APIKeyRevoke", reflect.TypeOf((*MockService)(nil).APIKeyRevoke), ctx, id)
+}
+
+// AuditLog mocks base method.
+func (m *MockService) AuditLog(ctx context.Context, filter *models.AuditFilter) (*models.AuditLogResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditLog", ctx, filter)
+	ret0, _ := ret[0].(*models.AuditLogResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuditLog indicates an expected call of AuditLog.
+func (mr *MockServiceMockRecorder) AuditLog(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditLog", reflect.TypeOf((*MockService)(nil).AuditLog), ctx, filter)
+}
+
 // CreateReport mocks base method.
-func (m *MockService) CreateReport(ctx context.Context, year, month int) (string, error) {
+func (m *MockService) CreateReport(ctx context.Context, filter *models.ReportFilter, format string, force bool) (*models.ReportJob, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateReport", ctx, year, month)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "CreateReport", ctx, filter, format, force)
+	ret0, _ := ret[0].(*models.ReportJob)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateReport indicates an expected call of CreateReport.
-func (mr *MockServiceMockRecorder) CreateReport(ctx, year, month interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) CreateReport(ctx, filter, format, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReport", reflect.TypeOf((*MockService)(nil).CreateReport), ctx, filter, format, force)
+}
+
+// ReportFile mocks base method.
+func (m *MockService) ReportFile(ctx context.Context, name string) (*models.ReportFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportFile", ctx, name)
+	ret0, _ := ret[0].(*models.ReportFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportFile indicates an expected call of ReportFile.
+func (mr *MockServiceMockRecorder) ReportFile(ctx, name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReport", reflect.TypeOf((*MockService)(nil).CreateReport), ctx, year, month)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportFile", reflect.TypeOf((*MockService)(nil).ReportFile), ctx, name)
+}
+
+// ReportJob mocks base method.
+func (m *MockService) ReportJob(ctx context.Context, id string) (*models.ReportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportJob", ctx, id)
+	ret0, _ := ret[0].(*models.ReportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportJob indicates an expected call of ReportJob.
+func (mr *MockServiceMockRecorder) ReportJob(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportJob", reflect.TypeOf((*MockService)(nil).ReportJob), ctx, id)
 }
 
 // SegmentAdd mocks base method.
@@ -78,6 +169,196 @@ func (mr *MockServiceMockRecorder) SegmentDelete(ctx, slug interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentDelete", reflect.TypeOf((*MockService)(nil).SegmentDelete), ctx, slug)
 }
 
+// SegmentExport mocks base method.
+func (m *MockService) SegmentExport(ctx context.Context, slug string, fn func(models.SegmentMember) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SegmentExport", ctx, slug, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SegmentExport indicates an expected call of SegmentExport.
+func (mr *MockServiceMockRecorder) SegmentExport(ctx, slug, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentExport", reflect.TypeOf((*MockService)(nil).SegmentExport), ctx, slug, fn)
+}
+
+// SegmentGet mocks base method.
+func (m *MockService) SegmentGet(ctx context.Context, slug string) (*models.SegmentInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SegmentGet", ctx, slug)
+	ret0, _ := ret[0].(*models.SegmentInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SegmentGet indicates an expected call of SegmentGet.
+func (mr *MockServiceMockRecorder) SegmentGet(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentGet", reflect.TypeOf((*MockService)(nil).SegmentGet), ctx, slug)
+}
+
+// SegmentImport mocks base method.
+func (m *MockService) SegmentImport(ctx context.Context, slug string, file io.Reader) (*models.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SegmentImport", ctx, slug, file)
+	ret0, _ := ret[0].(*models.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SegmentImport indicates an expected call of SegmentImport.
+func (mr *MockServiceMockRecorder) SegmentImport(ctx, slug, file interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentImport", reflect.TypeOf((*MockService)(nil).SegmentImport), ctx, slug, file)
+}
+
+// SegmentImportGet mocks base method.
+func (m *MockService) SegmentImportGet(ctx context.Context, slug, id string, limit, offset int) (*models.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SegmentImportGet", ctx, slug, id, limit, offset)
+	ret0, _ := ret[0].(*models.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SegmentImportGet indicates an expected call of SegmentImportGet.
+func (mr *MockServiceMockRecorder) SegmentImportGet(ctx, slug, id, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentImportGet", reflect.TypeOf((*MockService)(nil).SegmentImportGet), ctx, slug, id, limit, offset)
+}
+
+// SegmentList mocks base method.
+func (m *MockService) SegmentList(ctx context.Context, filter *models.SegmentFilter) (*models.SegmentListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SegmentList", ctx, filter)
+	ret0, _ := ret[0].(*models.SegmentListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SegmentList indicates an expected call of SegmentList.
+func (mr *MockServiceMockRecorder) SegmentList(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentList", reflect.TypeOf((*MockService)(nil).SegmentList), ctx, filter)
+}
+
+// SegmentMembers mocks base method.
+func (m *MockService) SegmentMembers(ctx context.Context, slug, cursor string, limit int) (*models.SegmentMembersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SegmentMembers", ctx, slug, cursor, limit)
+	ret0, _ := ret[0].(*models.SegmentMembersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SegmentMembers indicates an expected call of SegmentMembers.
+func (mr *MockServiceMockRecorder) SegmentMembers(ctx, slug, cursor, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentMembers", reflect.TypeOf((*MockService)(nil).SegmentMembers), ctx, slug, cursor, limit)
+}
+
+// SegmentRanking mocks base method.
+func (m *MockService) SegmentRanking(ctx context.Context, limit, offset int) (*models.SegmentListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SegmentRanking", ctx, limit, offset)
+	ret0, _ := ret[0].(*models.SegmentListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SegmentRanking indicates an expected call of SegmentRanking.
+func (mr *MockServiceMockRecorder) SegmentRanking(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentRanking", reflect.TypeOf((*MockService)(nil).SegmentRanking), ctx, limit, offset)
+}
+
+// SegmentRestore mocks base method.
+func (m *MockService) SegmentRestore(ctx context.Context, slug string, restoreMembers bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SegmentRestore", ctx, slug, restoreMembers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SegmentRestore indicates an expected call of SegmentRestore.
+func (mr *MockServiceMockRecorder) SegmentRestore(ctx, slug, restoreMembers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentRestore", reflect.TypeOf((*MockService)(nil).SegmentRestore), ctx, slug, restoreMembers)
+}
+
+// SegmentStats mocks base method.
+func (m *MockService) SegmentStats(ctx context.Context, slug string, from, to time.Time) (*models.SegmentStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SegmentStats", ctx, slug, from, to)
+	ret0, _ := ret[0].(*models.SegmentStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SegmentStats indicates an expected call of SegmentStats.
+func (mr *MockServiceMockRecorder) SegmentStats(ctx, slug, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentStats", reflect.TypeOf((*MockService)(nil).SegmentStats), ctx, slug, from, to)
+}
+
+// SegmentUpdate mocks base method.
+func (m *MockService) SegmentUpdate(ctx context.Context, slug string, req *models.SegmentUpdateRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SegmentUpdate", ctx, slug, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SegmentUpdate indicates an expected call of SegmentUpdate.
+func (mr *MockServiceMockRecorder) SegmentUpdate(ctx, slug, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SegmentUpdate", reflect.TypeOf((*MockService)(nil).SegmentUpdate), ctx, slug, req)
+}
+
+// StreamEvents mocks base method.
+func (m *MockService) StreamEvents(ctx context.Context, filter *models.EventFilter, lastEventID string, fn func([]models.StreamEvent) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamEvents", ctx, filter, lastEventID, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamEvents indicates an expected call of StreamEvents.
+func (mr *MockServiceMockRecorder) StreamEvents(ctx, filter, lastEventID, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamEvents", reflect.TypeOf((*MockService)(nil).StreamEvents), ctx, filter, lastEventID, fn)
+}
+
+// StreamReport mocks base method.
+func (m *MockService) StreamReport(ctx context.Context, filter *models.ReportFilter, format string, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamReport", ctx, filter, format, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamReport indicates an expected call of StreamReport.
+func (mr *MockServiceMockRecorder) StreamReport(ctx, filter, format, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamReport", reflect.TypeOf((*MockService)(nil).StreamReport), ctx, filter, format, w)
+}
+
+// UserBulkSetSegments mocks base method.
+func (m *MockService) UserBulkSetSegments(ctx context.Context, reqs []models.UserSetRequest) ([]error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserBulkSetSegments", ctx, reqs)
+	ret0, _ := ret[0].([]error)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserBulkSetSegments indicates an expected call of UserBulkSetSegments.
+func (mr *MockServiceMockRecorder) UserBulkSetSegments(ctx, reqs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserBulkSetSegments", reflect.TypeOf((*MockService)(nil).UserBulkSetSegments), ctx, reqs)
+}
+
 // UserDeleteSegments mocks base method.
 func (m *MockService) UserDeleteSegments(ctx context.Context, segments *models.UserDeleteRequest) error {
 	m.ctrl.T.Helper()
@@ -107,6 +388,21 @@ func (mr *MockServiceMockRecorder) UserGetSegments(ctx, userID interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserGetSegments", reflect.TypeOf((*MockService)(nil).UserGetSegments), ctx, userID)
 }
 
+// UserGetSegmentsAt mocks base method.
+func (m *MockService) UserGetSegmentsAt(ctx context.Context, userID string, at time.Time) (*models.UserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserGetSegmentsAt", ctx, userID, at)
+	ret0, _ := ret[0].(*models.UserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserGetSegmentsAt indicates an expected call of UserGetSegmentsAt.
+func (mr *MockServiceMockRecorder) UserGetSegmentsAt(ctx, userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserGetSegmentsAt", reflect.TypeOf((*MockService)(nil).UserGetSegmentsAt), ctx, userID, at)
+}
+
 // UserSetSegments mocks base method.
 func (m *MockService) UserSetSegments(ctx context.Context, segments *models.UserSetRequest) error {
 	m.ctrl.T.Helper()
@@ -120,3 +416,105 @@ func (mr *MockServiceMockRecorder) UserSetSegments(ctx, segments interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserSetSegments", reflect.TypeOf((*MockService)(nil).UserSetSegments), ctx, segments)
 }
+
+// UserUpdateSegments mocks base method.
+func (m *MockService) UserUpdateSegments(ctx context.Context, req *models.UserUpdateRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserUpdateSegments", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UserUpdateSegments indicates an expected call of UserUpdateSegments.
+func (mr *MockServiceMockRecorder) UserUpdateSegments(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserUpdateSegments", reflect.TypeOf((*MockService)(nil).UserUpdateSegments), ctx, req)
+}
+
+// WebhookCreate mocks base method.
+func (m *MockService) WebhookCreate(ctx context.Context, req *models.WebhookRequest) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookCreate", ctx, req)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WebhookCreate indicates an expected call of WebhookCreate.
+func (mr *MockServiceMockRecorder) WebhookCreate(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookCreate", reflect.TypeOf((*MockService)(nil).WebhookCreate), ctx, req)
+}
+
+// WebhookDelete mocks base method.
+func (m *MockService) WebhookDelete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookDelete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WebhookDelete indicates an expected call of WebhookDelete.
+func (mr *MockServiceMockRecorder) WebhookDelete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookDelete", reflect.TypeOf((*MockService)(nil).WebhookDelete), ctx, id)
+}
+
+// WebhookDeliveries mocks base method.
+func (m *MockService) WebhookDeliveries(ctx context.Context, id string, filter *models.DeliveryFilter) (*models.WebhookDeliveriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookDeliveries", ctx, id, filter)
+	ret0, _ := ret[0].(*models.WebhookDeliveriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WebhookDeliveries indicates an expected call of WebhookDeliveries.
+func (mr *MockServiceMockRecorder) WebhookDeliveries(ctx, id, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookDeliveries", reflect.TypeOf((*MockService)(nil).WebhookDeliveries), ctx, id, filter)
+}
+
+// WebhookGet mocks base method.
+func (m *MockService) WebhookGet(ctx context.Context, id string) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookGet", ctx, id)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WebhookGet indicates an expected call of WebhookGet.
+func (mr *MockServiceMockRecorder) WebhookGet(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookGet", reflect.TypeOf((*MockService)(nil).WebhookGet), ctx, id)
+}
+
+// WebhookList mocks base method.
+func (m *MockService) WebhookList(ctx context.Context) (*models.WebhookListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookList", ctx)
+	ret0, _ := ret[0].(*models.WebhookListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WebhookList indicates an expected call of WebhookList.
+func (mr *MockServiceMockRecorder) WebhookList(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookList", reflect.TypeOf((*MockService)(nil).WebhookList), ctx)
+}
+
+// WebhookRedeliver mocks base method.
+func (m *MockService) WebhookRedeliver(ctx context.Context, id string, deliveryID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookRedeliver", ctx, id, deliveryID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WebhookRedeliver indicates an expected call of WebhookRedeliver.
+func (mr *MockServiceMockRecorder) WebhookRedeliver(ctx, id, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookRedeliver", reflect.TypeOf((*MockService)(nil).WebhookRedeliver), ctx, id, deliveryID)
+}
@@ -1,11 +1,13 @@
 package handlers
 
 import (
-	"errors"
-	"fmt"
 	"io"
+	"mime"
 	"net/http"
-	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/mailru/easyjson"
@@ -13,6 +15,7 @@ import (
 
 	errs "github.com/dupreehkuda/avito-segments/internal/errors"
 	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/report"
 )
 
 func (h Handlers) ReportCreate(c echo.Context) error {
@@ -30,30 +33,223 @@ func (h Handlers) ReportCreate(c echo.Context) error {
 		return h.ErrorHandler(err)
 	}
 
-	potentialFileName := fmt.Sprintf("%v_%v_report.csv", req.Month, req.Year)
-	if _, err = os.Stat("reports/" + potentialFileName); !errors.Is(err, os.ErrNotExist) {
-		return c.JSON(http.StatusOK, models.ReportResponse{Link: c.Request().Host + "/api/v1/report/" + potentialFileName})
+	job, err := h.service.CreateReport(c.Request().Context(), &req.ReportFilter, req.Format, req.Force)
+	if err != nil {
+		return h.ErrorHandler(err)
 	}
 
-	fileName, err := h.service.CreateReport(c.Request().Context(), req.Year, req.Month)
+	if job.Status == models.JobDone {
+		return c.JSON(http.StatusOK, h.withReportLink(c, job))
+	}
+
+	return c.JSON(http.StatusAccepted, job)
+}
+
+func (h Handlers) ReportJobGet(c echo.Context) error {
+	job, err := h.service.ReportJob(c.Request().Context(), c.Param("id"))
 	if err != nil {
 		return h.ErrorHandler(err)
 	}
 
-	resp := models.ReportResponse{Link: c.Request().Host + "/api/v1/report/" + fileName}
+	return c.JSON(http.StatusOK, h.withReportLink(c, job))
+}
 
-	return c.JSON(http.StatusOK, resp)
+// withReportLink fills download link of finished report.
+func (h Handlers) withReportLink(c echo.Context, job *models.ReportJob) *models.ReportJob {
+	if job.Status == models.JobDone && job.File != "" {
+		job.Link = c.Request().Host + "/api/v1/report/" + job.File
+	}
+
+	return job
 }
 
+// ReportGet redirects to report storage if it provides direct links, otherwise streams the report itself.
+// Report requested without extension is served in the format negotiated by Accept header.
 func (h Handlers) ReportGet(c echo.Context) error {
-	file := c.Param("file")
+	name := c.Param("file")
+
+	if filepath.Ext(name) == "" {
+		format, err := acceptedReportFormat(c.Request().Header.Get(echo.HeaderAccept))
+		if err != nil {
+			return h.ErrorHandler(err)
+		}
+
+		name += "." + format
+	}
+
+	file, err := h.service.ReportFile(c.Request().Context(), name)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	if file.URL != "" {
+		return c.Redirect(http.StatusFound, file.URL)
+	}
+	defer file.Content.Close()
+
+	contentType := report.ContentType(strings.TrimPrefix(filepath.Ext(file.Name), "."))
+	if contentType == "" {
+		contentType = echo.MIMEOctetStream
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, "attachment;filename="+file.Name)
+
+	return c.Stream(http.StatusOK, contentType, file.Content)
+}
+
+// ReportStream generates report described by query parameters and streams it without storing.
+func (h Handlers) ReportStream(c echo.Context) error {
+	filter, err := reportQueryFilter(c)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
 
-	if _, err := os.Stat("reports/" + file); errors.Is(err, os.ErrNotExist) {
-		return h.ErrorHandler(errs.ErrReportNotFound)
+	format := c.QueryParam("format")
+	if format == "" {
+		if format, err = acceptedReportFormat(c.Request().Header.Get(echo.HeaderAccept)); err != nil {
+			return h.ErrorHandler(err)
+		}
 	}
 
-	c.Response().Header().Set("Content-Type", "text/csv")
-	c.Response().Header().Set("Content-Disposition", "attachment;filename="+file)
+	resp := c.Response()
+	out := &lazyWriter{w: resp, start: func() {
+		resp.Header().Set(echo.HeaderContentType, report.ContentType(format))
+		resp.Header().Set(echo.HeaderContentDisposition, "attachment;filename=report."+format)
+		resp.WriteHeader(http.StatusOK)
+	}}
+
+	err = h.service.StreamReport(c.Request().Context(), filter, format, out)
+	if err != nil {
+		if !out.started {
+			return h.ErrorHandler(err)
+		}
+
+		// Headers are already sent, so the only thing left is to cut the stream.
+		h.logger.Error("Report stream interrupted", zap.Error(err))
+
+		return nil
+	}
+
+	// Empty NDJSON report has no bytes at all.
+	if !out.started {
+		out.begin()
+	}
+
+	return nil
+}
+
+// reportQueryFilter reads report filter from query parameters.
+// List parameters may be repeated or hold comma separated values.
+func reportQueryFilter(c echo.Context) (*models.ReportFilter, error) {
+	var (
+		filter = &models.ReportFilter{}
+		err    error
+	)
+
+	if filter.Year, err = QueryInt(c, "year", 0); err != nil {
+		return nil, errs.ErrInvalidPeriod
+	}
+
+	if filter.Month, err = QueryInt(c, "month", 0); err != nil {
+		return nil, errs.ErrInvalidPeriod
+	}
+
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		return nil, errs.ErrInvalidPeriod
+	}
+
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		return nil, errs.ErrInvalidPeriod
+	}
+
+	params := c.QueryParams()
+	filter.Slugs = queryList(params["slugs"])
+	filter.UserIDs = queryList(params["userIDs"])
+	filter.Methods = queryList(params["methods"])
+
+	return filter, nil
+}
+
+func queryTime(c echo.Context, name string) (time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+func queryList(values []string) []string {
+	var list []string
+
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
+
+// lazyWriter commits response headers on first write, so errors found before any output still get proper status.
+type lazyWriter struct {
+	w       io.Writer
+	start   func()
+	started bool
+}
+
+func (l *lazyWriter) Write(p []byte) (int, error) {
+	if !l.started {
+		l.begin()
+	}
+
+	return l.w.Write(p)
+}
+
+func (l *lazyWriter) begin() {
+	l.started = true
+	l.start()
+}
+
+// acceptedReportFormat picks report format preferred by Accept header, CSV is the default.
+func acceptedReportFormat(accept string) (string, error) {
+	if strings.TrimSpace(accept) == "" {
+		return models.FormatCSV, nil
+	}
+
+	var (
+		format  string
+		quality = -1.0
+	)
+
+	for _, item := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(item))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+
+		candidate := report.FormatOf(mediaType)
+		if candidate == "" && (mediaType == "*/*" || mediaType == "text/*") {
+			candidate = models.FormatCSV
+		}
+
+		if candidate != "" && q > 0 && q > quality {
+			format, quality = candidate, q
+		}
+	}
+
+	if format == "" {
+		return "", errs.ErrNotAcceptable
+	}
 
-	return c.File("reports/" + file)
+	return format, nil
 }
@@ -0,0 +1,461 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mailru/easyjson"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/handlers"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+func TestHandlers_ReportCreate(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name               string
+		inputBody          string
+		serviceReturn      *models.ReportJob
+		serviceError       error
+		expectedStatusCode int
+		expectedLink       string
+	}{
+		{
+			name:               "Report queued",
+			inputBody:          `{"year":2023,"month":8}`,
+			serviceReturn:      &models.ReportJob{ReportFilter: models.ReportFilter{Year: 2023, Month: 8}, Status: models.JobQueued},
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "Report already generated",
+			inputBody:          `{"year":2023,"month":8}`,
+			serviceReturn:      &models.ReportJob{ReportFilter: models.ReportFilter{Year: 2023, Month: 8}, Status: models.JobDone, File: "8_2023_report.csv"},
+			expectedStatusCode: http.StatusOK,
+			expectedLink:       "example.com/api/v1/report/8_2023_report.csv",
+		},
+		{
+			name:               "XLSX report queued",
+			inputBody:          `{"year":2023,"month":8,"format":"xlsx"}`,
+			serviceReturn:      &models.ReportJob{ReportFilter: models.ReportFilter{Year: 2023, Month: 8}, Format: models.FormatXLSX, Status: models.JobQueued},
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "Unsupported format",
+			inputBody:          `{"year":2023,"month":8,"format":"pdf"}`,
+			serviceError:       errors.ErrInvalidFormat,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Filtered range report queued",
+			inputBody:          `{"from":"2023-08-01T00:00:00Z","to":"2023-08-15T00:00:00Z","slugs":["AVITO_TEST"],"methods":["added"]}`,
+			serviceReturn:      &models.ReportJob{Status: models.JobQueued},
+			expectedStatusCode: http.StatusAccepted,
+		},
+		{
+			name:               "Invalid period",
+			inputBody:          `{"year":2023,"month":13}`,
+			serviceError:       errors.ErrInvalidPeriod,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Queue is full",
+			inputBody:          `{"year":2023,"month":8}`,
+			serviceError:       errors.ErrQueueFull,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:               "Some internal error",
+			inputBody:          `{"year":2023,"month":8}`,
+			serviceError:       os.ErrInvalid,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var input models.ReportRequest
+			_ = easyjson.Unmarshal([]byte(tc.inputBody), &input)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			service.EXPECT().CreateReport(context.Background(), &input.ReportFilter, input.Format, input.Force).Return(tc.serviceReturn, tc.serviceError)
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(tc.inputBody)))
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/report")
+
+			err := server.ReportCreate(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+
+			if tc.serviceError == nil {
+				var resp models.ReportJob
+				a.NoError(easyjson.Unmarshal(rec.Body.Bytes(), &resp))
+				a.Equal(tc.expectedLink, resp.Link)
+			}
+		})
+	}
+}
+
+func TestHandlers_ReportJobGet(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name               string
+		serviceReturn      *models.ReportJob
+		serviceError       error
+		expectedStatusCode int
+		expectedLink       string
+	}{
+		{
+			name:               "Job running",
+			serviceReturn:      &models.ReportJob{Status: models.JobRunning},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Job done",
+			serviceReturn:      &models.ReportJob{Status: models.JobDone, Rows: 10, File: "8_2023_report.csv"},
+			expectedStatusCode: http.StatusOK,
+			expectedLink:       "example.com/api/v1/report/8_2023_report.csv",
+		},
+		{
+			name:               "Job not found",
+			serviceError:       errors.ErrReportJobNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Some internal error",
+			serviceError:       os.ErrInvalid,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			service.EXPECT().ReportJob(context.Background(), "80b0b88d-379e-11ee-8bf7-0242c0a80002").Return(tc.serviceReturn, tc.serviceError)
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/report/jobs/:id")
+			c.SetParamNames("id")
+			c.SetParamValues("80b0b88d-379e-11ee-8bf7-0242c0a80002")
+
+			err := server.ReportJobGet(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+
+			if tc.serviceError == nil {
+				var resp models.ReportJob
+				a.NoError(easyjson.Unmarshal(rec.Body.Bytes(), &resp))
+				a.Equal(tc.expectedLink, resp.Link)
+			}
+		})
+	}
+}
+
+func TestHandlers_ReportGet(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		file                 string
+		accept               string
+		expectedFile         string
+		serviceReturn        *models.ReportFile
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+		expectedLocation     string
+		expectedContentType  string
+		expectedBody         string
+	}{
+		{
+			name:         "Report streamed",
+			file:         "8_2023_report.csv",
+			expectedFile: "8_2023_report.csv",
+			serviceReturn: &models.ReportFile{
+				Name:    "8_2023_report.csv",
+				Content: io.NopCloser(strings.NewReader("report")),
+			},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/csv",
+			expectedBody:         "report",
+		},
+		{
+			name:         "Extension wins over Accept",
+			file:         "fcbf375313027e3ca47841baf5736886.ndjson",
+			accept:       "application/json",
+			expectedFile: "fcbf375313027e3ca47841baf5736886.ndjson",
+			serviceReturn: &models.ReportFile{
+				Name:    "fcbf375313027e3ca47841baf5736886.ndjson",
+				Content: io.NopCloser(strings.NewReader("{}\n")),
+			},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "application/x-ndjson",
+			expectedBody:         "{}\n",
+		},
+		{
+			name:         "Format negotiated by Accept",
+			file:         "fcbf375313027e3ca47841baf5736886",
+			accept:       "text/csv;q=0.5, application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			expectedFile: "fcbf375313027e3ca47841baf5736886.xlsx",
+			serviceReturn: &models.ReportFile{
+				Name:    "fcbf375313027e3ca47841baf5736886.xlsx",
+				Content: io.NopCloser(strings.NewReader("xlsx")),
+			},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			expectedBody:         "xlsx",
+		},
+		{
+			name:         "CSV is default",
+			file:         "fcbf375313027e3ca47841baf5736886",
+			accept:       "*/*",
+			expectedFile: "fcbf375313027e3ca47841baf5736886.csv",
+			serviceReturn: &models.ReportFile{
+				Name:    "fcbf375313027e3ca47841baf5736886.csv",
+				Content: io.NopCloser(strings.NewReader("report")),
+			},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/csv",
+			expectedBody:         "report",
+		},
+		{
+			name:               "Format not acceptable",
+			file:               "fcbf375313027e3ca47841baf5736886",
+			accept:             "application/pdf",
+			expectedStatusCode: http.StatusNotAcceptable,
+		},
+		{
+			name:         "Report redirected",
+			file:         "8_2023_report.csv",
+			expectedFile: "8_2023_report.csv",
+			serviceReturn: &models.ReportFile{
+				Name: "8_2023_report.csv",
+				URL:  "http://storage/reports/8_2023_report.csv",
+			},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusFound,
+			expectedLocation:     "http://storage/reports/8_2023_report.csv",
+		},
+		{
+			name:                 "Report not found",
+			file:                 "8_2023_report.csv",
+			expectedFile:         "8_2023_report.csv",
+			serviceError:         errors.ErrReportNotFound,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusNotFound,
+		},
+		{
+			name:                 "Some internal error",
+			file:                 "8_2023_report.csv",
+			expectedFile:         "8_2023_report.csv",
+			serviceError:         os.ErrInvalid,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+
+			if tc.expectingServiceCall {
+				service.EXPECT().ReportFile(context.Background(), tc.expectedFile).Return(tc.serviceReturn, tc.serviceError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAccept, tc.accept)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/report/:file")
+			c.SetParamNames("file")
+			c.SetParamValues(tc.file)
+
+			err := server.ReportGet(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+			a.Equal(tc.expectedLocation, rec.Header().Get(echo.HeaderLocation))
+
+			if tc.expectedBody != "" {
+				a.Equal(tc.expectedContentType, rec.Header().Get(echo.HeaderContentType))
+				a.Equal(tc.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlers_ReportStream(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		query                string
+		accept               string
+		expectedFilter       *models.ReportFilter
+		expectedFormat       string
+		serviceOutput        string
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+		expectedContentType  string
+		expectedBody         string
+	}{
+		{
+			name:  "Report streamed",
+			query: "from=2023-08-01T00:00:00Z&to=2023-08-15T00:00:00Z&slugs=AVITO_TEST,AVITO_VOICE&slugs=AVITO_MUSIC&methods=added",
+			expectedFilter: &models.ReportFilter{
+				From:    time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+				To:      time.Date(2023, time.August, 15, 0, 0, 0, 0, time.UTC),
+				Slugs:   []string{"AVITO_TEST", "AVITO_VOICE", "AVITO_MUSIC"},
+				Methods: []string{models.MethodAdded},
+			},
+			expectedFormat:       models.FormatCSV,
+			serviceOutput:        "report",
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/csv",
+			expectedBody:         "report",
+		},
+		{
+			name:                 "Format negotiated by Accept",
+			query:                "year=2023&month=8",
+			accept:               "application/json",
+			expectedFilter:       &models.ReportFilter{Year: 2023, Month: 8},
+			expectedFormat:       models.FormatJSON,
+			serviceOutput:        "[]\n",
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "application/json",
+			expectedBody:         "[]\n",
+		},
+		{
+			name:                 "Empty NDJSON report",
+			query:                "year=2023&month=8&format=ndjson",
+			expectedFilter:       &models.ReportFilter{Year: 2023, Month: 8},
+			expectedFormat:       models.FormatNDJSON,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "application/x-ndjson",
+		},
+		{
+			name:               "Invalid time",
+			query:              "from=yesterday&to=today",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Format not acceptable",
+			query:              "year=2023&month=8",
+			accept:             "application/pdf",
+			expectedStatusCode: http.StatusNotAcceptable,
+		},
+		{
+			name:                 "Invalid filter",
+			query:                "year=2023&month=8&methods=updated",
+			expectedFilter:       &models.ReportFilter{Year: 2023, Month: 8, Methods: []string{models.MethodUpdated}},
+			expectedFormat:       models.FormatCSV,
+			serviceError:         errors.ErrInvalidFilter,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:                 "Stream interrupted",
+			query:                "year=2023&month=8",
+			expectedFilter:       &models.ReportFilter{Year: 2023, Month: 8},
+			expectedFormat:       models.FormatCSV,
+			serviceOutput:        "partial",
+			serviceError:         os.ErrInvalid,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedContentType:  "text/csv",
+			expectedBody:         "partial",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+
+			if tc.expectingServiceCall {
+				service.EXPECT().StreamReport(context.Background(), tc.expectedFilter, tc.expectedFormat, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ *models.ReportFilter, _ string, w io.Writer) error {
+						if tc.serviceOutput != "" {
+							_, _ = io.WriteString(w, tc.serviceOutput)
+						}
+
+						return tc.serviceError
+					})
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/report/stream?"+tc.query, nil)
+			req.Header.Set(echo.HeaderAccept, tc.accept)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/report/stream")
+
+			err := server.ReportStream(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+
+			if tc.expectedStatusCode == http.StatusOK {
+				a.Equal(tc.expectedContentType, rec.Header().Get(echo.HeaderContentType))
+				a.Equal(tc.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -1,14 +1,18 @@
 package handlers
 
 import (
+	"encoding/csv"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/mailru/easyjson"
 	"go.uber.org/zap"
 
+	"github.com/dupreehkuda/avito-segments/internal/auth"
 	errs "github.com/dupreehkuda/avito-segments/internal/errors"
 	"github.com/dupreehkuda/avito-segments/internal/models"
 )
@@ -48,3 +52,203 @@ func (h Handlers) SegmentDelete(c echo.Context) error {
 
 	return c.NoContent(http.StatusOK)
 }
+
+func (h Handlers) SegmentUpdate(c echo.Context) error {
+	var req models.SegmentUpdateRequest
+
+	slug := c.Param("slug")
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.logger.Error("Unable to read body", zap.Error(err))
+		return h.ErrorHandler(err)
+	}
+
+	err = easyjson.Unmarshal(body, &req)
+	if err != nil {
+		h.logger.Error("Unable to decode JSON", zap.Error(err))
+		return h.ErrorHandler(err)
+	}
+
+	if err = h.service.SegmentUpdate(c.Request().Context(), slug, &req); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h Handlers) SegmentRestore(c echo.Context) error {
+	var req models.SegmentRestoreRequest
+
+	slug := c.Param("slug")
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.logger.Error("Unable to read body", zap.Error(err))
+		return h.ErrorHandler(err)
+	}
+
+	// Body is optional, restoring without members by default.
+	if len(body) != 0 {
+		err = easyjson.Unmarshal(body, &req)
+		if err != nil {
+			h.logger.Error("Unable to decode JSON", zap.Error(err))
+			return h.ErrorHandler(err)
+		}
+	}
+
+	// Restoring members writes memberships, so it is not allowed by segment scopes alone.
+	if req.RestoreMembers {
+		if err = auth.Authorize(c, models.ScopeUsersWrite); err != nil {
+			return err
+		}
+	}
+
+	if err = h.service.SegmentRestore(c.Request().Context(), slug, req.RestoreMembers); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h Handlers) SegmentList(c echo.Context) error {
+	filter := models.SegmentFilter{
+		Status: c.QueryParam("status"),
+		Prefix: c.QueryParam("prefix"),
+	}
+
+	if filter.Status == "" {
+		filter.Status = models.SegmentStatusActive
+	}
+
+	var err error
+
+	if filter.Limit, err = QueryInt(c, "limit", defaultPageLimit); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	if filter.Offset, err = QueryInt(c, "offset", 0); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	resp, err := h.service.SegmentList(c.Request().Context(), &filter)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h Handlers) SegmentGet(c echo.Context) error {
+	slug := c.Param("slug")
+
+	resp, err := h.service.SegmentGet(c.Request().Context(), slug)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h Handlers) SegmentMembers(c echo.Context) error {
+	limit, err := QueryInt(c, "limit", defaultPageLimit)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	resp, err := h.service.SegmentMembers(c.Request().Context(), c.Param("slug"), c.QueryParam("cursor"), limit)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SegmentExport streams every active member of the segment as CSV or NDJSON.
+func (h Handlers) SegmentExport(c echo.Context) error {
+	slug := c.Param("slug")
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = models.FormatCSV
+	}
+
+	if format != models.FormatCSV && format != models.FormatNDJSON {
+		return h.ErrorHandler(errs.ErrInvalidFormat)
+	}
+
+	resp := c.Response()
+	out := &lazyWriter{w: resp, start: func() {
+		if format == models.FormatNDJSON {
+			resp.Header().Set(echo.HeaderContentType, mimeXNDJSON)
+		} else {
+			resp.Header().Set(echo.HeaderContentType, "text/csv")
+		}
+
+		resp.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment;filename=%s_members.%s", slug, format))
+		resp.WriteHeader(http.StatusOK)
+	}}
+
+	// CSV header stays buffered until the first flush, so it does not commit the response either.
+	var csvOut *csv.Writer
+	if format == models.FormatCSV {
+		csvOut = csv.NewWriter(out)
+		_ = csvOut.Write([]string{"user_id", "created_at", "expired_at"})
+	}
+
+	written := 0
+
+	write := func(member models.SegmentMember) error {
+		if csvOut == nil {
+			if _, err := easyjson.MarshalToWriter(member, out); err != nil {
+				return err
+			}
+
+			if _, err := out.Write([]byte{'\n'}); err != nil {
+				return err
+			}
+		} else {
+			expiredAt := ""
+			if member.ExpiredAt != nil {
+				expiredAt = member.ExpiredAt.Format(time.RFC3339)
+			}
+
+			if err := csvOut.Write([]string{member.UserID, member.CreatedAt.Format(time.RFC3339), expiredAt}); err != nil {
+				return err
+			}
+		}
+
+		if written++; written%exportFlushEvery == 0 {
+			if csvOut != nil {
+				csvOut.Flush()
+			}
+
+			resp.Flush()
+		}
+
+		return nil
+	}
+
+	err := h.service.SegmentExport(c.Request().Context(), slug, write)
+	if err != nil {
+		if !out.started {
+			return h.ErrorHandler(err)
+		}
+
+		// Headers are already sent, so the only thing left is to cut the stream.
+		h.logger.Error("Export interrupted", zap.String("slug", slug), zap.Error(err))
+
+		return nil
+	}
+
+	if csvOut != nil {
+		csvOut.Flush()
+	}
+
+	// Empty NDJSON export has no bytes at all.
+	if !out.started {
+		out.begin()
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/mailru/easyjson"
@@ -14,6 +15,7 @@ import (
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 
+	"github.com/dupreehkuda/avito-segments/internal/auth"
 	"github.com/dupreehkuda/avito-segments/internal/errors"
 	"github.com/dupreehkuda/avito-segments/internal/handlers"
 	"github.com/dupreehkuda/avito-segments/internal/models"
@@ -54,6 +56,15 @@ func TestHandlers_SegmentAdd(t *testing.T) {
 			serviceReturn:      errors.ErrInvalidSegmentSlug,
 			expectedStatusCode: http.StatusBadRequest,
 		},
+		{
+			name: "Invalid percentage",
+			inputBody: &models.Segment{
+				Slug:       "NEW_SLUG",
+				Percentage: 120,
+			},
+			serviceReturn:      errors.ErrInvalidPercentage,
+			expectedStatusCode: http.StatusBadRequest,
+		},
 		{
 			name: "Empty slug",
 			inputBody: &models.Segment{
@@ -174,3 +185,504 @@ func TestHandlers_SegmentDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlers_SegmentList(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		query                string
+		expectedFilter       *models.SegmentFilter
+		expectingServiceCall bool
+		serviceReturn        error
+		expectedStatusCode   int
+	}{
+		{
+			name:                 "Default filter",
+			query:                "",
+			expectedFilter:       &models.SegmentFilter{Status: models.SegmentStatusActive, Limit: 50},
+			expectingServiceCall: true,
+			serviceReturn:        nil,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:                 "Custom filter",
+			query:                "?status=deleted&prefix=AVITO&limit=10&offset=20",
+			expectedFilter:       &models.SegmentFilter{Status: models.SegmentStatusDeleted, Prefix: "AVITO", Limit: 10, Offset: 20},
+			expectingServiceCall: true,
+			serviceReturn:        nil,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:                 "Invalid status",
+			query:                "?status=expired",
+			expectedFilter:       &models.SegmentFilter{Status: "expired", Limit: 50},
+			expectingServiceCall: true,
+			serviceReturn:        errors.ErrInvalidFilter,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:                 "Invalid limit",
+			query:                "?limit=ten",
+			expectingServiceCall: false,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:                 "Some internal error",
+			query:                "",
+			expectedFilter:       &models.SegmentFilter{Status: models.SegmentStatusActive, Limit: 50},
+			expectingServiceCall: true,
+			serviceReturn:        os.ErrInvalid,
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			if tc.expectingServiceCall {
+				service.EXPECT().SegmentList(context.Background(), tc.expectedFilter).
+					Return(&models.SegmentListResponse{}, tc.serviceReturn)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/segment")
+
+			err := server.SegmentList(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_SegmentGet(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name               string
+		input              string
+		serviceReturn      error
+		expectedStatusCode int
+	}{
+		{
+			name:               "Segment found",
+			input:              "NEW_SLUG",
+			serviceReturn:      nil,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Invalid slug naming",
+			input:              "NeW_SLug-1",
+			serviceReturn:      errors.ErrInvalidSegmentSlug,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Segment not found",
+			input:              "OLD_SLUG",
+			serviceReturn:      errors.ErrSegmentNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Some internal error",
+			input:              "NEW_SLUG",
+			serviceReturn:      os.ErrInvalid,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			service.EXPECT().SegmentGet(context.Background(), tc.input).Return(&models.SegmentInfo{Slug: tc.input}, tc.serviceReturn)
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/segment/:slug")
+			c.SetParamNames("slug")
+			c.SetParamValues(tc.input)
+
+			err := server.SegmentGet(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_SegmentUpdate(t *testing.T) {
+	a := assert.New(t)
+
+	description := "new description"
+
+	testCases := []struct {
+		name               string
+		slug               string
+		inputBody          *models.SegmentUpdateRequest
+		serviceReturn      error
+		expectedStatusCode int
+	}{
+		{
+			name:               "Segment updated",
+			slug:               "NEW_SLUG",
+			inputBody:          &models.SegmentUpdateRequest{Description: &description},
+			serviceReturn:      nil,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Nothing to update",
+			slug:               "NEW_SLUG",
+			inputBody:          &models.SegmentUpdateRequest{},
+			serviceReturn:      errors.ErrNothingToUpdate,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Segment not found",
+			slug:               "OLD_SLUG",
+			inputBody:          &models.SegmentUpdateRequest{Description: &description},
+			serviceReturn:      errors.ErrSegmentNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Segment has been deleted",
+			slug:               "NEW_SLUG",
+			inputBody:          &models.SegmentUpdateRequest{Description: &description},
+			serviceReturn:      errors.ErrAlreadyDeleted,
+			expectedStatusCode: http.StatusGone,
+		},
+		{
+			name:               "Some internal error",
+			slug:               "NEW_SLUG",
+			inputBody:          &models.SegmentUpdateRequest{Description: &description},
+			serviceReturn:      os.ErrInvalid,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, _ := easyjson.Marshal(tc.inputBody)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			service.EXPECT().SegmentUpdate(context.Background(), tc.slug, tc.inputBody).Return(tc.serviceReturn)
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodPatch, "/", bytes.NewReader(data))
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/segment/:slug")
+			c.SetParamNames("slug")
+			c.SetParamValues(tc.slug)
+
+			err := server.SegmentUpdate(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_SegmentRestore(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name               string
+		slug               string
+		body               string
+		restoreMembers     bool
+		scopes             []string
+		serviceReturn      error
+		expectedStatusCode int
+	}{
+		{
+			name:               "Segment restored w/out body",
+			slug:               "NEW_SLUG",
+			body:               "",
+			restoreMembers:     false,
+			serviceReturn:      nil,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Segment restored w/ members",
+			slug:               "NEW_SLUG",
+			body:               `{"restoreMembers":true}`,
+			restoreMembers:     true,
+			serviceReturn:      nil,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Members not restored w/out users scope",
+			slug:               "NEW_SLUG",
+			body:               `{"restoreMembers":true}`,
+			scopes:             []string{models.ScopeSegmentsWrite},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Segment not deleted",
+			slug:               "NEW_SLUG",
+			body:               "",
+			serviceReturn:      errors.ErrNotDeleted,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "Segment not found",
+			slug:               "OLD_SLUG",
+			body:               "",
+			serviceReturn:      errors.ErrSegmentNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Some internal error",
+			slug:               "NEW_SLUG",
+			body:               "",
+			serviceReturn:      os.ErrInvalid,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+
+			if tc.expectedStatusCode != http.StatusForbidden {
+				service.EXPECT().SegmentRestore(gomock.Any(), tc.slug, tc.restoreMembers).Return(tc.serviceReturn)
+			}
+
+			if tc.scopes == nil {
+				tc.scopes = []string{models.ScopeSegmentsWrite, models.ScopeUsersWrite}
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(tc.body)))
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/segment/:slug/restore")
+			c.SetParamNames("slug")
+			c.SetParamValues(tc.slug)
+			auth.WithPrincipal(c, &models.Principal{ID: "key:test", Scopes: tc.scopes})
+
+			err := server.SegmentRestore(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_SegmentMembers(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		query                string
+		limit                int
+		serviceReturn        *models.SegmentMembersResponse
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+	}{
+		{
+			name:                 "Members listed",
+			query:                "?limit=2&cursor=abc",
+			limit:                2,
+			serviceReturn:        &models.SegmentMembersResponse{NextCursor: "def"},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:               "Invalid limit",
+			query:              "?limit=two",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:                 "Segment not found",
+			query:                "?limit=2&cursor=abc",
+			limit:                2,
+			serviceError:         errors.ErrSegmentNotFound,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusNotFound,
+		},
+		{
+			name:                 "Some internal error",
+			query:                "?limit=2&cursor=abc",
+			limit:                2,
+			serviceError:         os.ErrInvalid,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			if tc.expectingServiceCall {
+				service.EXPECT().SegmentMembers(context.Background(), "TEST_SLUG", "abc", tc.limit).Return(tc.serviceReturn, tc.serviceError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/segment/:slug/users")
+			c.SetParamNames("slug")
+			c.SetParamValues("TEST_SLUG")
+
+			err := server.SegmentMembers(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_SegmentExport(t *testing.T) {
+	a := assert.New(t)
+
+	expire := time.Date(2099, time.August, 26, 19, 00, 00, 00, time.UTC)
+	members := []models.SegmentMember{
+		{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", CreatedAt: time.Date(2023, time.August, 26, 19, 00, 00, 00, time.UTC)},
+		{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80003", CreatedAt: time.Date(2023, time.August, 26, 19, 00, 00, 00, time.UTC), ExpiredAt: &expire},
+	}
+
+	testCases := []struct {
+		name                 string
+		query                string
+		empty                bool
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+		expectedBody         string
+	}{
+		{
+			name:                 "CSV export",
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedBody: "user_id,created_at,expired_at\n" +
+				"80b0b88d-379e-11ee-8bf7-0242c0a80002,2023-08-26T19:00:00Z,\n" +
+				"80b0b88d-379e-11ee-8bf7-0242c0a80003,2023-08-26T19:00:00Z,2099-08-26T19:00:00Z\n",
+		},
+		{
+			name:                 "NDJSON export",
+			query:                "?format=ndjson",
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedBody: `{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002","createdAt":"2023-08-26T19:00:00Z"}` + "\n" +
+				`{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80003","createdAt":"2023-08-26T19:00:00Z","expiredAt":"2099-08-26T19:00:00Z"}` + "\n",
+		},
+		{
+			name:                 "Empty CSV export",
+			empty:                true,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedBody:         "user_id,created_at,expired_at\n",
+		},
+		{
+			name:                 "Empty NDJSON export",
+			query:                "?format=ndjson",
+			empty:                true,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:               "Unsupported format",
+			query:              "?format=xml",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:                 "Segment deleted",
+			serviceError:         errors.ErrAlreadyDeleted,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusGone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			if tc.expectingServiceCall {
+				service.EXPECT().SegmentExport(context.Background(), "TEST_SLUG", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, fn func(member models.SegmentMember) error) error {
+						if tc.serviceError != nil {
+							return tc.serviceError
+						}
+
+						if tc.empty {
+							return nil
+						}
+
+						for _, member := range members {
+							if err := fn(member); err != nil {
+								return err
+							}
+						}
+
+						return nil
+					})
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/segment/:slug/users/export")
+			c.SetParamNames("slug")
+			c.SetParamValues("TEST_SLUG")
+
+			err := server.SegmentExport(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+
+			if tc.expectedBody != "" {
+				a.Equal(tc.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	errs "github.com/dupreehkuda/avito-segments/internal/errors"
+)
+
+// statsDateLayout is the layout of stats range bounds.
+const statsDateLayout = "2006-01-02"
+
+func (h Handlers) SegmentStats(c echo.Context) error {
+	from, err := queryDate(c, "from")
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	to, err := queryDate(c, "to")
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	resp, err := h.service.SegmentStats(c.Request().Context(), c.Param("slug"), from, to)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h Handlers) SegmentRanking(c echo.Context) error {
+	limit, err := QueryInt(c, "limit", defaultPageLimit)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	offset, err := QueryInt(c, "offset", 0)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	resp, err := h.service.SegmentRanking(c.Request().Context(), limit, offset)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// queryDate reads UTC date from query parameter, missing parameter is a zero time.
+func queryDate(c echo.Context, name string) (time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse(statsDateLayout, value)
+	if err != nil {
+		return time.Time{}, errs.ErrInvalidPeriod
+	}
+
+	return date, nil
+}
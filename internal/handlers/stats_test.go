@@ -0,0 +1,171 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/handlers"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+func TestHandlers_SegmentStats(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		slug                 string
+		query                string
+		expectedFrom         time.Time
+		expectedTo           time.Time
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+	}{
+		{
+			name:                 "Stats for range",
+			slug:                 "AVITO_TEST",
+			query:                "from=2023-08-01&to=2023-09-01",
+			expectedFrom:         time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:           time.Date(2023, time.September, 1, 0, 0, 0, 0, time.UTC),
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:                 "Stats for default range",
+			slug:                 "AVITO_TEST",
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:               "Invalid date",
+			slug:               "AVITO_TEST",
+			query:              "from=01.08.2023",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:                 "Segment not found",
+			slug:                 "AVITO_TEST",
+			serviceError:         errors.ErrSegmentNotFound,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusNotFound,
+		},
+		{
+			name:                 "Some internal error",
+			slug:                 "AVITO_TEST",
+			serviceError:         os.ErrInvalid,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+
+			if tc.expectingServiceCall {
+				service.EXPECT().SegmentStats(context.Background(), tc.slug, tc.expectedFrom, tc.expectedTo).
+					Return(&models.SegmentStats{Slug: tc.slug}, tc.serviceError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/segment/:slug/stats")
+			c.SetParamNames("slug")
+			c.SetParamValues(tc.slug)
+
+			err := server.SegmentStats(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_SegmentRanking(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		query                string
+		expectedLimit        int
+		expectedOffset       int
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+	}{
+		{
+			name:                 "Default page",
+			expectedLimit:        50,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:                 "Custom page",
+			query:                "limit=10&offset=20",
+			expectedLimit:        10,
+			expectedOffset:       20,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:               "Invalid limit",
+			query:              "limit=ten",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:                 "Some internal error",
+			expectedLimit:        50,
+			serviceError:         os.ErrInvalid,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+
+			if tc.expectingServiceCall {
+				service.EXPECT().SegmentRanking(context.Background(), tc.expectedLimit, tc.expectedOffset).
+					Return(&models.SegmentListResponse{Limit: tc.expectedLimit, Offset: tc.expectedOffset}, tc.serviceError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/stats/segments")
+
+			err := server.SegmentRanking(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"bufio"
+	"bytes"
 	"errors"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -12,6 +16,7 @@ import (
 
 	errs "github.com/dupreehkuda/avito-segments/internal/errors"
 	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/problem"
 )
 
 func (h Handlers) UserSetSegments(c echo.Context) error {
@@ -74,6 +79,159 @@ func (h Handlers) UserDeleteSegments(c echo.Context) error {
 	return c.NoContent(http.StatusOK)
 }
 
+func (h Handlers) UserUpdateSegments(c echo.Context) error {
+	var req models.UserUpdateRequest
+
+	id := c.Param("id")
+
+	if err := UUIDCheck(id); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.logger.Error("Unable to read body", zap.Error(err))
+		return h.ErrorHandler(err)
+	}
+
+	err = easyjson.Unmarshal(body, &req)
+	if err != nil {
+		h.logger.Error("Unable to decode JSON", zap.Error(err))
+		return h.ErrorHandler(err)
+	}
+
+	req.UserID = id
+
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		return h.ErrorHandler(errs.ErrNoSegmentsProvided)
+	}
+
+	if err = h.service.UserUpdateSegments(c.Request().Context(), &req); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h Handlers) UserBulkSetSegments(c echo.Context) error {
+	reqs, decodeErrs, err := h.readBulk(c)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	if len(reqs) == 0 {
+		return h.ErrorHandler(errs.ErrNoSegmentsProvided)
+	}
+
+	var (
+		resp      = models.UserBulkResponse{Results: make([]models.UserBulkResult, len(reqs))}
+		valid     = make([]models.UserSetRequest, 0, len(reqs))
+		positions = make([]int, 0, len(reqs))
+	)
+
+	for i, req := range reqs {
+		resp.Results[i] = models.UserBulkResult{Index: i, UserID: req.UserID}
+
+		if err = decodeErrs[i]; err == nil {
+			err = UUIDCheck(req.UserID)
+		}
+
+		if err != nil {
+			failBulkEntry(&resp.Results[i], err)
+			continue
+		}
+
+		valid = append(valid, req)
+		positions = append(positions, i)
+	}
+
+	if len(valid) > 0 {
+		results, err := h.service.UserBulkSetSegments(c.Request().Context(), valid)
+		if err != nil {
+			return h.ErrorHandler(err)
+		}
+
+		for i, result := range results {
+			if result != nil {
+				failBulkEntry(&resp.Results[positions[i]], result)
+			}
+		}
+	}
+
+	for _, result := range resp.Results {
+		if result.Error == "" {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// failBulkEntry reports error of bulk entry with message, code and details of its problem.
+func failBulkEntry(result *models.UserBulkResult, err error) {
+	entry := problem.Entry(err)
+	result.Error, result.Code, result.Details = entry.Detail, entry.Code, entry.Details
+}
+
+// readBulk decodes bulk request either as JSON object or as NDJSON stream of UserSetRequest.
+// For NDJSON malformed lines are reported as per-entry errors instead of failing the request.
+func (h Handlers) readBulk(c echo.Context) ([]models.UserSetRequest, []error, error) {
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+
+	if !strings.HasPrefix(contentType, mimeNDJSON) && !strings.HasPrefix(contentType, mimeXNDJSON) {
+		var req models.UserBulkRequest
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			h.logger.Error("Unable to read body", zap.Error(err))
+			return nil, nil, err
+		}
+
+		if err = easyjson.Unmarshal(body, &req); err != nil {
+			h.logger.Error("Unable to decode JSON", zap.Error(err))
+			return nil, nil, err
+		}
+
+		return req.Users, make([]error, len(req.Users)), nil
+	}
+
+	var (
+		reqs       = make([]models.UserSetRequest, 0)
+		decodeErrs = make([]error, 0)
+		scanner    = bufio.NewScanner(c.Request().Body)
+	)
+
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxNDJSONLine)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var req models.UserSetRequest
+
+		if err := easyjson.Unmarshal(line, &req); err != nil {
+			reqs = append(reqs, models.UserSetRequest{})
+			decodeErrs = append(decodeErrs, errs.ErrInvalidEntry)
+
+			continue
+		}
+
+		reqs = append(reqs, req)
+		decodeErrs = append(decodeErrs, nil)
+	}
+
+	if err := scanner.Err(); err != nil {
+		h.logger.Error("Unable to read body", zap.Error(err))
+		return nil, nil, err
+	}
+
+	return reqs, decodeErrs, nil
+}
+
 func (h Handlers) UserGetSegments(c echo.Context) error {
 	id := c.Param("id")
 
@@ -81,7 +239,22 @@ func (h Handlers) UserGetSegments(c echo.Context) error {
 		return h.ErrorHandler(err)
 	}
 
-	resp, err := h.service.UserGetSegments(c.Request().Context(), id)
+	var (
+		resp *models.UserResponse
+		err  error
+	)
+
+	if at := c.QueryParam("at"); at != "" {
+		moment, parseErr := time.Parse(time.RFC3339, at)
+		if parseErr != nil {
+			return h.ErrorHandler(errs.ErrInvalidPeriod)
+		}
+
+		resp, err = h.service.UserGetSegmentsAt(c.Request().Context(), id, moment)
+	} else {
+		resp, err = h.service.UserGetSegments(c.Request().Context(), id)
+	}
+
 	if err != nil {
 		if errors.Is(err, errs.ErrSegmentsNotFound) {
 			return c.NoContent(http.StatusNoContent)
@@ -102,7 +275,7 @@ func UUIDCheck(uuids ...string) error {
 
 		_, err := uuid.Parse(id)
 		if err != nil {
-			return errs.ErrInvalidUserID
+			return errs.WithUserIDs(errs.ErrInvalidUserID, id)
 		}
 	}
 
@@ -398,3 +398,301 @@ func TestHandlers_UserGetSegments(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlers_UserGetSegmentsAt(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		at                   string
+		expectingServiceCall bool
+		serviceReturnData    *models.UserResponse
+		serviceReturnError   error
+		expectedStatusCode   int
+	}{
+		{
+			name:                 "Successful request",
+			at:                   "2023-08-26T19:00:00Z",
+			expectingServiceCall: true,
+			serviceReturnData: &models.UserResponse{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+				Slugs:  []string{"TEST_SLUG"},
+			},
+			serviceReturnError: nil,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:                 "Invalid moment",
+			at:                   "26.08.2023",
+			expectingServiceCall: false,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:                 "Moment in future",
+			at:                   "2023-08-26T19:00:00Z",
+			expectingServiceCall: true,
+			serviceReturnError:   errors.ErrInvalidPeriod,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:                 "No segments at that moment",
+			at:                   "2023-08-26T19:00:00Z",
+			expectingServiceCall: true,
+			serviceReturnError:   errors.ErrSegmentsNotFound,
+			expectedStatusCode:   http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			id := "80b0b88d-379e-11ee-8bf7-0242c0a80002"
+
+			service := NewMockService(ctrl)
+			if tc.expectingServiceCall {
+				moment, _ := time.Parse(time.RFC3339, tc.at)
+				service.EXPECT().UserGetSegmentsAt(context.Background(), id, moment).Return(tc.serviceReturnData, tc.serviceReturnError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/?at="+tc.at, nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/user/:id")
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+
+			err := server.UserGetSegments(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_UserUpdateSegments(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		userID               string
+		inputBody            *models.UserUpdateRequest
+		expectingServiceCall bool
+		serviceReturn        error
+		expectedStatusCode   int
+	}{
+		{
+			name:   "Segments moved",
+			userID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			inputBody: &models.UserUpdateRequest{
+				Add:    []models.UserSegment{{Slug: "TEST_SLUG_B"}},
+				Remove: []string{"TEST_SLUG_A"},
+			},
+			expectingServiceCall: true,
+			serviceReturn:        nil,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:                 "Invalid user id",
+			userID:               "123456",
+			inputBody:            &models.UserUpdateRequest{Remove: []string{"TEST_SLUG_A"}},
+			expectingServiceCall: false,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:                 "Nothing provided",
+			userID:               "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			inputBody:            &models.UserUpdateRequest{},
+			expectingServiceCall: false,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:   "Conflicting slugs",
+			userID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			inputBody: &models.UserUpdateRequest{
+				Add:    []models.UserSegment{{Slug: "TEST_SLUG_A"}},
+				Remove: []string{"TEST_SLUG_A"},
+			},
+			expectingServiceCall: true,
+			serviceReturn:        errors.ErrConflictingSlugs,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:                 "Some internal error",
+			userID:               "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			inputBody:            &models.UserUpdateRequest{Remove: []string{"TEST_SLUG_A"}},
+			expectingServiceCall: true,
+			serviceReturn:        os.ErrInvalid,
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, _ := easyjson.Marshal(tc.inputBody)
+			var input models.UserUpdateRequest
+			_ = easyjson.Unmarshal(data, &input)
+			input.UserID = tc.userID
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			if tc.expectingServiceCall {
+				service.EXPECT().UserUpdateSegments(context.Background(), &input).Return(tc.serviceReturn)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodPatch, "/", bytes.NewReader(data))
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/user/:id")
+			c.SetParamNames("id")
+			c.SetParamValues(tc.userID)
+
+			err := server.UserUpdateSegments(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_UserBulkSetSegments(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		contentType          string
+		inputBody            string
+		serviceInput         []models.UserSetRequest
+		serviceReturn        []error
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+		expectedSucceeded    int
+		expectedFailed       int
+		expectedResults      []models.UserBulkResult
+	}{
+		{
+			name:        "JSON batch stored",
+			contentType: echo.MIMEApplicationJSON,
+			inputBody:   `{"users":[{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002","segments":[{"slug":"TEST_SLUG"}]}]}`,
+			serviceInput: []models.UserSetRequest{
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Segments: []models.UserSegment{{Slug: "TEST_SLUG"}}},
+			},
+			serviceReturn:        []error{nil},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedSucceeded:    1,
+		},
+		{
+			name:        "NDJSON batch w/ broken lines",
+			contentType: "application/x-ndjson",
+			inputBody: `{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002","segments":[{"slug":"TEST_SLUG"}]}
+{"userID":"123456","segments":[{"slug":"TEST_SLUG"}]}
+not a json
+
+{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80003","segments":[{"slug":"UNKNOWN"}]}
+`,
+			serviceInput: []models.UserSetRequest{
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Segments: []models.UserSegment{{Slug: "TEST_SLUG"}}},
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80003", Segments: []models.UserSegment{{Slug: "UNKNOWN"}}},
+			},
+			serviceReturn:        []error{nil, errors.WithSlugs(errors.ErrSegmentsNotFound, "UNKNOWN")},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+			expectedSucceeded:    1,
+			expectedFailed:       3,
+			expectedResults: []models.UserBulkResult{
+				{Index: 0, UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002"},
+				{
+					Index: 1, UserID: "123456", Error: "invalid userID", Code: "invalid_user_id",
+					Details: &models.ProblemDetails{UserIDs: []string{"123456"}},
+				},
+				{Index: 2, Error: "invalid entry", Code: "invalid_entry"},
+				{
+					Index: 3, UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80003", Error: "segment(s) not found", Code: "segments_not_found",
+					Details: &models.ProblemDetails{Slugs: []string{"UNKNOWN"}},
+				},
+			},
+		},
+		{
+			name:               "Empty batch",
+			contentType:        echo.MIMEApplicationJSON,
+			inputBody:          `{"users":[]}`,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "Batch too large",
+			contentType: echo.MIMEApplicationJSON,
+			inputBody:   `{"users":[{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002","segments":[{"slug":"TEST_SLUG"}]}]}`,
+			serviceInput: []models.UserSetRequest{
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Segments: []models.UserSegment{{Slug: "TEST_SLUG"}}},
+			},
+			serviceError:         errors.ErrBatchTooLarge,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "Some internal error",
+			contentType: echo.MIMEApplicationJSON,
+			inputBody:   `{"users":[{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002","segments":[{"slug":"TEST_SLUG"}]}]}`,
+			serviceInput: []models.UserSetRequest{
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Segments: []models.UserSegment{{Slug: "TEST_SLUG"}}},
+			},
+			serviceError:         os.ErrInvalid,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+			if tc.expectingServiceCall {
+				service.EXPECT().UserBulkSetSegments(context.Background(), tc.serviceInput).Return(tc.serviceReturn, tc.serviceError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(tc.inputBody)))
+			req.Header.Set(echo.HeaderContentType, tc.contentType)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/user/bulk")
+
+			err := server.UserBulkSetSegments(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+
+			if tc.expectedStatusCode == http.StatusOK {
+				var resp models.UserBulkResponse
+				a.NoError(easyjson.Unmarshal(rec.Body.Bytes(), &resp))
+				a.Equal(tc.expectedSucceeded, resp.Succeeded)
+				a.Equal(tc.expectedFailed, resp.Failed)
+
+				if tc.expectedResults != nil {
+					a.Equal(tc.expectedResults, resp.Results)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mailru/easyjson"
+	"go.uber.org/zap"
+
+	errs "github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+func (h Handlers) WebhookCreate(c echo.Context) error {
+	var req models.WebhookRequest
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		h.logger.Error("Unable to read body", zap.Error(err))
+		return h.ErrorHandler(err)
+	}
+
+	err = easyjson.Unmarshal(body, &req)
+	if err != nil {
+		h.logger.Error("Unable to decode JSON", zap.Error(err))
+		return h.ErrorHandler(err)
+	}
+
+	resp, err := h.service.WebhookCreate(c.Request().Context(), &req)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h Handlers) WebhookList(c echo.Context) error {
+	resp, err := h.service.WebhookList(c.Request().Context())
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h Handlers) WebhookGet(c echo.Context) error {
+	resp, err := h.service.WebhookGet(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h Handlers) WebhookDelete(c echo.Context) error {
+	if err := h.service.WebhookDelete(c.Request().Context(), c.Param("id")); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+// WebhookDeliveries returns delivery log of webhook, "status=dead" gives its dead-letter list.
+func (h Handlers) WebhookDeliveries(c echo.Context) error {
+	filter := models.DeliveryFilter{
+		Status: c.QueryParam("status"),
+	}
+
+	var err error
+
+	if filter.Limit, err = QueryInt(c, "limit", defaultPageLimit); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	if filter.Offset, err = QueryInt(c, "offset", 0); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	resp, err := h.service.WebhookDeliveries(c.Request().Context(), c.Param("id"), &filter)
+	if err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h Handlers) WebhookRedeliver(c echo.Context) error {
+	deliveryID, err := strconv.ParseInt(c.Param("delivery"), 10, 64)
+	if err != nil {
+		return h.ErrorHandler(errs.ErrDeliveryNotFound)
+	}
+
+	if err = h.service.WebhookRedeliver(c.Request().Context(), c.Param("id"), deliveryID); err != nil {
+		return h.ErrorHandler(err)
+	}
+
+	return c.NoContent(http.StatusAccepted)
+}
@@ -0,0 +1,236 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/handlers"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+const testWebhookID = "5f0c6a3e-4a0b-4c64-9a4e-2b7f3c1d9e10"
+
+func TestHandlers_WebhookCreate(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		body                 string
+		expectedReq          *models.WebhookRequest
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+	}{
+		{
+			name: "Webhook created",
+			body: `{"url":"https://example.com/hooks","slugs":["AVITO_TEST"]}`,
+			expectedReq: &models.WebhookRequest{
+				URL:   "https://example.com/hooks",
+				Slugs: []string{"AVITO_TEST"},
+			},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusCreated,
+		},
+		{
+			name:                 "Invalid url",
+			body:                 `{"url":"example","slugs":["AVITO_TEST"]}`,
+			expectedReq:          &models.WebhookRequest{URL: "example", Slugs: []string{"AVITO_TEST"}},
+			serviceError:         errors.ErrInvalidWebhook,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:                 "Segment not found",
+			body:                 `{"url":"https://example.com/hooks","slugs":["AVITO_TEST"]}`,
+			expectedReq:          &models.WebhookRequest{URL: "https://example.com/hooks", Slugs: []string{"AVITO_TEST"}},
+			serviceError:         errors.ErrSegmentsNotFound,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusBadRequest,
+		},
+		{
+			name:               "Invalid JSON",
+			body:               `{"url":`,
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+
+			if tc.expectingServiceCall {
+				service.EXPECT().WebhookCreate(context.Background(), tc.expectedReq).
+					Return(&models.Webhook{ID: testWebhookID}, tc.serviceError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/webhooks")
+
+			err := server.WebhookCreate(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_WebhookDeliveries(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		query                string
+		expectedFilter       *models.DeliveryFilter
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+	}{
+		{
+			name:                 "Delivery log",
+			expectedFilter:       &models.DeliveryFilter{Limit: 50},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:                 "Dead letters",
+			query:                "status=dead&limit=10&offset=10",
+			expectedFilter:       &models.DeliveryFilter{Status: models.DeliveryDead, Limit: 10, Offset: 10},
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusOK,
+		},
+		{
+			name:               "Invalid offset",
+			query:              "offset=first",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:                 "Webhook not found",
+			expectedFilter:       &models.DeliveryFilter{Limit: 50},
+			serviceError:         errors.ErrWebhookNotFound,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusNotFound,
+		},
+		{
+			name:                 "Some internal error",
+			expectedFilter:       &models.DeliveryFilter{Limit: 50},
+			serviceError:         os.ErrInvalid,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+
+			if tc.expectingServiceCall {
+				service.EXPECT().WebhookDeliveries(context.Background(), testWebhookID, tc.expectedFilter).
+					Return(&models.WebhookDeliveriesResponse{}, tc.serviceError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodGet, "/?"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/webhooks/:id/deliveries")
+			c.SetParamNames("id")
+			c.SetParamValues(testWebhookID)
+
+			err := server.WebhookDeliveries(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
+
+func TestHandlers_WebhookRedeliver(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name                 string
+		delivery             string
+		expectedDelivery     int64
+		serviceError         error
+		expectingServiceCall bool
+		expectedStatusCode   int
+	}{
+		{
+			name:                 "Delivery queued again",
+			delivery:             "42",
+			expectedDelivery:     42,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusAccepted,
+		},
+		{
+			name:               "Invalid delivery id",
+			delivery:           "last",
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:                 "Delivery is pending",
+			delivery:             "42",
+			expectedDelivery:     42,
+			serviceError:         errors.ErrDeliveryPending,
+			expectingServiceCall: true,
+			expectedStatusCode:   http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			service := NewMockService(ctrl)
+
+			if tc.expectingServiceCall {
+				service.EXPECT().WebhookRedeliver(context.Background(), testWebhookID, tc.expectedDelivery).Return(tc.serviceError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			server := handlers.New(service, zp)
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+
+			e := echo.New()
+			c := e.NewContext(req, rec)
+			c.SetPath("/api/v1/webhooks/:id/deliveries/:delivery/retry")
+			c.SetParamNames("id", "delivery")
+			c.SetParamValues(testWebhookID, tc.delivery)
+
+			err := server.WebhookRedeliver(c)
+			e.DefaultHTTPErrorHandler(err, c)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+		})
+	}
+}
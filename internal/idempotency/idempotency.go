@@ -0,0 +1,288 @@
+// Package idempotency replays responses to requests retried with the same Idempotency-Key.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/auth"
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	errs "github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/problem"
+)
+
+//go:generate mockgen -source=idempotency.go -destination=mock_test.go -package=idempotency_test
+
+const (
+	// HeaderIdempotencyKey is the header holding client's idempotency key.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderReplayed marks response replayed from a previous request.
+	HeaderReplayed = "Idempotent-Replayed"
+	// MaxKeyLength is the longest idempotency key.
+	MaxKeyLength = 255
+)
+
+const (
+	defaultTTL    = 24 * time.Hour
+	defaultLease  = time.Minute
+	purgeInterval = time.Hour
+	// storeTimeout bounds saving or releasing the key after the request, which is done
+	// even if client went away, so the key is not left in progress until it expires.
+	storeTimeout = 5 * time.Second
+)
+
+// Repository keeps idempotency keys along with responses to the requests made with them.
+type Repository interface {
+	ClaimIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error)
+	SaveIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) error
+	ReleaseIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) error
+	PurgeIdempotencyKeys(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Idempotency makes requests with Idempotency-Key header safe to retry.
+type Idempotency struct {
+	repo   Repository
+	ttl    time.Duration
+	lease  time.Duration
+	logger *zap.Logger
+}
+
+// Register creates new instance of Idempotency and binds purging of expired keys to the application lifecycle.
+func Register(lc fx.Lifecycle, repo Repository, config *config.Config, logger *zap.Logger) *Idempotency {
+	i := New(repo, config, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				i.run(ctx)
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			return nil
+		},
+	})
+
+	return i
+}
+
+// New creates new instance of Idempotency keeping responses for configured ttl
+// and handling requests for no longer than configured lease.
+func New(repo Repository, config *config.Config, logger *zap.Logger) *Idempotency {
+	i := &Idempotency{
+		repo:   repo,
+		ttl:    config.Idempotency.TTL,
+		lease:  config.Idempotency.Lease,
+		logger: logger,
+	}
+
+	if i.ttl <= 0 {
+		i.ttl = defaultTTL
+	}
+
+	if i.lease <= 0 {
+		i.lease = defaultLease
+	}
+
+	return i
+}
+
+// Idempotent handles request with Idempotency-Key only once per principal and key.
+// Retry with the same key and request gets the stored response, retry with another request is rejected.
+// Server errors are not stored, so such requests can be retried with the same key.
+// Key is held for the lease while request is handled, so a key left by a crashed instance is taken over after it.
+func (i *Idempotency) Idempotent(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return next(c)
+		}
+
+		if len(key) > MaxKeyLength {
+			return problem.New(http.StatusBadRequest, "invalid_idempotency_key", errs.ErrInvalidIdempotencyKey.Error())
+		}
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			i.logger.Error("Unable to read body", zap.Error(err))
+			return problem.New(http.StatusInternalServerError, "internal_server_error", "internal server error")
+		}
+
+		c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+		// Lease covers handling the request and storing its response afterwards.
+		now := time.Now()
+		lockedUntil := now.Add(i.lease + storeTimeout)
+		record := &models.IdempotencyRecord{
+			Principal:   auth.AnonymousID,
+			Key:         key,
+			RequestHash: requestHash(c.Request(), body),
+			CreatedAt:   now,
+			ExpiresAt:   lockedUntil,
+			LockedUntil: lockedUntil,
+		}
+
+		if principal := auth.Principal(c); principal != nil {
+			record.Principal = principal.ID
+		}
+
+		existing, err := i.repo.ClaimIdempotencyKey(c.Request().Context(), record)
+		if err != nil {
+			i.logger.Error("Unable to claim idempotency key", zap.Error(err))
+			return problem.New(http.StatusInternalServerError, "internal_server_error", "internal server error")
+		}
+
+		if existing != nil {
+			return replay(c, record, existing)
+		}
+
+		return i.handle(c, next, record)
+	}
+}
+
+// handle passes request to the handler within the lease and stores the response it got.
+func (i *Idempotency) handle(c echo.Context, next echo.HandlerFunc, record *models.IdempotencyRecord) error {
+	recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+	c.Response().Writer = recorder
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), i.lease)
+	defer cancel()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	stored := false
+
+	defer func() {
+		c.Response().Writer = recorder.ResponseWriter
+
+		if stored {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+		defer cancel()
+
+		if err := i.repo.ReleaseIdempotencyKey(ctx, record); err != nil {
+			i.logger.Error("Unable to release idempotency key", zap.Error(err))
+		}
+	}()
+
+	// Error is written here instead of by echo, so its response is stored as well.
+	if err := next(c); err != nil {
+		c.Error(err)
+	}
+
+	if !c.Response().Committed || c.Response().Status >= http.StatusInternalServerError {
+		return nil
+	}
+
+	record.Status = c.Response().Status
+	record.ContentType = c.Response().Header().Get(echo.HeaderContentType)
+	record.Body = recorder.body.Bytes()
+	record.ExpiresAt = time.Now().Add(i.ttl)
+
+	storeCtx, storeCancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer storeCancel()
+
+	if err := i.repo.SaveIdempotencyKey(storeCtx, record); err != nil {
+		i.logger.Error("Unable to save idempotent response", zap.Error(err))
+		return nil
+	}
+
+	stored = true
+
+	return nil
+}
+
+func (i *Idempotency) run(ctx context.Context) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			i.Purge(ctx)
+		}
+	}
+}
+
+// Purge deletes expired keys.
+func (i *Idempotency) Purge(ctx context.Context) {
+	count, err := i.repo.PurgeIdempotencyKeys(ctx, time.Now())
+	if err != nil {
+		if ctx.Err() == nil {
+			i.logger.Error("Error while purging idempotency keys", zap.Error(err))
+		}
+
+		return
+	}
+
+	if count > 0 {
+		i.logger.Info("Idempotency keys purged", zap.Int64("count", count))
+	}
+}
+
+// replay writes response stored for the key if it was used for the same request.
+func replay(c echo.Context, record, existing *models.IdempotencyRecord) error {
+	if existing.RequestHash != record.RequestHash {
+		return problem.New(http.StatusConflict, "idempotency_key_reused", errs.ErrIdempotencyKeyReused.Error())
+	}
+
+	if existing.Status == 0 {
+		return problem.New(http.StatusConflict, "idempotency_key_in_progress", errs.ErrIdempotencyKeyInProgress.Error())
+	}
+
+	c.Response().Header().Set(HeaderReplayed, "true")
+
+	if len(existing.Body) == 0 {
+		return c.NoContent(existing.Status)
+	}
+
+	return c.Blob(existing.Status, existing.ContentType, existing.Body)
+}
+
+// requestHash identifies request by its method, uri and body.
+func requestHash(req *http.Request, body []byte) string {
+	hash := sha256.New()
+
+	hash.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of response body written through it.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
@@ -0,0 +1,202 @@
+package idempotency_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/auth"
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/idempotency"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+const testResponse = `{"userID":"d9b4e1c4-3b1f-4f1e-9d4b-6c3f0a2e7b1a"}`
+
+func TestIdempotency_Idempotent(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name         string
+		key          string
+		handlerError error
+
+		// existing returns record the key is taken by for the claimed one, nil if key is free.
+		existing func(record *models.IdempotencyRecord) *models.IdempotencyRecord
+
+		expectedStatusCode int
+		expectedBody       string
+		expectedReplay     bool
+
+		expectingClaimCall   bool
+		expectingSaveCall    bool
+		expectingReleaseCall bool
+		expectingHandlerCall bool
+	}{
+		{
+			name:                 "No key",
+			expectedStatusCode:   http.StatusOK,
+			expectedBody:         testResponse,
+			expectingHandlerCall: true,
+		},
+		{
+			name:                 "First request",
+			key:                  "retry-1",
+			existing:             func(*models.IdempotencyRecord) *models.IdempotencyRecord { return nil },
+			expectedStatusCode:   http.StatusOK,
+			expectedBody:         testResponse,
+			expectingClaimCall:   true,
+			expectingSaveCall:    true,
+			expectingHandlerCall: true,
+		},
+		{
+			name: "Retried request",
+			key:  "retry-1",
+			existing: func(record *models.IdempotencyRecord) *models.IdempotencyRecord {
+				return &models.IdempotencyRecord{
+					RequestHash: record.RequestHash,
+					Status:      http.StatusOK,
+					ContentType: echo.MIMEApplicationJSON,
+					Body:        []byte(testResponse),
+				}
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       testResponse,
+			expectedReplay:     true,
+			expectingClaimCall: true,
+		},
+		{
+			name: "Key used with another request",
+			key:  "retry-1",
+			existing: func(record *models.IdempotencyRecord) *models.IdempotencyRecord {
+				return &models.IdempotencyRecord{RequestHash: "other", Status: http.StatusOK}
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectingClaimCall: true,
+		},
+		{
+			name: "Request in progress",
+			key:  "retry-1",
+			existing: func(record *models.IdempotencyRecord) *models.IdempotencyRecord {
+				return &models.IdempotencyRecord{RequestHash: record.RequestHash}
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectingClaimCall: true,
+		},
+		{
+			name:                 "Client error is stored",
+			key:                  "retry-1",
+			handlerError:         echo.NewHTTPError(http.StatusBadRequest, "invalid user id"),
+			existing:             func(*models.IdempotencyRecord) *models.IdempotencyRecord { return nil },
+			expectedStatusCode:   http.StatusBadRequest,
+			expectingClaimCall:   true,
+			expectingSaveCall:    true,
+			expectingHandlerCall: true,
+		},
+		{
+			name:                 "Server error releases key",
+			key:                  "retry-1",
+			handlerError:         echo.NewHTTPError(http.StatusInternalServerError, "internal server error"),
+			existing:             func(*models.IdempotencyRecord) *models.IdempotencyRecord { return nil },
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectingClaimCall:   true,
+			expectingReleaseCall: true,
+			expectingHandlerCall: true,
+		},
+		{
+			name:               "Too long key",
+			key:                strings.Repeat("k", idempotency.MaxKeyLength+1),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := NewMockRepository(ctrl)
+
+			if tc.expectingClaimCall {
+				repo.EXPECT().ClaimIdempotencyKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+						a.Equal(auth.AnonymousID, record.Principal)
+						a.Equal(tc.key, record.Key)
+						a.NotEmpty(record.RequestHash)
+						a.True(record.LockedUntil.After(record.CreatedAt))
+						a.Equal(record.LockedUntil, record.ExpiresAt, "Claimed key should expire with its lease")
+
+						return tc.existing(record), nil
+					})
+			}
+
+			if tc.expectingSaveCall {
+				repo.EXPECT().SaveIdempotencyKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, record *models.IdempotencyRecord) error {
+						a.Equal(tc.expectedStatusCode, record.Status)
+						a.NotEmpty(record.Body)
+						a.True(record.ExpiresAt.After(record.LockedUntil.Add(time.Hour)), "Stored response should be kept for ttl")
+
+						return nil
+					})
+			}
+
+			if tc.expectingReleaseCall {
+				repo.EXPECT().ReleaseIdempotencyKey(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, record *models.IdempotencyRecord) error {
+						a.Equal(auth.AnonymousID, record.Principal)
+						a.Equal(tc.key, record.Key)
+						a.False(record.LockedUntil.IsZero())
+
+						return nil
+					})
+			}
+
+			zp, _ := zap.NewDevelopment()
+			middleware := idempotency.New(repo, &config.Config{}, zp)
+
+			handlerCalled := false
+
+			authenticator, err := auth.New(nil, &config.Config{}, zp)
+			a.NoError(err)
+
+			e := echo.New()
+			e.POST("/user", func(c echo.Context) error {
+				handlerCalled = true
+
+				if tc.handlerError != nil {
+					return tc.handlerError
+				}
+
+				return c.JSONBlob(http.StatusOK, []byte(testResponse))
+			}, authenticator.Authenticate, middleware.Idempotent)
+
+			req := httptest.NewRequest(http.MethodPost, "/user", strings.NewReader(`{"userID":"d9b4e1c4-3b1f-4f1e-9d4b-6c3f0a2e7b1a"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			if tc.key != "" {
+				req.Header.Set(idempotency.HeaderIdempotencyKey, tc.key)
+			}
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			a.Equal(tc.expectedStatusCode, rec.Code, "Wrong status code")
+			a.Equal(tc.expectingHandlerCall, handlerCalled)
+			a.Equal(tc.expectedReplay, rec.Header().Get(idempotency.HeaderReplayed) == "true")
+
+			if tc.expectedBody != "" {
+				a.Equal(tc.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: idempotency.go
+
+// Package idempotency_test is a generated GoMock package.
+package idempotency_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/dupreehkuda/avito-segments/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimIdempotencyKey mocks base method.
+func (m *MockRepository) ClaimIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimIdempotencyKey", ctx, record)
+	ret0, _ := ret[0].(*models.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimIdempotencyKey indicates an expected call of ClaimIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) ClaimIdempotencyKey(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).ClaimIdempotencyKey), ctx, record)
+}
+
+// PurgeIdempotencyKeys mocks base method.
+func (m *MockRepository) PurgeIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeIdempotencyKeys", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeIdempotencyKeys indicates an expected call of PurgeIdempotencyKeys.
+func (mr *MockRepositoryMockRecorder) PurgeIdempotencyKeys(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeIdempotencyKeys", reflect.TypeOf((*MockRepository)(nil).PurgeIdempotencyKeys), ctx, before)
+}
+
+// ReleaseIdempotencyKey mocks base method.
+func (m *MockRepository) ReleaseIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotencyKey", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotencyKey indicates an expected call of ReleaseIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) ReleaseIdempotencyKey(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).ReleaseIdempotencyKey), ctx, record)
+}
+
+// SaveIdempotencyKey mocks base method.
+func (m *MockRepository) SaveIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveIdempotencyKey", ctx, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotencyKey indicates an expected call of SaveIdempotencyKey.
+func (mr *MockRepositoryMockRecorder) SaveIdempotencyKey(ctx, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotencyKey", reflect.TypeOf((*MockRepository)(nil).SaveIdempotencyKey), ctx, record)
+}
@@ -1,16 +1,78 @@
 //go:generate easyjson -no_std_marshalers models.go
 package models
 
-import "time"
+import (
+	"io"
+	"time"
+
+	"github.com/mailru/easyjson"
+)
 
 //easyjson:json
 type (
 	Segment struct {
 		Slug        string `json:"slug"`
 		Description string `json:"description,omitempty"`
+		Percentage  int    `json:"percentage,omitempty"`
 		DeletedAt   time.Time
 	}
 
+	SegmentInfo struct {
+		Slug        string     `json:"slug"`
+		Description string     `json:"description,omitempty"`
+		Percentage  int        `json:"percentage,omitempty"`
+		CreatedAt   time.Time  `json:"createdAt"`
+		DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+		Members     int        `json:"members"`
+	}
+
+	SegmentListResponse struct {
+		Segments []SegmentInfo `json:"segments"`
+		Total    int           `json:"total"`
+		Limit    int           `json:"limit"`
+		Offset   int           `json:"offset"`
+	}
+
+	SegmentStats struct {
+		Slug      string            `json:"slug"`
+		Active    int               `json:"active"`
+		From      time.Time         `json:"from"`
+		To        time.Time         `json:"to"`
+		Added     int               `json:"added"`
+		Deleted   int               `json:"deleted"`
+		Expired   int               `json:"expired"`
+		NetGrowth int               `json:"netGrowth"`
+		Days      []SegmentDayStats `json:"days"`
+	}
+
+	SegmentDayStats struct {
+		Day     time.Time `json:"day"`
+		Added   int       `json:"added"`
+		Deleted int       `json:"deleted"`
+		Expired int       `json:"expired"`
+		Net     int       `json:"net"`
+	}
+
+	SegmentMember struct {
+		UserID    string     `json:"userID"`
+		CreatedAt time.Time  `json:"createdAt"`
+		ExpiredAt *time.Time `json:"expiredAt,omitempty"`
+	}
+
+	SegmentMembersResponse struct {
+		Users      []SegmentMember `json:"users"`
+		NextCursor string          `json:"nextCursor,omitempty"`
+	}
+
+	SegmentUpdateRequest struct {
+		Description *string `json:"description,omitempty"`
+		Percentage  *int    `json:"percentage,omitempty"`
+	}
+
+	SegmentRestoreRequest struct {
+		RestoreMembers bool `json:"restoreMembers"`
+	}
+
 	UserSegment struct {
 		Slug   string    `json:"slug"`
 		Expire time.Time `json:"expire,omitempty"`
@@ -26,11 +88,61 @@ type (
 		Slugs  []string `json:"slugs"`
 	}
 
+	UserUpdateRequest struct {
+		UserID string        `json:"-"`
+		Add    []UserSegment `json:"add"`
+		Remove []string      `json:"remove"`
+	}
+
+	UserBulkRequest struct {
+		Users []UserSetRequest `json:"users"`
+	}
+
+	// UserBulkResult is the outcome of a bulk entry. Failed entry carries message, code and details of its problem.
+	UserBulkResult struct {
+		Index   int             `json:"index"`
+		UserID  string          `json:"userID"`
+		Error   string          `json:"error,omitempty"`
+		Code    string          `json:"code,omitempty"`
+		Details *ProblemDetails `json:"details,omitempty"`
+	}
+
+	UserBulkResponse struct {
+		Succeeded int              `json:"succeeded"`
+		Failed    int              `json:"failed"`
+		Results   []UserBulkResult `json:"results"`
+	}
+
 	UserResponse struct {
 		UserID string   `json:"userID"`
 		Slugs  []string `json:"slugs"`
 	}
 
+	ImportJob struct {
+		ID         string           `json:"id"`
+		Slug       string           `json:"slug"`
+		Status     string           `json:"status"`
+		Total      int              `json:"total"`
+		Processed  int              `json:"processed"`
+		Failed     int              `json:"failed"`
+		Error      string           `json:"error,omitempty"`
+		CreatedAt  time.Time        `json:"createdAt"`
+		StartedAt  *time.Time       `json:"startedAt,omitempty"`
+		FinishedAt *time.Time       `json:"finishedAt,omitempty"`
+		Errors     []ImportRowError `json:"errors,omitempty"`
+		// Audit describes who started the import, memberships written by the job are recorded with it.
+		Audit *AuditEntry `json:"-"`
+	}
+
+	// ImportRowError is a rejected row of import file with message, code and details of its problem.
+	ImportRowError struct {
+		Row     int             `json:"row"`
+		UserID  string          `json:"userID"`
+		Error   string          `json:"error"`
+		Code    string          `json:"code"`
+		Details *ProblemDetails `json:"details,omitempty"`
+	}
+
 	ReportRow struct {
 		UserID    string    `json:"userID"`
 		Slug      string    `json:"slug"`
@@ -38,12 +150,326 @@ type (
 		Timestamp time.Time `json:"timestamp"`
 	}
 
+	// ReportFilter selects membership events for report. Period is either a month or [From, To) range.
+	ReportFilter struct {
+		Year    int       `json:"year,omitempty"`
+		Month   int       `json:"month,omitempty"`
+		From    time.Time `json:"from"`
+		To      time.Time `json:"to"`
+		Slugs   []string  `json:"slugs,omitempty"`
+		UserIDs []string  `json:"userIDs,omitempty"`
+		Methods []string  `json:"methods,omitempty"`
+	}
+
 	ReportRequest struct {
-		Year  int `json:"year"`
-		Month int `json:"month"`
+		ReportFilter
+		Format string `json:"format,omitempty"`
+		Force  bool   `json:"force,omitempty"`
 	}
 
 	ReportResponse struct {
 		Link string `json:"link"`
 	}
+
+	ReportJob struct {
+		ID       string `json:"id"`
+		ReportID string `json:"reportID"`
+		ReportFilter
+		Format      string     `json:"format"`
+		Status      string     `json:"status"`
+		Rows        int        `json:"rows"`
+		GeneratedAt *time.Time `json:"generatedAt,omitempty"`
+		File        string     `json:"-"`
+		Link        string     `json:"link,omitempty"`
+		Error       string     `json:"error,omitempty"`
+		CreatedAt   time.Time  `json:"createdAt"`
+		StartedAt   *time.Time `json:"startedAt,omitempty"`
+		FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+	}
+
+	// OutboxEvent is a membership or segment catalog change written along with it and published later.
+	OutboxEvent struct {
+		ID        int64      `json:"id"`
+		Kind      string     `json:"kind"`
+		UserID    string     `json:"userID,omitempty"`
+		Slug      string     `json:"slug"`
+		Method    string     `json:"method"`
+		ExpireAt  *time.Time `json:"expireAt,omitempty"`
+		Timestamp time.Time  `json:"timestamp"`
+	}
+
+	WebhookRequest struct {
+		URL    string   `json:"url"`
+		Slugs  []string `json:"slugs"`
+		Secret string   `json:"secret,omitempty"`
+	}
+
+	// Webhook is a subscription to membership changes in segments. Secret is shown only on creation.
+	Webhook struct {
+		ID        string    `json:"id"`
+		URL       string    `json:"url"`
+		Slugs     []string  `json:"slugs"`
+		Secret    string    `json:"secret,omitempty"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+
+	WebhookListResponse struct {
+		Webhooks []Webhook `json:"webhooks"`
+	}
+
+	// WebhookDelivery is a single event to be sent to webhook along with its delivery state.
+	WebhookDelivery struct {
+		ID            int64       `json:"id"`
+		WebhookID     string      `json:"webhookID"`
+		Event         OutboxEvent `json:"event"`
+		Status        string      `json:"status"`
+		Attempts      int         `json:"attempts"`
+		NextAttemptAt *time.Time  `json:"nextAttemptAt,omitempty"`
+		LastStatus    int         `json:"lastStatus,omitempty"`
+		LastError     string      `json:"lastError,omitempty"`
+		CreatedAt     time.Time   `json:"createdAt"`
+		DeliveredAt   *time.Time  `json:"deliveredAt,omitempty"`
+		URL           string      `json:"-"`
+		Secret        string      `json:"-"`
+	}
+
+	WebhookDeliveriesResponse struct {
+		Deliveries []WebhookDelivery `json:"deliveries"`
+		Total      int               `json:"total"`
+		Limit      int               `json:"limit"`
+		Offset     int               `json:"offset"`
+	}
+
+	APIKeyRequest struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+
+	// APIKey is a client's key with the scopes it grants. Key itself is only known when it is created.
+	APIKey struct {
+		ID        string     `json:"id"`
+		Name      string     `json:"name"`
+		Prefix    string     `json:"prefix"`
+		Scopes    []string   `json:"scopes"`
+		Key       string     `json:"key,omitempty"`
+		CreatedAt time.Time  `json:"createdAt"`
+		RevokedAt *time.Time `json:"revokedAt,omitempty"`
+		Hash      string     `json:"-"`
+	}
+
+	APIKeyListResponse struct {
+		Keys []APIKey `json:"keys"`
+	}
+
+	// AuditEntry is a mutation made by an actor along with state it changed.
+	AuditEntry struct {
+		ID        int64               `json:"id"`
+		Actor     string              `json:"actor"`
+		ActorName string              `json:"actorName,omitempty"`
+		RequestID string              `json:"requestID,omitempty"`
+		Operation string              `json:"operation"`
+		Slugs     []string            `json:"slugs,omitempty"`
+		UserID    string              `json:"userID,omitempty"`
+		Before    easyjson.RawMessage `json:"before,omitempty"`
+		After     easyjson.RawMessage `json:"after,omitempty"`
+		CreatedAt time.Time           `json:"createdAt"`
+	}
+
+	AuditLogResponse struct {
+		Entries []AuditEntry `json:"entries"`
+		Total   int          `json:"total"`
+		Limit   int          `json:"limit"`
+		Offset  int          `json:"offset"`
+	}
+
+	// Problem is an error response as of RFC 7807. Code tells errors apart and does not change.
+	Problem struct {
+		Type      string          `json:"type"`
+		Title     string          `json:"title"`
+		Status    int             `json:"status"`
+		Detail    string          `json:"detail,omitempty"`
+		Instance  string          `json:"instance,omitempty"`
+		Code      string          `json:"code"`
+		RequestID string          `json:"requestID,omitempty"`
+		Details   *ProblemDetails `json:"details,omitempty"`
+	}
+
+	// ProblemDetails lists entries of the request that caused the problem.
+	ProblemDetails struct {
+		Slugs   []string `json:"slugs,omitempty"`
+		UserIDs []string `json:"userIDs,omitempty"`
+	}
+)
+
+// Membership history methods.
+const (
+	MethodAdded   = "added"
+	MethodUpdated = "updated"
+	MethodDeleted = "deleted"
+	MethodExpired = "expired"
 )
+
+// Segment catalog history methods.
+const (
+	SegmentCreated  = "created"
+	SegmentDeleted  = "deleted"
+	SegmentRestored = "restored"
+)
+
+// Outbox event kinds.
+const (
+	EventMembership = "membership"
+	EventSegment    = "segment"
+)
+
+// Webhook delivery statuses. Dead deliveries exhausted their attempts and form the dead-letter list.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// Permission scopes granted to clients.
+const (
+	ScopeSegmentsRead  = "segments:read"
+	ScopeSegmentsWrite = "segments:write"
+	ScopeUsersRead     = "users:read"
+	ScopeUsersWrite    = "users:write"
+	ScopeReportsRead   = "reports:read"
+	ScopeWebhooksWrite = "webhooks:write"
+	ScopeAuditRead     = "audit:read"
+	ScopeAdmin         = "admin"
+)
+
+// Scopes lists all permission scopes.
+var Scopes = []string{
+	ScopeSegmentsRead, ScopeSegmentsWrite, ScopeUsersRead, ScopeUsersWrite,
+	ScopeReportsRead, ScopeWebhooksWrite, ScopeAuditRead, ScopeAdmin,
+}
+
+// Audited operations.
+const (
+	AuditSegmentAdd          = "segment.add"
+	AuditSegmentDelete       = "segment.delete"
+	AuditSegmentUpdate       = "segment.update"
+	AuditSegmentRestore      = "segment.restore"
+	AuditSegmentImport       = "segment.import"
+	AuditUserSetSegments     = "user.setSegments"
+	AuditUserDeleteSegments  = "user.deleteSegments"
+	AuditUserUpdateSegments  = "user.updateSegments"
+	AuditUserBulkSetSegments = "user.bulkSetSegments"
+	AuditAPIKeyCreate        = "apiKey.create"
+	AuditAPIKeyRevoke        = "apiKey.revoke"
+	AuditWebhookCreate       = "webhook.create"
+	AuditWebhookDelete       = "webhook.delete"
+)
+
+// Background job statuses.
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+// Export and report formats.
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// ReportCursor iterates over report rows as they are read from database.
+// Close must be called to release resources held by the cursor.
+type ReportCursor interface {
+	Next() bool
+	Row() ReportRow
+	Err() error
+	Close()
+}
+
+// ReportFile is either stored report contents or a direct link to it.
+type ReportFile struct {
+	Name    string
+	URL     string
+	Content io.ReadCloser
+}
+
+// Segment statuses used to filter segment catalog.
+const (
+	SegmentStatusActive  = "active"
+	SegmentStatusDeleted = "deleted"
+	SegmentStatusAll     = "all"
+)
+
+// DeliveryFilter describes a page of webhook delivery log.
+type DeliveryFilter struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+// SegmentFilter describes a page of segment catalog.
+type SegmentFilter struct {
+	Status string
+	Prefix string
+	Limit  int
+	Offset int
+}
+
+// EventPosition is a place in the event stream. Events are ordered by writing transaction and then by id.
+type EventPosition struct {
+	Tx int64
+	ID int64
+}
+
+// StreamEvent is an outbox event along with its place in the event stream.
+// ID is the place formatted for clients to resume the stream from.
+type StreamEvent struct {
+	ID       string
+	Position EventPosition
+	Event    OutboxEvent
+}
+
+// EventFilter selects events of the stream following the position.
+// Segment catalog events have no user, so they never match a filter by user.
+type EventFilter struct {
+	Slugs   []string
+	UserIDs []string
+	After   EventPosition
+	Limit   int
+}
+
+// Principal is an authenticated client along with the scopes it was granted.
+type Principal struct {
+	ID     string
+	Name   string
+	Scopes []string
+}
+
+// AuditFilter describes a page of audit log. Empty fields do not filter.
+type AuditFilter struct {
+	Actor  string
+	Slug   string
+	UserID string
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+// IdempotencyRecord is a request made with idempotency key along with the response it got.
+// Status is zero while the request is being handled.
+type IdempotencyRecord struct {
+	Principal   string
+	Key         string
+	RequestHash string
+	Status      int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	// LockedUntil is the end of the lease of the request holding the key, zero once its response is stored.
+	LockedUntil time.Time
+}
@@ -7,6 +7,7 @@ import (
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
 )
 
 // suppress unused package warning
@@ -17,7 +18,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels(in *jlexer.Lexer, out *UserSetRequest) {
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels(in *jlexer.Lexer, out *WebhookRequest) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -36,31 +37,33 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels(in *j
 			continue
 		}
 		switch key {
-		case "userID":
-			out.UserID = string(in.String())
-		case "segments":
+		case "url":
+			out.URL = string(in.String())
+		case "slugs":
 			if in.IsNull() {
 				in.Skip()
-				out.Segments = nil
+				out.Slugs = nil
 			} else {
 				in.Delim('[')
-				if out.Segments == nil {
+				if out.Slugs == nil {
 					if !in.IsDelim(']') {
-						out.Segments = make([]UserSegment, 0, 1)
+						out.Slugs = make([]string, 0, 4)
 					} else {
-						out.Segments = []UserSegment{}
+						out.Slugs = []string{}
 					}
 				} else {
-					out.Segments = (out.Segments)[:0]
+					out.Slugs = (out.Slugs)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v1 UserSegment
-					(v1).UnmarshalEasyJSON(in)
-					out.Segments = append(out.Segments, v1)
+					var v1 string
+					v1 = string(in.String())
+					out.Slugs = append(out.Slugs, v1)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
+		case "secret":
+			out.Secret = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -71,44 +74,49 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels(in *j
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels(out *jwriter.Writer, in UserSetRequest) {
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels(out *jwriter.Writer, in WebhookRequest) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"userID\":"
+		const prefix string = ",\"url\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.UserID))
+		out.String(string(in.URL))
 	}
 	{
-		const prefix string = ",\"segments\":"
+		const prefix string = ",\"slugs\":"
 		out.RawString(prefix)
-		if in.Segments == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		if in.Slugs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v2, v3 := range in.Segments {
+			for v2, v3 := range in.Slugs {
 				if v2 > 0 {
 					out.RawByte(',')
 				}
-				(v3).MarshalEasyJSON(out)
+				out.String(string(v3))
 			}
 			out.RawByte(']')
 		}
 	}
+	if in.Secret != "" {
+		const prefix string = ",\"secret\":"
+		out.RawString(prefix)
+		out.String(string(in.Secret))
+	}
 	out.RawByte('}')
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v UserSetRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v WebhookRequest) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels(w, v)
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *UserSetRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *WebhookRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels(l, v)
 }
-func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels1(in *jlexer.Lexer, out *UserSegment) {
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels1(in *jlexer.Lexer, out *WebhookListResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -127,11 +135,28 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels1(in *
 			continue
 		}
 		switch key {
-		case "slug":
-			out.Slug = string(in.String())
-		case "expire":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Expire).UnmarshalJSON(data))
+		case "webhooks":
+			if in.IsNull() {
+				in.Skip()
+				out.Webhooks = nil
+			} else {
+				in.Delim('[')
+				if out.Webhooks == nil {
+					if !in.IsDelim(']') {
+						out.Webhooks = make([]Webhook, 0, 0)
+					} else {
+						out.Webhooks = []Webhook{}
+					}
+				} else {
+					out.Webhooks = (out.Webhooks)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 Webhook
+					(v4).UnmarshalEasyJSON(in)
+					out.Webhooks = append(out.Webhooks, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
 			}
 		default:
 			in.SkipRecursive()
@@ -143,33 +168,39 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels1(in *
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels1(out *jwriter.Writer, in UserSegment) {
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels1(out *jwriter.Writer, in WebhookListResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"slug\":"
+		const prefix string = ",\"webhooks\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Slug))
-	}
-	if true {
-		const prefix string = ",\"expire\":"
-		out.RawString(prefix)
-		out.Raw((in.Expire).MarshalJSON())
+		if in.Webhooks == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.Webhooks {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				(v6).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v UserSegment) MarshalEasyJSON(w *jwriter.Writer) {
+func (v WebhookListResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels1(w, v)
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *UserSegment) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *WebhookListResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels1(l, v)
 }
-func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels2(in *jlexer.Lexer, out *UserResponse) {
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels2(in *jlexer.Lexer, out *WebhookDelivery) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -188,30 +219,47 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels2(in *
 			continue
 		}
 		switch key {
-		case "userID":
-			out.UserID = string(in.String())
-		case "slugs":
+		case "id":
+			out.ID = int64(in.Int64())
+		case "webhookID":
+			out.WebhookID = string(in.String())
+		case "event":
+			(out.Event).UnmarshalEasyJSON(in)
+		case "status":
+			out.Status = string(in.String())
+		case "attempts":
+			out.Attempts = int(in.Int())
+		case "nextAttemptAt":
 			if in.IsNull() {
 				in.Skip()
-				out.Slugs = nil
+				out.NextAttemptAt = nil
 			} else {
-				in.Delim('[')
-				if out.Slugs == nil {
-					if !in.IsDelim(']') {
-						out.Slugs = make([]string, 0, 4)
-					} else {
-						out.Slugs = []string{}
-					}
-				} else {
-					out.Slugs = (out.Slugs)[:0]
+				if out.NextAttemptAt == nil {
+					out.NextAttemptAt = new(time.Time)
 				}
-				for !in.IsDelim(']') {
-					var v4 string
-					v4 = string(in.String())
-					out.Slugs = append(out.Slugs, v4)
-					in.WantComma()
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.NextAttemptAt).UnmarshalJSON(data))
+				}
+			}
+		case "lastStatus":
+			out.LastStatus = int(in.Int())
+		case "lastError":
+			out.LastError = string(in.String())
+		case "createdAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		case "deliveredAt":
+			if in.IsNull() {
+				in.Skip()
+				out.DeliveredAt = nil
+			} else {
+				if out.DeliveredAt == nil {
+					out.DeliveredAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.DeliveredAt).UnmarshalJSON(data))
 				}
-				in.Delim(']')
 			}
 		default:
 			in.SkipRecursive()
@@ -223,44 +271,73 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels2(in *
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels2(out *jwriter.Writer, in UserResponse) {
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels2(out *jwriter.Writer, in WebhookDelivery) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"userID\":"
+		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.UserID))
+		out.Int64(int64(in.ID))
 	}
 	{
-		const prefix string = ",\"slugs\":"
+		const prefix string = ",\"webhookID\":"
 		out.RawString(prefix)
-		if in.Slugs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-			out.RawString("null")
-		} else {
-			out.RawByte('[')
-			for v5, v6 := range in.Slugs {
-				if v5 > 0 {
-					out.RawByte(',')
-				}
-				out.String(string(v6))
-			}
-			out.RawByte(']')
-		}
+		out.String(string(in.WebhookID))
+	}
+	{
+		const prefix string = ",\"event\":"
+		out.RawString(prefix)
+		(in.Event).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"attempts\":"
+		out.RawString(prefix)
+		out.Int(int(in.Attempts))
+	}
+	if in.NextAttemptAt != nil {
+		const prefix string = ",\"nextAttemptAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.NextAttemptAt).MarshalJSON())
+	}
+	if in.LastStatus != 0 {
+		const prefix string = ",\"lastStatus\":"
+		out.RawString(prefix)
+		out.Int(int(in.LastStatus))
+	}
+	if in.LastError != "" {
+		const prefix string = ",\"lastError\":"
+		out.RawString(prefix)
+		out.String(string(in.LastError))
+	}
+	{
+		const prefix string = ",\"createdAt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	if in.DeliveredAt != nil {
+		const prefix string = ",\"deliveredAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.DeliveredAt).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v UserResponse) MarshalEasyJSON(w *jwriter.Writer) {
+func (v WebhookDelivery) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels2(w, v)
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *UserResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *WebhookDelivery) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels2(l, v)
 }
-func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels3(in *jlexer.Lexer, out *UserDeleteRequest) {
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels3(in *jlexer.Lexer, out *WebhookDeliveriesResponse) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -279,31 +356,35 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels3(in *
 			continue
 		}
 		switch key {
-		case "userID":
-			out.UserID = string(in.String())
-		case "slugs":
+		case "deliveries":
 			if in.IsNull() {
 				in.Skip()
-				out.Slugs = nil
+				out.Deliveries = nil
 			} else {
 				in.Delim('[')
-				if out.Slugs == nil {
+				if out.Deliveries == nil {
 					if !in.IsDelim(']') {
-						out.Slugs = make([]string, 0, 4)
+						out.Deliveries = make([]WebhookDelivery, 0, 0)
 					} else {
-						out.Slugs = []string{}
+						out.Deliveries = []WebhookDelivery{}
 					}
 				} else {
-					out.Slugs = (out.Slugs)[:0]
+					out.Deliveries = (out.Deliveries)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v7 string
-					v7 = string(in.String())
-					out.Slugs = append(out.Slugs, v7)
+					var v7 WebhookDelivery
+					(v7).UnmarshalEasyJSON(in)
+					out.Deliveries = append(out.Deliveries, v7)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
+		case "total":
+			out.Total = int(in.Int())
+		case "limit":
+			out.Limit = int(in.Int())
+		case "offset":
+			out.Offset = int(in.Int())
 		default:
 			in.SkipRecursive()
 		}
@@ -314,44 +395,54 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels3(in *
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels3(out *jwriter.Writer, in UserDeleteRequest) {
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels3(out *jwriter.Writer, in WebhookDeliveriesResponse) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"userID\":"
+		const prefix string = ",\"deliveries\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.UserID))
-	}
-	{
-		const prefix string = ",\"slugs\":"
-		out.RawString(prefix)
-		if in.Slugs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		if in.Deliveries == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v8, v9 := range in.Slugs {
+			for v8, v9 := range in.Deliveries {
 				if v8 > 0 {
 					out.RawByte(',')
 				}
-				out.String(string(v9))
+				(v9).MarshalEasyJSON(out)
 			}
 			out.RawByte(']')
 		}
 	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int(int(in.Total))
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"offset\":"
+		out.RawString(prefix)
+		out.Int(int(in.Offset))
+	}
 	out.RawByte('}')
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v UserDeleteRequest) MarshalEasyJSON(w *jwriter.Writer) {
+func (v WebhookDeliveriesResponse) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels3(w, v)
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *UserDeleteRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *WebhookDeliveriesResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels3(l, v)
 }
-func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels4(in *jlexer.Lexer, out *Segment) {
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels4(in *jlexer.Lexer, out *Webhook) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -370,13 +461,38 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels4(in *
 			continue
 		}
 		switch key {
-		case "slug":
-			out.Slug = string(in.String())
-		case "description":
-			out.Description = string(in.String())
-		case "DeletedAt":
+		case "id":
+			out.ID = string(in.String())
+		case "url":
+			out.URL = string(in.String())
+		case "slugs":
+			if in.IsNull() {
+				in.Skip()
+				out.Slugs = nil
+			} else {
+				in.Delim('[')
+				if out.Slugs == nil {
+					if !in.IsDelim(']') {
+						out.Slugs = make([]string, 0, 4)
+					} else {
+						out.Slugs = []string{}
+					}
+				} else {
+					out.Slugs = (out.Slugs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 string
+					v10 = string(in.String())
+					out.Slugs = append(out.Slugs, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "secret":
+			out.Secret = string(in.String())
+		case "createdAt":
 			if data := in.Raw(); in.Ok() {
-				in.AddError((out.DeletedAt).UnmarshalJSON(data))
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
 			}
 		default:
 			in.SkipRecursive()
@@ -388,38 +504,59 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels4(in *
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels4(out *jwriter.Writer, in Segment) {
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels4(out *jwriter.Writer, in Webhook) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"slug\":"
+		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Slug))
+		out.String(string(in.ID))
 	}
-	if in.Description != "" {
-		const prefix string = ",\"description\":"
+	{
+		const prefix string = ",\"url\":"
 		out.RawString(prefix)
-		out.String(string(in.Description))
+		out.String(string(in.URL))
 	}
 	{
-		const prefix string = ",\"DeletedAt\":"
+		const prefix string = ",\"slugs\":"
 		out.RawString(prefix)
-		out.Raw((in.DeletedAt).MarshalJSON())
+		if in.Slugs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.Slugs {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v12))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Secret != "" {
+		const prefix string = ",\"secret\":"
+		out.RawString(prefix)
+		out.String(string(in.Secret))
+	}
+	{
+		const prefix string = ",\"createdAt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Segment) MarshalEasyJSON(w *jwriter.Writer) {
+func (v Webhook) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels4(w, v)
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Segment) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *Webhook) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels4(l, v)
 }
-func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels5(in *jlexer.Lexer, out *ReportRow) {
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels5(in *jlexer.Lexer, out *UserUpdateRequest) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -438,15 +575,51 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels5(in *
 			continue
 		}
 		switch key {
-		case "userID":
-			out.UserID = string(in.String())
-		case "slug":
-			out.Slug = string(in.String())
-		case "method":
-			out.Method = string(in.String())
-		case "timestamp":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Timestamp).UnmarshalJSON(data))
+		case "add":
+			if in.IsNull() {
+				in.Skip()
+				out.Add = nil
+			} else {
+				in.Delim('[')
+				if out.Add == nil {
+					if !in.IsDelim(']') {
+						out.Add = make([]UserSegment, 0, 1)
+					} else {
+						out.Add = []UserSegment{}
+					}
+				} else {
+					out.Add = (out.Add)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 UserSegment
+					(v13).UnmarshalEasyJSON(in)
+					out.Add = append(out.Add, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "remove":
+			if in.IsNull() {
+				in.Skip()
+				out.Remove = nil
+			} else {
+				in.Delim('[')
+				if out.Remove == nil {
+					if !in.IsDelim(']') {
+						out.Remove = make([]string, 0, 4)
+					} else {
+						out.Remove = []string{}
+					}
+				} else {
+					out.Remove = (out.Remove)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v14 string
+					v14 = string(in.String())
+					out.Remove = append(out.Remove, v14)
+					in.WantComma()
+				}
+				in.Delim(']')
 			}
 		default:
 			in.SkipRecursive()
@@ -458,43 +631,3024 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels5(in *
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels5(out *jwriter.Writer, in ReportRow) {
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels5(out *jwriter.Writer, in UserUpdateRequest) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"userID\":"
-		out.RawString(prefix[1:])
-		out.String(string(in.UserID))
+		const prefix string = ",\"add\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		if in.Add == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v15, v16 := range in.Add {
+				if v15 > 0 {
+					out.RawByte(',')
+				}
+				(v16).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
 	}
 	{
-		const prefix string = ",\"slug\":"
+		const prefix string = ",\"remove\":"
 		out.RawString(prefix)
-		out.String(string(in.Slug))
+		if in.Remove == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v17, v18 := range in.Remove {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v18))
+			}
+			out.RawByte(']')
+		}
 	}
-	{
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserUpdateRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels5(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserUpdateRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels5(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels6(in *jlexer.Lexer, out *UserSetRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "userID":
+			out.UserID = string(in.String())
+		case "segments":
+			if in.IsNull() {
+				in.Skip()
+				out.Segments = nil
+			} else {
+				in.Delim('[')
+				if out.Segments == nil {
+					if !in.IsDelim(']') {
+						out.Segments = make([]UserSegment, 0, 1)
+					} else {
+						out.Segments = []UserSegment{}
+					}
+				} else {
+					out.Segments = (out.Segments)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 UserSegment
+					(v19).UnmarshalEasyJSON(in)
+					out.Segments = append(out.Segments, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels6(out *jwriter.Writer, in UserSetRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"userID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"segments\":"
+		out.RawString(prefix)
+		if in.Segments == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v20, v21 := range in.Segments {
+				if v20 > 0 {
+					out.RawByte(',')
+				}
+				(v21).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserSetRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels6(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserSetRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels6(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels7(in *jlexer.Lexer, out *UserSegment) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "slug":
+			out.Slug = string(in.String())
+		case "expire":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Expire).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels7(out *jwriter.Writer, in UserSegment) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Slug))
+	}
+	if true {
+		const prefix string = ",\"expire\":"
+		out.RawString(prefix)
+		out.Raw((in.Expire).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserSegment) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels7(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserSegment) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels7(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels8(in *jlexer.Lexer, out *UserResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "userID":
+			out.UserID = string(in.String())
+		case "slugs":
+			if in.IsNull() {
+				in.Skip()
+				out.Slugs = nil
+			} else {
+				in.Delim('[')
+				if out.Slugs == nil {
+					if !in.IsDelim(']') {
+						out.Slugs = make([]string, 0, 4)
+					} else {
+						out.Slugs = []string{}
+					}
+				} else {
+					out.Slugs = (out.Slugs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v22 string
+					v22 = string(in.String())
+					out.Slugs = append(out.Slugs, v22)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels8(out *jwriter.Writer, in UserResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"userID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"slugs\":"
+		out.RawString(prefix)
+		if in.Slugs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v23, v24 := range in.Slugs {
+				if v23 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v24))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels8(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels8(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels9(in *jlexer.Lexer, out *UserDeleteRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "userID":
+			out.UserID = string(in.String())
+		case "slugs":
+			if in.IsNull() {
+				in.Skip()
+				out.Slugs = nil
+			} else {
+				in.Delim('[')
+				if out.Slugs == nil {
+					if !in.IsDelim(']') {
+						out.Slugs = make([]string, 0, 4)
+					} else {
+						out.Slugs = []string{}
+					}
+				} else {
+					out.Slugs = (out.Slugs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v25 string
+					v25 = string(in.String())
+					out.Slugs = append(out.Slugs, v25)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels9(out *jwriter.Writer, in UserDeleteRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"userID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"slugs\":"
+		out.RawString(prefix)
+		if in.Slugs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v26, v27 := range in.Slugs {
+				if v26 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v27))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserDeleteRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels9(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserDeleteRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels9(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels10(in *jlexer.Lexer, out *UserBulkResult) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "index":
+			out.Index = int(in.Int())
+		case "userID":
+			out.UserID = string(in.String())
+		case "error":
+			out.Error = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		case "details":
+			if in.IsNull() {
+				in.Skip()
+				out.Details = nil
+			} else {
+				if out.Details == nil {
+					out.Details = new(ProblemDetails)
+				}
+				(*out.Details).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels10(out *jwriter.Writer, in UserBulkResult) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"index\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Index))
+	}
+	{
+		const prefix string = ",\"userID\":"
+		out.RawString(prefix)
+		out.String(string(in.UserID))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	if in.Code != "" {
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.String(string(in.Code))
+	}
+	if in.Details != nil {
+		const prefix string = ",\"details\":"
+		out.RawString(prefix)
+		(*in.Details).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserBulkResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels10(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserBulkResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels10(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels11(in *jlexer.Lexer, out *UserBulkResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "succeeded":
+			out.Succeeded = int(in.Int())
+		case "failed":
+			out.Failed = int(in.Int())
+		case "results":
+			if in.IsNull() {
+				in.Skip()
+				out.Results = nil
+			} else {
+				in.Delim('[')
+				if out.Results == nil {
+					if !in.IsDelim(']') {
+						out.Results = make([]UserBulkResult, 0, 1)
+					} else {
+						out.Results = []UserBulkResult{}
+					}
+				} else {
+					out.Results = (out.Results)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v28 UserBulkResult
+					(v28).UnmarshalEasyJSON(in)
+					out.Results = append(out.Results, v28)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels11(out *jwriter.Writer, in UserBulkResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"succeeded\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Succeeded))
+	}
+	{
+		const prefix string = ",\"failed\":"
+		out.RawString(prefix)
+		out.Int(int(in.Failed))
+	}
+	{
+		const prefix string = ",\"results\":"
+		out.RawString(prefix)
+		if in.Results == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v29, v30 := range in.Results {
+				if v29 > 0 {
+					out.RawByte(',')
+				}
+				(v30).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserBulkResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels11(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserBulkResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels11(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels12(in *jlexer.Lexer, out *UserBulkRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "users":
+			if in.IsNull() {
+				in.Skip()
+				out.Users = nil
+			} else {
+				in.Delim('[')
+				if out.Users == nil {
+					if !in.IsDelim(']') {
+						out.Users = make([]UserSetRequest, 0, 1)
+					} else {
+						out.Users = []UserSetRequest{}
+					}
+				} else {
+					out.Users = (out.Users)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v31 UserSetRequest
+					(v31).UnmarshalEasyJSON(in)
+					out.Users = append(out.Users, v31)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels12(out *jwriter.Writer, in UserBulkRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"users\":"
+		out.RawString(prefix[1:])
+		if in.Users == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v32, v33 := range in.Users {
+				if v32 > 0 {
+					out.RawByte(',')
+				}
+				(v33).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserBulkRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels12(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserBulkRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels12(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels13(in *jlexer.Lexer, out *SegmentUpdateRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "description":
+			if in.IsNull() {
+				in.Skip()
+				out.Description = nil
+			} else {
+				if out.Description == nil {
+					out.Description = new(string)
+				}
+				*out.Description = string(in.String())
+			}
+		case "percentage":
+			if in.IsNull() {
+				in.Skip()
+				out.Percentage = nil
+			} else {
+				if out.Percentage == nil {
+					out.Percentage = new(int)
+				}
+				*out.Percentage = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels13(out *jwriter.Writer, in SegmentUpdateRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Description != nil {
+		const prefix string = ",\"description\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(*in.Description))
+	}
+	if in.Percentage != nil {
+		const prefix string = ",\"percentage\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(*in.Percentage))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SegmentUpdateRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels13(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SegmentUpdateRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels13(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels14(in *jlexer.Lexer, out *SegmentStats) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "slug":
+			out.Slug = string(in.String())
+		case "active":
+			out.Active = int(in.Int())
+		case "from":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.From).UnmarshalJSON(data))
+			}
+		case "to":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.To).UnmarshalJSON(data))
+			}
+		case "added":
+			out.Added = int(in.Int())
+		case "deleted":
+			out.Deleted = int(in.Int())
+		case "expired":
+			out.Expired = int(in.Int())
+		case "netGrowth":
+			out.NetGrowth = int(in.Int())
+		case "days":
+			if in.IsNull() {
+				in.Skip()
+				out.Days = nil
+			} else {
+				in.Delim('[')
+				if out.Days == nil {
+					if !in.IsDelim(']') {
+						out.Days = make([]SegmentDayStats, 0, 1)
+					} else {
+						out.Days = []SegmentDayStats{}
+					}
+				} else {
+					out.Days = (out.Days)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v34 SegmentDayStats
+					(v34).UnmarshalEasyJSON(in)
+					out.Days = append(out.Days, v34)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels14(out *jwriter.Writer, in SegmentStats) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Slug))
+	}
+	{
+		const prefix string = ",\"active\":"
+		out.RawString(prefix)
+		out.Int(int(in.Active))
+	}
+	{
+		const prefix string = ",\"from\":"
+		out.RawString(prefix)
+		out.Raw((in.From).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		out.Raw((in.To).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"added\":"
+		out.RawString(prefix)
+		out.Int(int(in.Added))
+	}
+	{
+		const prefix string = ",\"deleted\":"
+		out.RawString(prefix)
+		out.Int(int(in.Deleted))
+	}
+	{
+		const prefix string = ",\"expired\":"
+		out.RawString(prefix)
+		out.Int(int(in.Expired))
+	}
+	{
+		const prefix string = ",\"netGrowth\":"
+		out.RawString(prefix)
+		out.Int(int(in.NetGrowth))
+	}
+	{
+		const prefix string = ",\"days\":"
+		out.RawString(prefix)
+		if in.Days == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v35, v36 := range in.Days {
+				if v35 > 0 {
+					out.RawByte(',')
+				}
+				(v36).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SegmentStats) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels14(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SegmentStats) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels14(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels15(in *jlexer.Lexer, out *SegmentRestoreRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "restoreMembers":
+			out.RestoreMembers = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels15(out *jwriter.Writer, in SegmentRestoreRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"restoreMembers\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.RestoreMembers))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SegmentRestoreRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels15(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SegmentRestoreRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels15(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels16(in *jlexer.Lexer, out *SegmentMembersResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "users":
+			if in.IsNull() {
+				in.Skip()
+				out.Users = nil
+			} else {
+				in.Delim('[')
+				if out.Users == nil {
+					if !in.IsDelim(']') {
+						out.Users = make([]SegmentMember, 0, 1)
+					} else {
+						out.Users = []SegmentMember{}
+					}
+				} else {
+					out.Users = (out.Users)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v37 SegmentMember
+					(v37).UnmarshalEasyJSON(in)
+					out.Users = append(out.Users, v37)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "nextCursor":
+			out.NextCursor = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels16(out *jwriter.Writer, in SegmentMembersResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"users\":"
+		out.RawString(prefix[1:])
+		if in.Users == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v38, v39 := range in.Users {
+				if v38 > 0 {
+					out.RawByte(',')
+				}
+				(v39).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.NextCursor != "" {
+		const prefix string = ",\"nextCursor\":"
+		out.RawString(prefix)
+		out.String(string(in.NextCursor))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SegmentMembersResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels16(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SegmentMembersResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels16(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels17(in *jlexer.Lexer, out *SegmentMember) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "userID":
+			out.UserID = string(in.String())
+		case "createdAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		case "expiredAt":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpiredAt = nil
+			} else {
+				if out.ExpiredAt == nil {
+					out.ExpiredAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ExpiredAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels17(out *jwriter.Writer, in SegmentMember) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"userID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"createdAt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	if in.ExpiredAt != nil {
+		const prefix string = ",\"expiredAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.ExpiredAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SegmentMember) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels17(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SegmentMember) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels17(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels18(in *jlexer.Lexer, out *SegmentListResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "segments":
+			if in.IsNull() {
+				in.Skip()
+				out.Segments = nil
+			} else {
+				in.Delim('[')
+				if out.Segments == nil {
+					if !in.IsDelim(']') {
+						out.Segments = make([]SegmentInfo, 0, 0)
+					} else {
+						out.Segments = []SegmentInfo{}
+					}
+				} else {
+					out.Segments = (out.Segments)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v40 SegmentInfo
+					(v40).UnmarshalEasyJSON(in)
+					out.Segments = append(out.Segments, v40)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "total":
+			out.Total = int(in.Int())
+		case "limit":
+			out.Limit = int(in.Int())
+		case "offset":
+			out.Offset = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels18(out *jwriter.Writer, in SegmentListResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"segments\":"
+		out.RawString(prefix[1:])
+		if in.Segments == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v41, v42 := range in.Segments {
+				if v41 > 0 {
+					out.RawByte(',')
+				}
+				(v42).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int(int(in.Total))
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"offset\":"
+		out.RawString(prefix)
+		out.Int(int(in.Offset))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SegmentListResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels18(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SegmentListResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels18(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels19(in *jlexer.Lexer, out *SegmentInfo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "slug":
+			out.Slug = string(in.String())
+		case "description":
+			out.Description = string(in.String())
+		case "percentage":
+			out.Percentage = int(in.Int())
+		case "createdAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		case "deletedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.DeletedAt = nil
+			} else {
+				if out.DeletedAt == nil {
+					out.DeletedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.DeletedAt).UnmarshalJSON(data))
+				}
+			}
+		case "members":
+			out.Members = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels19(out *jwriter.Writer, in SegmentInfo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Slug))
+	}
+	if in.Description != "" {
+		const prefix string = ",\"description\":"
+		out.RawString(prefix)
+		out.String(string(in.Description))
+	}
+	if in.Percentage != 0 {
+		const prefix string = ",\"percentage\":"
+		out.RawString(prefix)
+		out.Int(int(in.Percentage))
+	}
+	{
+		const prefix string = ",\"createdAt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	if in.DeletedAt != nil {
+		const prefix string = ",\"deletedAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.DeletedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"members\":"
+		out.RawString(prefix)
+		out.Int(int(in.Members))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SegmentInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels19(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SegmentInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels19(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels20(in *jlexer.Lexer, out *SegmentDayStats) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "day":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Day).UnmarshalJSON(data))
+			}
+		case "added":
+			out.Added = int(in.Int())
+		case "deleted":
+			out.Deleted = int(in.Int())
+		case "expired":
+			out.Expired = int(in.Int())
+		case "net":
+			out.Net = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels20(out *jwriter.Writer, in SegmentDayStats) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"day\":"
+		out.RawString(prefix[1:])
+		out.Raw((in.Day).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"added\":"
+		out.RawString(prefix)
+		out.Int(int(in.Added))
+	}
+	{
+		const prefix string = ",\"deleted\":"
+		out.RawString(prefix)
+		out.Int(int(in.Deleted))
+	}
+	{
+		const prefix string = ",\"expired\":"
+		out.RawString(prefix)
+		out.Int(int(in.Expired))
+	}
+	{
+		const prefix string = ",\"net\":"
+		out.RawString(prefix)
+		out.Int(int(in.Net))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SegmentDayStats) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels20(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SegmentDayStats) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels20(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels21(in *jlexer.Lexer, out *Segment) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "slug":
+			out.Slug = string(in.String())
+		case "description":
+			out.Description = string(in.String())
+		case "percentage":
+			out.Percentage = int(in.Int())
+		case "DeletedAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.DeletedAt).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels21(out *jwriter.Writer, in Segment) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Slug))
+	}
+	if in.Description != "" {
+		const prefix string = ",\"description\":"
+		out.RawString(prefix)
+		out.String(string(in.Description))
+	}
+	if in.Percentage != 0 {
+		const prefix string = ",\"percentage\":"
+		out.RawString(prefix)
+		out.Int(int(in.Percentage))
+	}
+	{
+		const prefix string = ",\"DeletedAt\":"
+		out.RawString(prefix)
+		out.Raw((in.DeletedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Segment) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels21(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Segment) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels21(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels22(in *jlexer.Lexer, out *ReportRow) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "userID":
+			out.UserID = string(in.String())
+		case "slug":
+			out.Slug = string(in.String())
+		case "method":
+			out.Method = string(in.String())
+		case "timestamp":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Timestamp).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels22(out *jwriter.Writer, in ReportRow) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"userID\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix)
+		out.String(string(in.Slug))
+	}
+	{
+		const prefix string = ",\"method\":"
+		out.RawString(prefix)
+		out.String(string(in.Method))
+	}
+	{
+		const prefix string = ",\"timestamp\":"
+		out.RawString(prefix)
+		out.Raw((in.Timestamp).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReportRow) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels22(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReportRow) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels22(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels23(in *jlexer.Lexer, out *ReportResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "link":
+			out.Link = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels23(out *jwriter.Writer, in ReportResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"link\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Link))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReportResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels23(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReportResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels23(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels24(in *jlexer.Lexer, out *ReportRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "format":
+			out.Format = string(in.String())
+		case "force":
+			out.Force = bool(in.Bool())
+		case "year":
+			out.Year = int(in.Int())
+		case "month":
+			out.Month = int(in.Int())
+		case "from":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.From).UnmarshalJSON(data))
+			}
+		case "to":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.To).UnmarshalJSON(data))
+			}
+		case "slugs":
+			if in.IsNull() {
+				in.Skip()
+				out.Slugs = nil
+			} else {
+				in.Delim('[')
+				if out.Slugs == nil {
+					if !in.IsDelim(']') {
+						out.Slugs = make([]string, 0, 4)
+					} else {
+						out.Slugs = []string{}
+					}
+				} else {
+					out.Slugs = (out.Slugs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v43 string
+					v43 = string(in.String())
+					out.Slugs = append(out.Slugs, v43)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "userIDs":
+			if in.IsNull() {
+				in.Skip()
+				out.UserIDs = nil
+			} else {
+				in.Delim('[')
+				if out.UserIDs == nil {
+					if !in.IsDelim(']') {
+						out.UserIDs = make([]string, 0, 4)
+					} else {
+						out.UserIDs = []string{}
+					}
+				} else {
+					out.UserIDs = (out.UserIDs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v44 string
+					v44 = string(in.String())
+					out.UserIDs = append(out.UserIDs, v44)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "methods":
+			if in.IsNull() {
+				in.Skip()
+				out.Methods = nil
+			} else {
+				in.Delim('[')
+				if out.Methods == nil {
+					if !in.IsDelim(']') {
+						out.Methods = make([]string, 0, 4)
+					} else {
+						out.Methods = []string{}
+					}
+				} else {
+					out.Methods = (out.Methods)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v45 string
+					v45 = string(in.String())
+					out.Methods = append(out.Methods, v45)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels24(out *jwriter.Writer, in ReportRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Format != "" {
+		const prefix string = ",\"format\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Format))
+	}
+	if in.Force {
+		const prefix string = ",\"force\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Bool(bool(in.Force))
+	}
+	if in.Year != 0 {
+		const prefix string = ",\"year\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Year))
+	}
+	if in.Month != 0 {
+		const prefix string = ",\"month\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Month))
+	}
+	{
+		const prefix string = ",\"from\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.From).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		out.Raw((in.To).MarshalJSON())
+	}
+	if len(in.Slugs) != 0 {
+		const prefix string = ",\"slugs\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v46, v47 := range in.Slugs {
+				if v46 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v47))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.UserIDs) != 0 {
+		const prefix string = ",\"userIDs\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v48, v49 := range in.UserIDs {
+				if v48 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v49))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Methods) != 0 {
+		const prefix string = ",\"methods\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v50, v51 := range in.Methods {
+				if v50 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v51))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReportRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels24(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReportRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels24(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels25(in *jlexer.Lexer, out *ReportJob) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "reportID":
+			out.ReportID = string(in.String())
+		case "format":
+			out.Format = string(in.String())
+		case "status":
+			out.Status = string(in.String())
+		case "rows":
+			out.Rows = int(in.Int())
+		case "generatedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.GeneratedAt = nil
+			} else {
+				if out.GeneratedAt == nil {
+					out.GeneratedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.GeneratedAt).UnmarshalJSON(data))
+				}
+			}
+		case "link":
+			out.Link = string(in.String())
+		case "error":
+			out.Error = string(in.String())
+		case "createdAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		case "startedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.StartedAt = nil
+			} else {
+				if out.StartedAt == nil {
+					out.StartedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.StartedAt).UnmarshalJSON(data))
+				}
+			}
+		case "finishedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.FinishedAt = nil
+			} else {
+				if out.FinishedAt == nil {
+					out.FinishedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.FinishedAt).UnmarshalJSON(data))
+				}
+			}
+		case "year":
+			out.Year = int(in.Int())
+		case "month":
+			out.Month = int(in.Int())
+		case "from":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.From).UnmarshalJSON(data))
+			}
+		case "to":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.To).UnmarshalJSON(data))
+			}
+		case "slugs":
+			if in.IsNull() {
+				in.Skip()
+				out.Slugs = nil
+			} else {
+				in.Delim('[')
+				if out.Slugs == nil {
+					if !in.IsDelim(']') {
+						out.Slugs = make([]string, 0, 4)
+					} else {
+						out.Slugs = []string{}
+					}
+				} else {
+					out.Slugs = (out.Slugs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v52 string
+					v52 = string(in.String())
+					out.Slugs = append(out.Slugs, v52)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "userIDs":
+			if in.IsNull() {
+				in.Skip()
+				out.UserIDs = nil
+			} else {
+				in.Delim('[')
+				if out.UserIDs == nil {
+					if !in.IsDelim(']') {
+						out.UserIDs = make([]string, 0, 4)
+					} else {
+						out.UserIDs = []string{}
+					}
+				} else {
+					out.UserIDs = (out.UserIDs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v53 string
+					v53 = string(in.String())
+					out.UserIDs = append(out.UserIDs, v53)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "methods":
+			if in.IsNull() {
+				in.Skip()
+				out.Methods = nil
+			} else {
+				in.Delim('[')
+				if out.Methods == nil {
+					if !in.IsDelim(']') {
+						out.Methods = make([]string, 0, 4)
+					} else {
+						out.Methods = []string{}
+					}
+				} else {
+					out.Methods = (out.Methods)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v54 string
+					v54 = string(in.String())
+					out.Methods = append(out.Methods, v54)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels25(out *jwriter.Writer, in ReportJob) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"reportID\":"
+		out.RawString(prefix)
+		out.String(string(in.ReportID))
+	}
+	{
+		const prefix string = ",\"format\":"
+		out.RawString(prefix)
+		out.String(string(in.Format))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"rows\":"
+		out.RawString(prefix)
+		out.Int(int(in.Rows))
+	}
+	if in.GeneratedAt != nil {
+		const prefix string = ",\"generatedAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.GeneratedAt).MarshalJSON())
+	}
+	if in.Link != "" {
+		const prefix string = ",\"link\":"
+		out.RawString(prefix)
+		out.String(string(in.Link))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	{
+		const prefix string = ",\"createdAt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	if in.StartedAt != nil {
+		const prefix string = ",\"startedAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.StartedAt).MarshalJSON())
+	}
+	if in.FinishedAt != nil {
+		const prefix string = ",\"finishedAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.FinishedAt).MarshalJSON())
+	}
+	if in.Year != 0 {
+		const prefix string = ",\"year\":"
+		out.RawString(prefix)
+		out.Int(int(in.Year))
+	}
+	if in.Month != 0 {
+		const prefix string = ",\"month\":"
+		out.RawString(prefix)
+		out.Int(int(in.Month))
+	}
+	{
+		const prefix string = ",\"from\":"
+		out.RawString(prefix)
+		out.Raw((in.From).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		out.Raw((in.To).MarshalJSON())
+	}
+	if len(in.Slugs) != 0 {
+		const prefix string = ",\"slugs\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v55, v56 := range in.Slugs {
+				if v55 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v56))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.UserIDs) != 0 {
+		const prefix string = ",\"userIDs\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v57, v58 := range in.UserIDs {
+				if v57 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v58))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Methods) != 0 {
+		const prefix string = ",\"methods\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v59, v60 := range in.Methods {
+				if v59 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v60))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReportJob) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels25(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReportJob) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels25(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels26(in *jlexer.Lexer, out *ReportFilter) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "year":
+			out.Year = int(in.Int())
+		case "month":
+			out.Month = int(in.Int())
+		case "from":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.From).UnmarshalJSON(data))
+			}
+		case "to":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.To).UnmarshalJSON(data))
+			}
+		case "slugs":
+			if in.IsNull() {
+				in.Skip()
+				out.Slugs = nil
+			} else {
+				in.Delim('[')
+				if out.Slugs == nil {
+					if !in.IsDelim(']') {
+						out.Slugs = make([]string, 0, 4)
+					} else {
+						out.Slugs = []string{}
+					}
+				} else {
+					out.Slugs = (out.Slugs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v61 string
+					v61 = string(in.String())
+					out.Slugs = append(out.Slugs, v61)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "userIDs":
+			if in.IsNull() {
+				in.Skip()
+				out.UserIDs = nil
+			} else {
+				in.Delim('[')
+				if out.UserIDs == nil {
+					if !in.IsDelim(']') {
+						out.UserIDs = make([]string, 0, 4)
+					} else {
+						out.UserIDs = []string{}
+					}
+				} else {
+					out.UserIDs = (out.UserIDs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v62 string
+					v62 = string(in.String())
+					out.UserIDs = append(out.UserIDs, v62)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "methods":
+			if in.IsNull() {
+				in.Skip()
+				out.Methods = nil
+			} else {
+				in.Delim('[')
+				if out.Methods == nil {
+					if !in.IsDelim(']') {
+						out.Methods = make([]string, 0, 4)
+					} else {
+						out.Methods = []string{}
+					}
+				} else {
+					out.Methods = (out.Methods)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v63 string
+					v63 = string(in.String())
+					out.Methods = append(out.Methods, v63)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels26(out *jwriter.Writer, in ReportFilter) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Year != 0 {
+		const prefix string = ",\"year\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.Int(int(in.Year))
+	}
+	if in.Month != 0 {
+		const prefix string = ",\"month\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int(int(in.Month))
+	}
+	{
+		const prefix string = ",\"from\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Raw((in.From).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		out.Raw((in.To).MarshalJSON())
+	}
+	if len(in.Slugs) != 0 {
+		const prefix string = ",\"slugs\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v64, v65 := range in.Slugs {
+				if v64 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v65))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.UserIDs) != 0 {
+		const prefix string = ",\"userIDs\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v66, v67 := range in.UserIDs {
+				if v66 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v67))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Methods) != 0 {
+		const prefix string = ",\"methods\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v68, v69 := range in.Methods {
+				if v68 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v69))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReportFilter) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels26(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReportFilter) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels26(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels27(in *jlexer.Lexer, out *ProblemDetails) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "slugs":
+			if in.IsNull() {
+				in.Skip()
+				out.Slugs = nil
+			} else {
+				in.Delim('[')
+				if out.Slugs == nil {
+					if !in.IsDelim(']') {
+						out.Slugs = make([]string, 0, 4)
+					} else {
+						out.Slugs = []string{}
+					}
+				} else {
+					out.Slugs = (out.Slugs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v70 string
+					v70 = string(in.String())
+					out.Slugs = append(out.Slugs, v70)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "userIDs":
+			if in.IsNull() {
+				in.Skip()
+				out.UserIDs = nil
+			} else {
+				in.Delim('[')
+				if out.UserIDs == nil {
+					if !in.IsDelim(']') {
+						out.UserIDs = make([]string, 0, 4)
+					} else {
+						out.UserIDs = []string{}
+					}
+				} else {
+					out.UserIDs = (out.UserIDs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v71 string
+					v71 = string(in.String())
+					out.UserIDs = append(out.UserIDs, v71)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels27(out *jwriter.Writer, in ProblemDetails) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if len(in.Slugs) != 0 {
+		const prefix string = ",\"slugs\":"
+		first = false
+		out.RawString(prefix[1:])
+		{
+			out.RawByte('[')
+			for v72, v73 := range in.Slugs {
+				if v72 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v73))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.UserIDs) != 0 {
+		const prefix string = ",\"userIDs\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		{
+			out.RawByte('[')
+			for v74, v75 := range in.UserIDs {
+				if v74 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v75))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ProblemDetails) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels27(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ProblemDetails) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels27(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels28(in *jlexer.Lexer, out *Problem) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "status":
+			out.Status = int(in.Int())
+		case "detail":
+			out.Detail = string(in.String())
+		case "instance":
+			out.Instance = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		case "requestID":
+			out.RequestID = string(in.String())
+		case "details":
+			if in.IsNull() {
+				in.Skip()
+				out.Details = nil
+			} else {
+				if out.Details == nil {
+					out.Details = new(ProblemDetails)
+				}
+				(*out.Details).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels28(out *jwriter.Writer, in Problem) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.Int(int(in.Status))
+	}
+	if in.Detail != "" {
+		const prefix string = ",\"detail\":"
+		out.RawString(prefix)
+		out.String(string(in.Detail))
+	}
+	if in.Instance != "" {
+		const prefix string = ",\"instance\":"
+		out.RawString(prefix)
+		out.String(string(in.Instance))
+	}
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.String(string(in.Code))
+	}
+	if in.RequestID != "" {
+		const prefix string = ",\"requestID\":"
+		out.RawString(prefix)
+		out.String(string(in.RequestID))
+	}
+	if in.Details != nil {
+		const prefix string = ",\"details\":"
+		out.RawString(prefix)
+		(*in.Details).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Problem) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels28(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Problem) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels28(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels29(in *jlexer.Lexer, out *OutboxEvent) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int64(in.Int64())
+		case "kind":
+			out.Kind = string(in.String())
+		case "userID":
+			out.UserID = string(in.String())
+		case "slug":
+			out.Slug = string(in.String())
+		case "method":
+			out.Method = string(in.String())
+		case "expireAt":
+			if in.IsNull() {
+				in.Skip()
+				out.ExpireAt = nil
+			} else {
+				if out.ExpireAt == nil {
+					out.ExpireAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.ExpireAt).UnmarshalJSON(data))
+				}
+			}
+		case "timestamp":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Timestamp).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels29(out *jwriter.Writer, in OutboxEvent) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.ID))
+	}
+	{
+		const prefix string = ",\"kind\":"
+		out.RawString(prefix)
+		out.String(string(in.Kind))
+	}
+	if in.UserID != "" {
+		const prefix string = ",\"userID\":"
+		out.RawString(prefix)
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix)
+		out.String(string(in.Slug))
+	}
+	{
 		const prefix string = ",\"method\":"
 		out.RawString(prefix)
-		out.String(string(in.Method))
+		out.String(string(in.Method))
+	}
+	if in.ExpireAt != nil {
+		const prefix string = ",\"expireAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.ExpireAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"timestamp\":"
+		out.RawString(prefix)
+		out.Raw((in.Timestamp).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v OutboxEvent) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels29(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *OutboxEvent) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels29(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels30(in *jlexer.Lexer, out *ImportRowError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "row":
+			out.Row = int(in.Int())
+		case "userID":
+			out.UserID = string(in.String())
+		case "error":
+			out.Error = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		case "details":
+			if in.IsNull() {
+				in.Skip()
+				out.Details = nil
+			} else {
+				if out.Details == nil {
+					out.Details = new(ProblemDetails)
+				}
+				(*out.Details).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels30(out *jwriter.Writer, in ImportRowError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"row\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Row))
+	}
+	{
+		const prefix string = ",\"userID\":"
+		out.RawString(prefix)
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.String(string(in.Code))
+	}
+	if in.Details != nil {
+		const prefix string = ",\"details\":"
+		out.RawString(prefix)
+		(*in.Details).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ImportRowError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels30(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ImportRowError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels30(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels31(in *jlexer.Lexer, out *ImportJob) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "slug":
+			out.Slug = string(in.String())
+		case "status":
+			out.Status = string(in.String())
+		case "total":
+			out.Total = int(in.Int())
+		case "processed":
+			out.Processed = int(in.Int())
+		case "failed":
+			out.Failed = int(in.Int())
+		case "error":
+			out.Error = string(in.String())
+		case "createdAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		case "startedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.StartedAt = nil
+			} else {
+				if out.StartedAt == nil {
+					out.StartedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.StartedAt).UnmarshalJSON(data))
+				}
+			}
+		case "finishedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.FinishedAt = nil
+			} else {
+				if out.FinishedAt == nil {
+					out.FinishedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.FinishedAt).UnmarshalJSON(data))
+				}
+			}
+		case "errors":
+			if in.IsNull() {
+				in.Skip()
+				out.Errors = nil
+			} else {
+				in.Delim('[')
+				if out.Errors == nil {
+					if !in.IsDelim(']') {
+						out.Errors = make([]ImportRowError, 0, 1)
+					} else {
+						out.Errors = []ImportRowError{}
+					}
+				} else {
+					out.Errors = (out.Errors)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v76 ImportRowError
+					(v76).UnmarshalEasyJSON(in)
+					out.Errors = append(out.Errors, v76)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels31(out *jwriter.Writer, in ImportJob) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix)
+		out.String(string(in.Slug))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int(int(in.Total))
+	}
+	{
+		const prefix string = ",\"processed\":"
+		out.RawString(prefix)
+		out.Int(int(in.Processed))
+	}
+	{
+		const prefix string = ",\"failed\":"
+		out.RawString(prefix)
+		out.Int(int(in.Failed))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	{
+		const prefix string = ",\"createdAt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	if in.StartedAt != nil {
+		const prefix string = ",\"startedAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.StartedAt).MarshalJSON())
+	}
+	if in.FinishedAt != nil {
+		const prefix string = ",\"finishedAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.FinishedAt).MarshalJSON())
+	}
+	if len(in.Errors) != 0 {
+		const prefix string = ",\"errors\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v77, v78 := range in.Errors {
+				if v77 > 0 {
+					out.RawByte(',')
+				}
+				(v78).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ImportJob) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels31(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ImportJob) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels31(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels32(in *jlexer.Lexer, out *AuditLogResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "entries":
+			if in.IsNull() {
+				in.Skip()
+				out.Entries = nil
+			} else {
+				in.Delim('[')
+				if out.Entries == nil {
+					if !in.IsDelim(']') {
+						out.Entries = make([]AuditEntry, 0, 0)
+					} else {
+						out.Entries = []AuditEntry{}
+					}
+				} else {
+					out.Entries = (out.Entries)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v79 AuditEntry
+					(v79).UnmarshalEasyJSON(in)
+					out.Entries = append(out.Entries, v79)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "total":
+			out.Total = int(in.Int())
+		case "limit":
+			out.Limit = int(in.Int())
+		case "offset":
+			out.Offset = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels32(out *jwriter.Writer, in AuditLogResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"entries\":"
+		out.RawString(prefix[1:])
+		if in.Entries == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v80, v81 := range in.Entries {
+				if v80 > 0 {
+					out.RawByte(',')
+				}
+				(v81).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int(int(in.Total))
+	}
+	{
+		const prefix string = ",\"limit\":"
+		out.RawString(prefix)
+		out.Int(int(in.Limit))
 	}
 	{
-		const prefix string = ",\"timestamp\":"
+		const prefix string = ",\"offset\":"
 		out.RawString(prefix)
-		out.Raw((in.Timestamp).MarshalJSON())
+		out.Int(int(in.Offset))
 	}
 	out.RawByte('}')
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ReportRow) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels5(w, v)
+func (v AuditLogResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels32(w, v)
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ReportRow) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels5(l, v)
+func (v *AuditLogResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels32(l, v)
 }
-func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels6(in *jlexer.Lexer, out *ReportResponse) {
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels33(in *jlexer.Lexer, out *AuditEntry) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -513,8 +3667,49 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels6(in *
 			continue
 		}
 		switch key {
-		case "link":
-			out.Link = string(in.String())
+		case "id":
+			out.ID = int64(in.Int64())
+		case "actor":
+			out.Actor = string(in.String())
+		case "actorName":
+			out.ActorName = string(in.String())
+		case "requestID":
+			out.RequestID = string(in.String())
+		case "operation":
+			out.Operation = string(in.String())
+		case "slugs":
+			if in.IsNull() {
+				in.Skip()
+				out.Slugs = nil
+			} else {
+				in.Delim('[')
+				if out.Slugs == nil {
+					if !in.IsDelim(']') {
+						out.Slugs = make([]string, 0, 4)
+					} else {
+						out.Slugs = []string{}
+					}
+				} else {
+					out.Slugs = (out.Slugs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v82 string
+					v82 = string(in.String())
+					out.Slugs = append(out.Slugs, v82)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "userID":
+			out.UserID = string(in.String())
+		case "before":
+			(out.Before).UnmarshalEasyJSON(in)
+		case "after":
+			(out.After).UnmarshalEasyJSON(in)
+		case "createdAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -525,28 +3720,82 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels6(in *
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels6(out *jwriter.Writer, in ReportResponse) {
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels33(out *jwriter.Writer, in AuditEntry) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"link\":"
+		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Link))
+		out.Int64(int64(in.ID))
+	}
+	{
+		const prefix string = ",\"actor\":"
+		out.RawString(prefix)
+		out.String(string(in.Actor))
+	}
+	if in.ActorName != "" {
+		const prefix string = ",\"actorName\":"
+		out.RawString(prefix)
+		out.String(string(in.ActorName))
+	}
+	if in.RequestID != "" {
+		const prefix string = ",\"requestID\":"
+		out.RawString(prefix)
+		out.String(string(in.RequestID))
+	}
+	{
+		const prefix string = ",\"operation\":"
+		out.RawString(prefix)
+		out.String(string(in.Operation))
+	}
+	if len(in.Slugs) != 0 {
+		const prefix string = ",\"slugs\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v83, v84 := range in.Slugs {
+				if v83 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v84))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.UserID != "" {
+		const prefix string = ",\"userID\":"
+		out.RawString(prefix)
+		out.String(string(in.UserID))
+	}
+	if (in.Before).IsDefined() {
+		const prefix string = ",\"before\":"
+		out.RawString(prefix)
+		(in.Before).MarshalEasyJSON(out)
+	}
+	if (in.After).IsDefined() {
+		const prefix string = ",\"after\":"
+		out.RawString(prefix)
+		(in.After).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"createdAt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ReportResponse) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels6(w, v)
+func (v AuditEntry) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels33(w, v)
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ReportResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels6(l, v)
+func (v *AuditEntry) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels33(l, v)
 }
-func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels7(in *jlexer.Lexer, out *ReportRequest) {
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels34(in *jlexer.Lexer, out *APIKeyRequest) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -565,10 +3814,31 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels7(in *
 			continue
 		}
 		switch key {
-		case "year":
-			out.Year = int(in.Int())
-		case "month":
-			out.Month = int(in.Int())
+		case "name":
+			out.Name = string(in.String())
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v85 string
+					v85 = string(in.String())
+					out.Scopes = append(out.Scopes, v85)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -579,29 +3849,262 @@ func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels7(in *
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels7(out *jwriter.Writer, in ReportRequest) {
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels34(out *jwriter.Writer, in APIKeyRequest) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"year\":"
+		const prefix string = ",\"name\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Year))
+		out.String(string(in.Name))
 	}
 	{
-		const prefix string = ",\"month\":"
+		const prefix string = ",\"scopes\":"
 		out.RawString(prefix)
-		out.Int(int(in.Month))
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v86, v87 := range in.Scopes {
+				if v86 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v87))
+			}
+			out.RawByte(']')
+		}
 	}
 	out.RawByte('}')
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ReportRequest) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels7(w, v)
+func (v APIKeyRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels34(w, v)
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ReportRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels7(l, v)
+func (v *APIKeyRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels34(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels35(in *jlexer.Lexer, out *APIKeyListResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "keys":
+			if in.IsNull() {
+				in.Skip()
+				out.Keys = nil
+			} else {
+				in.Delim('[')
+				if out.Keys == nil {
+					if !in.IsDelim(']') {
+						out.Keys = make([]APIKey, 0, 0)
+					} else {
+						out.Keys = []APIKey{}
+					}
+				} else {
+					out.Keys = (out.Keys)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v88 APIKey
+					(v88).UnmarshalEasyJSON(in)
+					out.Keys = append(out.Keys, v88)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels35(out *jwriter.Writer, in APIKeyListResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"keys\":"
+		out.RawString(prefix[1:])
+		if in.Keys == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v89, v90 := range in.Keys {
+				if v89 > 0 {
+					out.RawByte(',')
+				}
+				(v90).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v APIKeyListResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels35(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *APIKeyListResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels35(l, v)
+}
+func easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels36(in *jlexer.Lexer, out *APIKey) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "prefix":
+			out.Prefix = string(in.String())
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v91 string
+					v91 = string(in.String())
+					out.Scopes = append(out.Scopes, v91)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "key":
+			out.Key = string(in.String())
+		case "createdAt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.CreatedAt).UnmarshalJSON(data))
+			}
+		case "revokedAt":
+			if in.IsNull() {
+				in.Skip()
+				out.RevokedAt = nil
+			} else {
+				if out.RevokedAt == nil {
+					out.RevokedAt = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.RevokedAt).UnmarshalJSON(data))
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels36(out *jwriter.Writer, in APIKey) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"prefix\":"
+		out.RawString(prefix)
+		out.String(string(in.Prefix))
+	}
+	{
+		const prefix string = ",\"scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v92, v93 := range in.Scopes {
+				if v92 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v93))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Key != "" {
+		const prefix string = ",\"key\":"
+		out.RawString(prefix)
+		out.String(string(in.Key))
+	}
+	{
+		const prefix string = ",\"createdAt\":"
+		out.RawString(prefix)
+		out.Raw((in.CreatedAt).MarshalJSON())
+	}
+	if in.RevokedAt != nil {
+		const prefix string = ",\"revokedAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.RevokedAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v APIKey) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeGithubComDupreehkudaAvitoSegmentsInternalModels36(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *APIKey) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeGithubComDupreehkudaAvitoSegmentsInternalModels36(l, v)
 }
@@ -0,0 +1,109 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	errs "github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// Of maps domain error to http error carrying its problem with stable code.
+// It returns nil for errors which are not part of the api.
+func Of(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, errs.ErrInvalidPeriod):
+		return FromError(err, http.StatusBadRequest, "invalid_period", "invalid time period provided")
+	case errors.Is(err, errs.ErrDataNotFound):
+		return FromError(err, http.StatusNotFound, "report_data_not_found", "no data for report")
+	case errors.Is(err, errs.ErrInvalidSegmentSlug):
+		return FromError(err, http.StatusBadRequest, "invalid_slug", "invalid slug naming")
+	case errors.Is(err, errs.ErrInvalidPercentage):
+		return FromError(err, http.StatusBadRequest, "invalid_percentage", "percentage should be between 0 and 100")
+	case errors.Is(err, errs.ErrNothingToUpdate):
+		return FromError(err, http.StatusBadRequest, "nothing_to_update", "nothing to update")
+	case errors.Is(err, errs.ErrNotDeleted):
+		return FromError(err, http.StatusConflict, "segment_not_deleted", "slug is not deleted")
+	case errors.Is(err, errs.ErrInvalidFilter):
+		return FromError(err, http.StatusBadRequest, "invalid_filter", "invalid filter")
+	case errors.Is(err, errs.ErrInvalidPagination):
+		return FromError(err, http.StatusBadRequest, "invalid_pagination", "invalid pagination")
+	case errors.Is(err, errs.ErrInvalidFormat):
+		return FromError(err, http.StatusBadRequest, "unsupported_format", "unsupported format")
+	case errors.Is(err, errs.ErrNotAcceptable):
+		return FromError(err, http.StatusNotAcceptable, "not_acceptable", "none of accepted formats is supported")
+	case errors.Is(err, errs.ErrInvalidUserID):
+		return FromError(err, http.StatusBadRequest, "invalid_user_id", "invalid userID")
+	case errors.Is(err, errs.ErrInvalidExpiry):
+		return FromError(err, http.StatusBadRequest, "invalid_expiry", "invalid expiry")
+	case errors.Is(err, errs.ErrNoSegmentsProvided):
+		return FromError(err, http.StatusBadRequest, "no_segments_provided", "no segments provided")
+	case errors.Is(err, errs.ErrReportNotFound):
+		return FromError(err, http.StatusNotFound, "report_not_found", "requested report not found")
+	case errors.Is(err, errs.ErrReportJobNotFound):
+		return FromError(err, http.StatusNotFound, "report_job_not_found", "report job not found")
+	case errors.Is(err, errs.ErrSegmentsNotFound):
+		return FromError(err, http.StatusBadRequest, "segments_not_found", "segment(s) not found")
+	case errors.Is(err, errs.ErrConflictingSlugs):
+		return FromError(err, http.StatusBadRequest, "conflicting_slugs", "segment(s) both added and removed")
+	case errors.Is(err, errs.ErrInvalidEntry):
+		return FromError(err, http.StatusBadRequest, "invalid_entry", "invalid entry")
+	case errors.Is(err, errs.ErrBatchTooLarge):
+		return FromError(err, http.StatusRequestEntityTooLarge, "batch_too_large", "too many entries in batch")
+	case errors.Is(err, errs.ErrInvalidImportFile):
+		return FromError(err, http.StatusBadRequest, "invalid_import_file", "invalid import file")
+	case errors.Is(err, errs.ErrImportNotFound):
+		return FromError(err, http.StatusNotFound, "import_not_found", "import job not found")
+	case errors.Is(err, errs.ErrQueueFull):
+		return FromError(err, http.StatusServiceUnavailable, "queue_full", "job queue is full, try again later")
+	case errors.Is(err, errs.ErrInvalidWebhook):
+		return FromError(err, http.StatusBadRequest, "invalid_webhook", "invalid webhook url or secret")
+	case errors.Is(err, errs.ErrWebhookNotFound):
+		return FromError(err, http.StatusNotFound, "webhook_not_found", "webhook not found")
+	case errors.Is(err, errs.ErrDeliveryNotFound):
+		return FromError(err, http.StatusNotFound, "delivery_not_found", "webhook delivery not found")
+	case errors.Is(err, errs.ErrDeliveryPending):
+		return FromError(err, http.StatusConflict, "delivery_pending", "webhook delivery is still pending")
+	case errors.Is(err, errs.ErrInvalidEventID):
+		return FromError(err, http.StatusBadRequest, "invalid_event_id", "invalid last event id")
+	case errors.Is(err, errs.ErrInvalidAPIKey):
+		return FromError(err, http.StatusBadRequest, "invalid_api_key", "invalid api key name or scopes")
+	case errors.Is(err, errs.ErrAPIKeyNotFound):
+		return FromError(err, http.StatusNotFound, "api_key_not_found", "api key not found")
+	case errors.Is(err, errs.ErrAlreadyExpired):
+		return FromError(err, http.StatusBadRequest, "segment_expired", "segment operation expired")
+	case errors.Is(err, errs.ErrUserNotFound):
+		return FromError(err, http.StatusNotFound, "user_not_found", "user not found")
+	case errors.Is(err, errs.ErrSegmentNotFound):
+		return FromError(err, http.StatusNotFound, "segment_not_found", "slug not found")
+	case errors.Is(err, errs.ErrAlreadyDeleted):
+		return FromError(err, http.StatusGone, "segment_deleted", "slug has been already deleted")
+	default:
+		return nil
+	}
+}
+
+// Entry returns problem of a single entry of batch request, which failed with the error.
+// Errors which are not part of the api are reported as internal ones.
+func Entry(err error) *models.Problem {
+	if he := Of(err); he != nil {
+		return he.Message.(*models.Problem)
+	}
+
+	return newProblem(http.StatusInternalServerError, "internal_server_error", "internal server error")
+}
+
+// RowError describes row of import file rejected with the error.
+func RowError(row int, userID string, err error) models.ImportRowError {
+	problem := Entry(err)
+
+	return models.ImportRowError{
+		Row:     row,
+		UserID:  userID,
+		Error:   problem.Detail,
+		Code:    problem.Code,
+		Details: problem.Details,
+	}
+}
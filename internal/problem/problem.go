@@ -0,0 +1,106 @@
+// Package problem renders errors as RFC 7807 problem details.
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mailru/easyjson"
+	"go.uber.org/zap"
+
+	errs "github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/requestid"
+)
+
+const (
+	// MIMEProblemJSON is the media type of problem responses.
+	MIMEProblemJSON = "application/problem+json"
+	// TypePrefix starts type of every problem, the code follows it.
+	TypePrefix = "urn:avito-segments:problem:"
+)
+
+// New returns http error with problem of the code as its message.
+func New(status int, code, detail string) *echo.HTTPError {
+	return echo.NewHTTPError(status, newProblem(status, code, detail))
+}
+
+// FromError returns http error with problem of the code, listing slugs and user ids the error was caused by.
+func FromError(err error, status int, code, detail string) *echo.HTTPError {
+	problem := newProblem(status, code, detail)
+
+	var detailed *errs.DetailedError
+	if errors.As(err, &detailed) && (len(detailed.Slugs) > 0 || len(detailed.UserIDs) > 0) {
+		problem.Details = &models.ProblemDetails{
+			Slugs:   detailed.Slugs,
+			UserIDs: detailed.UserIDs,
+		}
+	}
+
+	return echo.NewHTTPError(status, problem)
+}
+
+// Handler writes errors returned by handlers and middleware as problem+json.
+// Errors without problem get a code derived from their status.
+func Handler(logger *zap.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		var he *echo.HTTPError
+		if !errors.As(err, &he) {
+			logger.Error("Unhandled error", zap.Error(err))
+			he = New(http.StatusInternalServerError, codeFromStatus(http.StatusInternalServerError), "internal server error")
+		}
+
+		var problem models.Problem
+
+		switch message := he.Message.(type) {
+		case *models.Problem:
+			problem = *message
+		default:
+			problem = *newProblem(he.Code, codeFromStatus(he.Code), fmt.Sprint(message))
+		}
+
+		problem.Instance = c.Request().URL.Path
+		problem.RequestID = requestid.FromContext(c.Request().Context())
+
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(problem.Status)
+		} else {
+			var body []byte
+
+			if body, err = easyjson.Marshal(&problem); err == nil {
+				err = c.Blob(problem.Status, MIMEProblemJSON, body)
+			}
+		}
+
+		if err != nil {
+			logger.Error("Unable to write error response", zap.Error(err))
+		}
+	}
+}
+
+func newProblem(status int, code, detail string) *models.Problem {
+	return &models.Problem{
+		Type:   TypePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// codeFromStatus makes code of generic problem out of status text, e.g. "method_not_allowed".
+func codeFromStatus(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		text = "Unknown Error"
+	}
+
+	return strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(strings.ToLower(text))
+}
@@ -0,0 +1,107 @@
+package problem_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mailru/easyjson"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/problem"
+	"github.com/dupreehkuda/avito-segments/internal/requestid"
+)
+
+func TestHandler(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name            string
+		err             error
+		expectedProblem models.Problem
+	}{
+		{
+			name: "Problem with details",
+			err: problem.FromError(fmt.Errorf("set segments: %w", errors.WithSlugs(errors.ErrSegmentsNotFound, "AVITO_VOICE")),
+				http.StatusBadRequest, "segments_not_found", "segment(s) not found"),
+			expectedProblem: models.Problem{
+				Type:    problem.TypePrefix + "segments_not_found",
+				Title:   "Bad Request",
+				Status:  http.StatusBadRequest,
+				Detail:  "segment(s) not found",
+				Code:    "segments_not_found",
+				Details: &models.ProblemDetails{Slugs: []string{"AVITO_VOICE"}},
+			},
+		},
+		{
+			name: "Problem without details",
+			err:  problem.FromError(errors.ErrWebhookNotFound, http.StatusNotFound, "webhook_not_found", "webhook not found"),
+			expectedProblem: models.Problem{
+				Type:   problem.TypePrefix + "webhook_not_found",
+				Title:  "Not Found",
+				Status: http.StatusNotFound,
+				Detail: "webhook not found",
+				Code:   "webhook_not_found",
+			},
+		},
+		{
+			name: "Plain http error",
+			err:  echo.ErrMethodNotAllowed,
+			expectedProblem: models.Problem{
+				Type:   problem.TypePrefix + "method_not_allowed",
+				Title:  "Method Not Allowed",
+				Status: http.StatusMethodNotAllowed,
+				Detail: "Method Not Allowed",
+				Code:   "method_not_allowed",
+			},
+		},
+		{
+			name: "Unknown error",
+			err:  os.ErrInvalid,
+			expectedProblem: models.Problem{
+				Type:   problem.TypePrefix + "internal_server_error",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+				Detail: "internal server error",
+				Code:   "internal_server_error",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			zp, _ := zap.NewDevelopment()
+
+			e := echo.New()
+			e.HTTPErrorHandler = problem.Handler(zp)
+			e.Use(requestid.Middleware())
+			e.GET("/api/v1/user/:id", func(c echo.Context) error {
+				return tc.err
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/user/80b0b88d-379e-11ee-8bf7-0242c0a80002", nil)
+			req.Header.Set(echo.HeaderXRequestID, "f2b1c0de")
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			a.Equal(tc.expectedProblem.Status, rec.Code, "Wrong status code")
+			a.Equal(problem.MIMEProblemJSON, rec.Header().Get(echo.HeaderContentType))
+
+			var resp models.Problem
+			a.NoError(easyjson.Unmarshal(rec.Body.Bytes(), &resp))
+
+			tc.expectedProblem.Instance = "/api/v1/user/80b0b88d-379e-11ee-8bf7-0242c0a80002"
+			tc.expectedProblem.RequestID = "f2b1c0de"
+
+			a.Equal(tc.expectedProblem, resp)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/mailru/easyjson"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// Kafka publishes events as JSON messages keyed by Key, so events of a user land in one partition.
+type Kafka struct {
+	writer *kafka.Writer
+}
+
+// NewKafka creates kafka publisher. Brokers are not contacted until the first Publish.
+func NewKafka(config *config.Config) (*Kafka, error) {
+	cfg := config.Outbox.Kafka
+
+	if len(cfg.Brokers) == 0 || cfg.Topic == "" {
+		return nil, ErrKafkaNotConfigured
+	}
+
+	return &Kafka{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Topic:        cfg.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}, nil
+}
+
+// Publish writes events synchronously and returns once all of them are acknowledged.
+func (k *Kafka) Publish(ctx context.Context, events []models.OutboxEvent) error {
+	messages := make([]kafka.Message, 0, len(events))
+
+	for _, event := range events {
+		value, err := easyjson.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, kafka.Message{
+			Key:   []byte(Key(event)),
+			Value: value,
+			Time:  event.Timestamp,
+		})
+	}
+
+	return k.writer.WriteMessages(ctx, messages...)
+}
+
+func (k *Kafka) Close() error {
+	return k.writer.Close()
+}
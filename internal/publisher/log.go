@@ -0,0 +1,69 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// Log writes events to the service log. It is meant for development and setups without a broker.
+type Log struct {
+	logger *zap.Logger
+}
+
+func NewLog(logger *zap.Logger) *Log {
+	return &Log{logger: logger}
+}
+
+func (l *Log) Publish(_ context.Context, events []models.OutboxEvent) error {
+	for _, event := range events {
+		l.logger.Info("Event published",
+			zap.Int64("id", event.ID),
+			zap.String("kind", event.Kind),
+			zap.String("key", Key(event)),
+			zap.String("slug", event.Slug),
+			zap.String("method", event.Method),
+			zap.Time("timestamp", event.Timestamp),
+		)
+	}
+
+	return nil
+}
+
+func (l *Log) Close() error {
+	return nil
+}
+
+// Memory keeps published events in process, so tests can inspect them.
+type Memory struct {
+	mu     sync.Mutex
+	events []models.OutboxEvent
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) Publish(_ context.Context, events []models.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events = append(m.events, events...)
+
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+// Events returns copy of events published so far.
+func (m *Memory) Events() []models.OutboxEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]models.OutboxEvent(nil), m.events...)
+}
@@ -0,0 +1,60 @@
+// Package publisher delivers outbox events to consumers outside of the service.
+package publisher
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// Publisher types selectable in config.
+const (
+	TypeLog   = "log"
+	TypeKafka = "kafka"
+)
+
+var ErrKafkaNotConfigured = errors.New("kafka brokers and topic are required")
+
+// Publisher delivers events in the given order. Events of one key must not be reordered,
+// the whole batch is published again if Publish fails.
+type Publisher interface {
+	Publish(ctx context.Context, events []models.OutboxEvent) error
+	Close() error
+}
+
+// New creates publisher chosen in config.
+func New(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) (Publisher, error) {
+	switch config.Outbox.Publisher {
+	case TypeKafka:
+		pub, err := NewKafka(config)
+		if err != nil {
+			logger.Error("Unable to create kafka publisher", zap.Error(err))
+			return nil, err
+		}
+
+		lc.Append(fx.Hook{
+			OnStop: func(ctx context.Context) error {
+				return pub.Close()
+			},
+		})
+
+		return pub, nil
+	default:
+		return NewLog(logger), nil
+	}
+}
+
+// Key returns partitioning key of the event: user for membership changes and slug for catalog changes.
+// Events with the same key are delivered in order.
+func Key(event models.OutboxEvent) string {
+	if event.Kind == models.EventMembership {
+		return event.UserID
+	}
+
+	return event.Slug
+}
@@ -0,0 +1,96 @@
+package publisher_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/publisher"
+)
+
+func TestNew(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name          string
+		publisher     string
+		brokers       []string
+		topic         string
+		expected      publisher.Publisher
+		expectedError error
+	}{
+		{
+			name:     "Log by default",
+			expected: &publisher.Log{},
+		},
+		{
+			name:      "Kafka",
+			publisher: publisher.TypeKafka,
+			brokers:   []string{"localhost:9092"},
+			topic:     "segment-events",
+			expected:  &publisher.Kafka{},
+		},
+		{
+			name:          "Kafka without brokers",
+			publisher:     publisher.TypeKafka,
+			topic:         "segment-events",
+			expectedError: publisher.ErrKafkaNotConfigured,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Outbox.Publisher = tc.publisher
+			cfg.Outbox.Kafka.Brokers = tc.brokers
+			cfg.Outbox.Kafka.Topic = tc.topic
+
+			lc := fxtest.NewLifecycle(t)
+			pub, err := publisher.New(lc, cfg, zap.NewNop())
+
+			a.Equal(tc.expectedError, err)
+
+			if err == nil {
+				a.IsType(tc.expected, pub)
+				a.NoError(pub.Publish(context.Background(), nil))
+			}
+
+			lc.RequireStart().RequireStop()
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal("80b0b88d-379e-11ee-8bf7-0242c0a80002", publisher.Key(models.OutboxEvent{
+		Kind:   models.EventMembership,
+		UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+		Slug:   "TEST_SLUG",
+	}))
+	a.Equal("TEST_SLUG", publisher.Key(models.OutboxEvent{Kind: models.EventSegment, Slug: "TEST_SLUG"}))
+}
+
+func TestMemory(t *testing.T) {
+	a := assert.New(t)
+
+	events := []models.OutboxEvent{
+		{ID: 1, Kind: models.EventMembership, UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Slug: "TEST_SLUG", Method: models.MethodAdded},
+		{ID: 2, Kind: models.EventSegment, Slug: "TEST_SLUG", Method: models.SegmentDeleted},
+	}
+
+	memory := publisher.NewMemory()
+	a.NoError(memory.Publish(context.Background(), events[:1]))
+	a.NoError(memory.Publish(context.Background(), events[1:]))
+
+	published := memory.Events()
+	a.Equal(events, published)
+
+	published[0].Slug = "CHANGED"
+	a.Equal(events, memory.Events())
+}
@@ -0,0 +1,52 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// csvWriter writes CSV with header row and RFC3339 timestamps in UTC.
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+	header bool
+}
+
+func newCSV(w io.Writer) *csvWriter {
+	return &csvWriter{writer: csv.NewWriter(w), record: make([]string, len(header))}
+}
+
+func (c *csvWriter) Write(row models.ReportRow) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	c.record[0], c.record[1], c.record[2] = row.UserID, row.Slug, row.Method
+	c.record[3] = row.Timestamp.UTC().Format(time.RFC3339)
+
+	return c.writer.Write(c.record)
+}
+
+// Close writes header of empty report and flushes buffered rows.
+func (c *csvWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	c.writer.Flush()
+
+	return c.writer.Error()
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+
+	c.header = true
+
+	return c.writer.Write(header)
+}
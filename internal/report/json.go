@@ -0,0 +1,66 @@
+package report
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/mailru/easyjson"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// jsonWriter writes rows either as a single JSON array or as newline delimited objects.
+type jsonWriter struct {
+	writer    *bufio.Writer
+	delimited bool
+	rows      int
+}
+
+func newJSON(w io.Writer, delimited bool) *jsonWriter {
+	return &jsonWriter{writer: bufio.NewWriter(w), delimited: delimited}
+}
+
+func (j *jsonWriter) Write(row models.ReportRow) error {
+	var sep byte
+
+	switch {
+	case j.delimited:
+	case j.rows == 0:
+		sep = '['
+	default:
+		sep = ','
+	}
+
+	if sep != 0 {
+		if err := j.writer.WriteByte(sep); err != nil {
+			return err
+		}
+	}
+
+	j.rows++
+
+	if _, err := easyjson.MarshalToWriter(row, j.writer); err != nil {
+		return err
+	}
+
+	if j.delimited {
+		return j.writer.WriteByte('\n')
+	}
+
+	return nil
+}
+
+func (j *jsonWriter) Close() error {
+	if !j.delimited {
+		closing := "]\n"
+		if j.rows == 0 {
+			closing = "[]\n"
+		}
+
+		if _, err := j.writer.WriteString(closing); err != nil {
+			return err
+		}
+	}
+
+	return j.writer.Flush()
+}
@@ -0,0 +1,79 @@
+// Package report writes membership history reports in formats suitable for spreadsheets and BI tools.
+package report
+
+import (
+	"io"
+
+	errs "github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// Content types of report formats.
+const (
+	MimeCSV    = "text/csv"
+	MimeJSON   = "application/json"
+	MimeNDJSON = "application/x-ndjson"
+	MimeXLSX   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// header names report columns in formats which have one.
+var header = []string{"user_id", "slug", "method", "timestamp"}
+
+// Writer writes report rows one by one. Report is complete only after Close.
+type Writer interface {
+	Write(row models.ReportRow) error
+	Close() error
+}
+
+// NewWriter creates writer of report in the format. Close does not close w.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case models.FormatCSV:
+		return newCSV(w), nil
+	case models.FormatJSON:
+		return newJSON(w, false), nil
+	case models.FormatNDJSON:
+		return newJSON(w, true), nil
+	case models.FormatXLSX:
+		return newXLSX(w)
+	default:
+		return nil, errs.ErrInvalidFormat
+	}
+}
+
+// IsValidFormat checks that report can be written in the format.
+func IsValidFormat(format string) bool {
+	return ContentType(format) != ""
+}
+
+// ContentType returns content type of the format or empty string for unknown one.
+func ContentType(format string) string {
+	switch format {
+	case models.FormatCSV:
+		return MimeCSV
+	case models.FormatJSON:
+		return MimeJSON
+	case models.FormatNDJSON:
+		return MimeNDJSON
+	case models.FormatXLSX:
+		return MimeXLSX
+	default:
+		return ""
+	}
+}
+
+// FormatOf returns format of the content type or empty string for unknown one.
+func FormatOf(contentType string) string {
+	switch contentType {
+	case MimeCSV:
+		return models.FormatCSV
+	case MimeJSON:
+		return models.FormatJSON
+	case MimeNDJSON, "application/ndjson":
+		return models.FormatNDJSON
+	case MimeXLSX:
+		return models.FormatXLSX
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,170 @@
+package report_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/report"
+)
+
+var testRows = []models.ReportRow{
+	{
+		UserID:    "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+		Slug:      "TEST_SLUG",
+		Method:    models.MethodAdded,
+		Timestamp: time.Date(2023, time.August, 26, 19, 0, 0, 0, time.UTC),
+	},
+	{
+		UserID:    "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+		Slug:      "TEST_SLUG",
+		Method:    models.MethodDeleted,
+		Timestamp: time.Date(2023, time.August, 27, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60)),
+	},
+}
+
+func TestWriter(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name          string
+		format        string
+		rows          []models.ReportRow
+		expected      string
+		expectedError error
+	}{
+		{
+			name:   "CSV",
+			format: models.FormatCSV,
+			rows:   testRows,
+			expected: "user_id,slug,method,timestamp\n" +
+				"80b0b88d-379e-11ee-8bf7-0242c0a80002,TEST_SLUG,added,2023-08-26T19:00:00Z\n" +
+				"80b0b88d-379e-11ee-8bf7-0242c0a80002,TEST_SLUG,deleted,2023-08-27T09:00:00Z\n",
+		},
+		{
+			name:     "Empty CSV",
+			format:   models.FormatCSV,
+			expected: "user_id,slug,method,timestamp\n",
+		},
+		{
+			name:   "JSON",
+			format: models.FormatJSON,
+			rows:   testRows,
+			expected: `[{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002","slug":"TEST_SLUG","method":"added","timestamp":"2023-08-26T19:00:00Z"},` +
+				`{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002","slug":"TEST_SLUG","method":"deleted","timestamp":"2023-08-27T12:00:00+03:00"}]` + "\n",
+		},
+		{
+			name:     "Empty JSON",
+			format:   models.FormatJSON,
+			expected: "[]\n",
+		},
+		{
+			name:   "NDJSON",
+			format: models.FormatNDJSON,
+			rows:   testRows,
+			expected: `{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002","slug":"TEST_SLUG","method":"added","timestamp":"2023-08-26T19:00:00Z"}` + "\n" +
+				`{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002","slug":"TEST_SLUG","method":"deleted","timestamp":"2023-08-27T12:00:00+03:00"}` + "\n",
+		},
+		{
+			name:          "Unsupported format",
+			format:        "pdf",
+			expectedError: errors.ErrInvalidFormat,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			writer, err := report.NewWriter(tc.format, &out)
+			a.Equal(tc.expectedError, err)
+
+			if err != nil {
+				return
+			}
+
+			for _, row := range tc.rows {
+				a.NoError(writer.Write(row))
+			}
+
+			a.NoError(writer.Close())
+			a.Equal(tc.expected, out.String())
+		})
+	}
+}
+
+func TestWriter_XLSX(t *testing.T) {
+	a := assert.New(t)
+
+	var out bytes.Buffer
+
+	writer, err := report.NewWriter(models.FormatXLSX, &out)
+	a.NoError(err)
+
+	for _, row := range append(testRows, models.ReportRow{UserID: "<&>", Timestamp: testRows[0].Timestamp}) {
+		a.NoError(writer.Write(row))
+	}
+
+	a.NoError(writer.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	a.NoError(err)
+
+	parts := make(map[string]*zip.File)
+	for _, file := range archive.File {
+		parts[file.Name] = file
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		a.Contains(parts, name)
+	}
+
+	file, err := parts["xl/worksheets/sheet1.xml"].Open()
+	a.NoError(err)
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	a.NoError(err)
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Style  string `xml:"s,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+
+	a.NoError(xml.Unmarshal(content, &sheet))
+	a.Len(sheet.Rows, 4)
+
+	a.Equal("user_id", sheet.Rows[0].Cells[0].Inline)
+	a.Equal("timestamp", sheet.Rows[0].Cells[3].Inline)
+	a.Equal("80b0b88d-379e-11ee-8bf7-0242c0a80002", sheet.Rows[1].Cells[0].Inline)
+	a.Equal("added", sheet.Rows[1].Cells[2].Inline)
+	a.Equal("1", sheet.Rows[1].Cells[3].Style)
+	a.Equal("45164.791666666664", sheet.Rows[1].Cells[3].Value)
+	a.Equal("45165.375", sheet.Rows[2].Cells[3].Value)
+	a.Equal("<&>", sheet.Rows[3].Cells[0].Inline)
+}
+
+func TestFormatOf(t *testing.T) {
+	a := assert.New(t)
+
+	for _, format := range []string{models.FormatCSV, models.FormatJSON, models.FormatNDJSON, models.FormatXLSX} {
+		a.True(report.IsValidFormat(format))
+		a.Equal(format, report.FormatOf(report.ContentType(format)))
+	}
+
+	a.False(report.IsValidFormat("pdf"))
+	a.Equal("", report.FormatOf("application/pdf"))
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// xlsxParts are static parts of workbook with a single sheet.
+// Style 1 shows spreadsheet dates as timestamps.
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", xml.Header +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Report" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	{"xl/styles.xml", xml.Header +
+		`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+		`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border/></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+		`</styleSheet>`},
+}
+
+const (
+	xlsxSheet     = "xl/worksheets/sheet1.xml"
+	xlsxSheetOpen = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetEnd  = `</sheetData></worksheet>`
+)
+
+// spreadsheetEpoch is the zero day of spreadsheet date serials.
+var spreadsheetEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// xlsxWriter streams rows into the only sheet of workbook, so memory does not depend on report size.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	row     int
+}
+
+func newXLSX(w io.Writer) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+
+	for _, part := range xlsxParts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = io.WriteString(file, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	file, err := archive.Create(xlsxSheet)
+	if err != nil {
+		return nil, err
+	}
+
+	x := &xlsxWriter{archive: archive, sheet: bufio.NewWriter(file)}
+
+	if _, err = x.sheet.WriteString(xlsxSheetOpen); err != nil {
+		return nil, err
+	}
+
+	x.openRow()
+	for _, name := range header {
+		x.stringCell(name)
+	}
+
+	return x, x.closeRow()
+}
+
+func (x *xlsxWriter) Write(row models.ReportRow) error {
+	x.openRow()
+	x.stringCell(row.UserID)
+	x.stringCell(row.Slug)
+	x.stringCell(row.Method)
+	x.timeCell(row.Timestamp)
+
+	return x.closeRow()
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := x.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return x.archive.Close()
+}
+
+func (x *xlsxWriter) openRow() {
+	x.row++
+	_, _ = x.sheet.WriteString(`<row r="` + strconv.Itoa(x.row) + `">`)
+}
+
+// closeRow ends the row and returns first error of writing it, bufio keeps it until flush.
+func (x *xlsxWriter) closeRow() error {
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (x *xlsxWriter) stringCell(value string) {
+	_, _ = x.sheet.WriteString(`<c t="inlineStr"><is><t>`)
+	_ = xml.EscapeText(x.sheet, []byte(value))
+	_, _ = x.sheet.WriteString(`</t></is></c>`)
+}
+
+// timeCell writes timestamp as spreadsheet date serial, so it can be sorted and filtered as a date.
+func (x *xlsxWriter) timeCell(value time.Time) {
+	days := value.Sub(spreadsheetEpoch).Hours() / 24
+
+	_, _ = x.sheet.WriteString(`<c s="1"><v>`)
+	_, _ = x.sheet.WriteString(strconv.FormatFloat(days, 'f', -1, 64))
+	_, _ = x.sheet.WriteString(`</v></c>`)
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// CreateAPIKey stores new api key by its hash. Key without its hash is recorded to audit log.
+func (r *Repository) CreateAPIKey(ctx context.Context, key *models.APIKey, audit *models.AuditEntry) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Insert("api_keys").
+		Columns("id", "name", "prefix", "key_hash", "scopes", "created_at").
+		Values(key.ID, key.Name, key.Prefix, key.Hash, key.Scopes, key.CreatedAt).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err = tx.Exec(ctx, queryString, queryArgs...); err != nil {
+			return err
+		}
+
+		if audit.After, err = apiKeySnapshot(ctx, tx, key.ID); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, audit, key.CreatedAt)
+	})
+	if err != nil {
+		r.logger.Error("Error while creating api key", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ListAPIKeys returns all api keys including revoked ones.
+func (r *Repository) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := apiKeyQuery().
+		OrderBy("created_at", "id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+
+	keys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.APIKey, error) {
+		var key models.APIKey
+		err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Scopes, &key.CreatedAt, &key.RevokedAt)
+
+		return key, err
+	})
+	if err != nil {
+		r.logger.Error("Error while scanning query", zap.Error(err))
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// APIKeyByHash returns active api key with the hash or nil if there is no such key.
+func (r *Repository) APIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := apiKeyQuery().
+		Where(sq.Eq{"key_hash": hash, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	key := &models.APIKey{}
+
+	err = conn.QueryRow(ctx, queryString, queryArgs...).
+		Scan(&key.ID, &key.Name, &key.Prefix, &key.Scopes, &key.CreatedAt, &key.RevokedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func apiKeyQuery() sq.SelectBuilder {
+	return sq.Select("id", "name", "prefix", "scopes", "created_at", "revoked_at").From("api_keys")
+}
+
+// RevokeAPIKey revokes api key and reports whether it exists. Revoking a key twice keeps the first moment.
+// Key before and after is recorded to audit log.
+func (r *Repository) RevokeAPIKey(ctx context.Context, id string, now time.Time, audit *models.AuditEntry) (bool, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return false, err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("api_keys").
+		Set("revoked_at", sq.Expr("COALESCE(revoked_at, ?)", now)).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	found := false
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if audit.Before, err = apiKeySnapshot(ctx, tx, id); err != nil || audit.Before == nil {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, queryString, queryArgs...); err != nil {
+			return err
+		}
+
+		if audit.After, err = apiKeySnapshot(ctx, tx, id); err != nil {
+			return err
+		}
+
+		found = true
+
+		return recordAudit(ctx, tx, audit, now)
+	})
+	if err != nil {
+		r.logger.Error("Error while revoking api key", zap.Error(err))
+		return false, err
+	}
+
+	return found, nil
+}
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// AuditLog returns a page of audit log matching filter, newest first, and total amount of matching entries.
+func (r *Repository) AuditLog(ctx context.Context, filter *models.AuditFilter) ([]models.AuditEntry, int, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, 0, err
+	}
+	defer conn.Release()
+
+	query := sq.Select("id", "actor", "actor_name", "request_id", "operation", "slugs",
+		"COALESCE(user_id, '')", "before", "after", "created_at", "COUNT(*) OVER()").
+		From("audit_log").
+		OrderBy("id DESC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+
+	if filter.Actor != "" {
+		query = query.Where(sq.Eq{"actor": filter.Actor})
+	}
+
+	if filter.Slug != "" {
+		query = query.Where("slugs @> ARRAY[?]::text[]", filter.Slug)
+	}
+
+	if filter.UserID != "" {
+		query = query.Where(sq.Eq{"user_id": filter.UserID})
+	}
+
+	if !filter.From.IsZero() {
+		query = query.Where(sq.GtOrEq{"created_at": filter.From})
+	}
+
+	if !filter.To.IsZero() {
+		query = query.Where(sq.Lt{"created_at": filter.To})
+	}
+
+	queryString, queryArgs := query.PlaceholderFormat(sq.Dollar).MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		total int
+		resp  = make([]models.AuditEntry, 0)
+	)
+
+	for rows.Next() {
+		var (
+			entry         models.AuditEntry
+			before, after []byte
+		)
+
+		err = rows.Scan(&entry.ID, &entry.Actor, &entry.ActorName, &entry.RequestID, &entry.Operation, &entry.Slugs,
+			&entry.UserID, &before, &after, &entry.CreatedAt, &total)
+		if err != nil {
+			r.logger.Error("Error while scanning query", zap.Error(err))
+			return nil, 0, err
+		}
+
+		entry.Before, entry.After = before, after
+
+		resp = append(resp, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return resp, total, nil
+}
+
+// recordAudit appends the entry to audit log in the same transaction as the change it describes.
+func recordAudit(ctx context.Context, tx pgx.Tx, entry *models.AuditEntry, now time.Time) error {
+	var userID interface{}
+	if entry.UserID != "" {
+		userID = entry.UserID
+	}
+
+	slugs := entry.Slugs
+	if slugs == nil {
+		slugs = []string{}
+	}
+
+	queryString, queryArgs := sq.Insert("audit_log").
+		Columns("actor", "actor_name", "request_id", "operation", "slugs", "user_id", "before", "after", "created_at").
+		Values(entry.Actor, entry.ActorName, entry.RequestID, entry.Operation, slugs, userID,
+			rawJSON(entry.Before), rawJSON(entry.After), now).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if err := tx.QueryRow(ctx, queryString, queryArgs...).Scan(&entry.ID); err != nil {
+		return err
+	}
+
+	entry.CreatedAt = now
+
+	return nil
+}
+
+// recordAuditEntries appends entries to audit log in the same transaction as the change they describe.
+// Unlike recordAudit it copies entries at once, so it suits changes of many users.
+func recordAuditEntries(ctx context.Context, tx pgx.Tx, entries []models.AuditEntry, now time.Time) error {
+	_, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"audit_log"},
+		[]string{"actor", "actor_name", "request_id", "operation", "slugs", "user_id", "before", "after", "created_at"},
+		pgx.CopyFromSlice(len(entries), func(i int) ([]any, error) {
+			entry := entries[i]
+
+			slugs := entry.Slugs
+			if slugs == nil {
+				slugs = []string{}
+			}
+
+			return []any{entry.Actor, entry.ActorName, entry.RequestID, entry.Operation, slugs,
+				entry.UserID, rawJSON(entry.Before), rawJSON(entry.After), now}, nil
+		}),
+	)
+
+	return err
+}
+
+// segmentSnapshot returns segment's state as JSON or nil if there is no such segment.
+func segmentSnapshot(ctx context.Context, tx pgx.Tx, slug string) ([]byte, error) {
+	return rowSnapshot(ctx, tx, sq.Select(`jsonb_build_object(
+			'slug', slug, 'description', description, 'percentage', percentage,
+			'createdAt', created_at, 'deletedAt', deleted_at)`).
+		From("segments").
+		Where(sq.Eq{"slug": slug}))
+}
+
+// apiKeySnapshot returns api key's state as JSON or nil if there is no such key.
+// Hash of the key is left out, the key itself is never stored.
+func apiKeySnapshot(ctx context.Context, tx pgx.Tx, id string) ([]byte, error) {
+	return rowSnapshot(ctx, tx, sq.Select(`jsonb_build_object(
+			'id', id, 'name', name, 'prefix', prefix, 'scopes', scopes,
+			'createdAt', created_at, 'revokedAt', revoked_at)`).
+		From("api_keys").
+		Where(sq.Eq{"id": id}))
+}
+
+// webhookSnapshot returns webhook's state as JSON or nil if there is no such webhook.
+// Signing secret is left out.
+func webhookSnapshot(ctx context.Context, tx pgx.Tx, id string) ([]byte, error) {
+	return rowSnapshot(ctx, tx, sq.Select(`jsonb_build_object(
+			'id', id, 'url', url, 'slugs', slugs, 'createdAt', created_at)`).
+		From("webhooks").
+		Where(sq.Eq{"id": id}))
+}
+
+// rowSnapshot returns the only JSON column of the row selected by query or nil if there is no such row.
+func rowSnapshot(ctx context.Context, tx pgx.Tx, query sq.SelectBuilder) ([]byte, error) {
+	queryString, queryArgs := query.PlaceholderFormat(sq.Dollar).MustSql()
+
+	var snapshot []byte
+
+	err := tx.QueryRow(ctx, queryString, queryArgs...).Scan(&snapshot)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return snapshot, err
+}
+
+// membershipSnapshotColumn aggregates active memberships into JSON array.
+const membershipSnapshotColumn = `COALESCE(jsonb_agg(jsonb_build_object(
+			'slug', user_segments.slug, 'createdAt', user_segments.created_at,
+			'expiredAt', user_segments.expired_at) ORDER BY user_segments.slug), '[]'::jsonb)`
+
+// membershipSnapshot returns user's active memberships as JSON array.
+func membershipSnapshot(ctx context.Context, tx pgx.Tx, userID string, now time.Time) ([]byte, error) {
+	queryString, queryArgs := sq.Select(membershipSnapshotColumn).
+		From("user_segments").
+		Where(sq.Eq{"user_segments.user_id": userID}).
+		Where(activeMembership(now)).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	var snapshot []byte
+
+	err := tx.QueryRow(ctx, queryString, queryArgs...).Scan(&snapshot)
+
+	return snapshot, err
+}
+
+// membershipSnapshots returns active memberships of each of the users as JSON array.
+// Users without active memberships are left out.
+func membershipSnapshots(ctx context.Context, tx pgx.Tx, userIDs []string, now time.Time) (map[string][]byte, error) {
+	queryString, queryArgs := sq.Select("user_segments.user_id", membershipSnapshotColumn).
+		From("user_segments").
+		Where("user_segments.user_id = ANY(?)", userIDs).
+		Where(activeMembership(now)).
+		GroupBy("user_segments.user_id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	rows, err := tx.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := make(map[string][]byte, len(userIDs))
+
+	for rows.Next() {
+		var (
+			userID   string
+			snapshot []byte
+		)
+
+		if err = rows.Scan(&userID, &snapshot); err != nil {
+			return nil, err
+		}
+
+		snapshots[userID] = snapshot
+	}
+
+	return snapshots, rows.Err()
+}
+
+// snapshotOf returns user's snapshot taken by membershipSnapshots. User left out has no memberships.
+func snapshotOf(snapshots map[string][]byte, userID string) []byte {
+	if snapshot, ok := snapshots[userID]; ok {
+		return snapshot
+	}
+
+	return []byte("[]")
+}
+
+// rawJSON passes empty JSON to database as NULL.
+func rawJSON(raw []byte) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	return raw
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// stableEvents limits outbox to events written by transactions finished before the oldest running one.
+// Events of a running transaction may get smaller ids than already committed ones,
+// so reading only finished part of the stream guarantees nothing appears behind a position later.
+const stableEvents = "txid < pg_snapshot_xmin(pg_current_snapshot())"
+
+// Events returns events matching the filter which follow its position, in stream order.
+func (r *Repository) Events(ctx context.Context, filter *models.EventFilter) ([]models.StreamEvent, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	query := sq.Select("txid::text::bigint", "id", "kind", "COALESCE(user_id, '')", "slug", "method", "expire_at", "created_at").
+		From("outbox").
+		Where("(txid, id) > (?::text::xid8, ?)", strconv.FormatInt(filter.After.Tx, 10), filter.After.ID).
+		Where(stableEvents).
+		OrderBy("txid", "id").
+		Limit(uint64(filter.Limit)).
+		PlaceholderFormat(sq.Dollar)
+
+	if len(filter.Slugs) > 0 {
+		query = query.Where(sq.Eq{"slug": filter.Slugs})
+	}
+
+	if len(filter.UserIDs) > 0 {
+		query = query.Where(sq.Eq{"user_id": filter.UserIDs})
+	}
+
+	queryString, queryArgs := query.MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.StreamEvent, error) {
+		var (
+			res   models.StreamEvent
+			event = &res.Event
+		)
+
+		err := row.Scan(&res.Position.Tx, &event.ID, &event.Kind, &event.UserID, &event.Slug,
+			&event.Method, &event.ExpireAt, &event.Timestamp)
+		res.Position.ID = event.ID
+
+		return res, err
+	})
+	if err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// EventsHead returns position which precedes every event that is not in the stream yet.
+func (r *Repository) EventsHead(ctx context.Context) (models.EventPosition, error) {
+	var head models.EventPosition
+
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return head, err
+	}
+	defer conn.Release()
+
+	// Events with smaller transaction ids are already in the stream, ids start from one.
+	err = conn.QueryRow(ctx, "SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint").Scan(&head.Tx)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return head, err
+	}
+
+	return head, nil
+}
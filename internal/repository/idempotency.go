@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// ClaimIdempotencyKey stores the request under its key unless the key is already taken.
+// Returns nil if the key was claimed or the record it is taken by.
+// Expired records and requests in progress past their lease are taken over.
+func (r *Repository) ClaimIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) (*models.IdempotencyRecord, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Insert("idempotency_keys").
+		Columns("principal", "key", "request_hash", "created_at", "expires_at", "locked_until").
+		Values(record.Principal, record.Key, record.RequestHash, record.CreatedAt, record.ExpiresAt,
+			record.LockedUntil).
+		Suffix(`ON CONFLICT (principal, key) DO UPDATE
+			SET request_hash = EXCLUDED.request_hash, status = NULL, content_type = '', body = NULL,
+				created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at,
+				locked_until = EXCLUDED.locked_until
+			WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+				OR (idempotency_keys.status IS NULL AND idempotency_keys.locked_until <= EXCLUDED.created_at)`).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	tag, err := conn.Exec(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+
+	if tag.RowsAffected() == 1 {
+		return nil, nil
+	}
+
+	queryString, queryArgs = sq.Select("request_hash", "COALESCE(status, 0)", "content_type", "body",
+		"created_at", "expires_at").
+		From("idempotency_keys").
+		Where(sq.Eq{"principal": record.Principal, "key": record.Key}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	existing := &models.IdempotencyRecord{Principal: record.Principal, Key: record.Key}
+
+	err = conn.QueryRow(ctx, queryString, queryArgs...).
+		Scan(&existing.RequestHash, &existing.Status, &existing.ContentType, &existing.Body,
+			&existing.CreatedAt, &existing.ExpiresAt)
+
+	if err != nil {
+		// Key was released by the request holding it in between, so it is still in progress for the caller.
+		if errors.Is(err, pgx.ErrNoRows) {
+			existing.RequestHash = record.RequestHash
+			return existing, nil
+		}
+
+		r.logger.Error("Error while scanning query", zap.Error(err))
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// SaveIdempotencyKey stores response to the request claimed the key and keeps it until the record expires.
+// Nothing is stored if the lease was lost and the key was taken over by another request.
+func (r *Repository) SaveIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("idempotency_keys").
+		Set("status", record.Status).
+		Set("content_type", record.ContentType).
+		Set("body", record.Body).
+		Set("expires_at", record.ExpiresAt).
+		Set("locked_until", nil).
+		Where(sq.Eq{"principal": record.Principal, "key": record.Key, "locked_until": record.LockedUntil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey deletes the key held by the request, so the request can be retried with it.
+func (r *Repository) ReleaseIdempotencyKey(ctx context.Context, record *models.IdempotencyRecord) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Delete("idempotency_keys").
+		Where(sq.Eq{"principal": record.Principal, "key": record.Key, "locked_until": record.LockedUntil}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// PurgeIdempotencyKeys deletes keys expired before the moment.
+func (r *Repository) PurgeIdempotencyKeys(ctx context.Context, before time.Time) (int64, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return 0, err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Delete("idempotency_keys").
+		Where(sq.Lt{"expires_at": before}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	tag, err := conn.Exec(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
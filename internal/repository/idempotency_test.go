@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+func TestRepository_ClaimIdempotencyKey_StaleLease(t *testing.T) {
+	a := assert.New(t)
+	repo := testRepository(t)
+	ctx := context.Background()
+
+	key := uuid.NewString()
+	now := time.Now()
+
+	t.Cleanup(func() {
+		_, _ = repo.pool.Exec(ctx, "DELETE FROM idempotency_keys WHERE key = $1", key)
+	})
+
+	claim := func(hash string, at time.Time) *models.IdempotencyRecord {
+		return &models.IdempotencyRecord{
+			Principal:   "anonymous",
+			Key:         key,
+			RequestHash: hash,
+			CreatedAt:   at,
+			ExpiresAt:   at.Add(time.Minute),
+			LockedUntil: at.Add(time.Minute),
+		}
+	}
+
+	// Request holding the key crashed, so its lease lapsed without the key being released.
+	// It is kept until the ttl, as keys claimed before leases were, so only the lease lets it be taken over.
+	stale := claim("first", now.Add(-2*time.Minute))
+	stale.ExpiresAt = stale.CreatedAt.Add(24 * time.Hour)
+
+	existing, err := repo.ClaimIdempotencyKey(ctx, stale)
+	require.NoError(t, err)
+	a.Nil(existing)
+
+	retry := claim("first", now)
+	existing, err = repo.ClaimIdempotencyKey(ctx, retry)
+	require.NoError(t, err)
+	a.Nil(existing, "Key past its lease should be taken over")
+
+	existing, err = repo.ClaimIdempotencyKey(ctx, claim("first", now.Add(time.Second)))
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	a.Zero(existing.Status, "Key within its lease should stay in progress")
+
+	// Request that lost its lease neither stores its response nor frees the key.
+	stale.Status = http.StatusCreated
+	stale.ExpiresAt = now.Add(24 * time.Hour)
+	require.NoError(t, repo.SaveIdempotencyKey(ctx, stale))
+	require.NoError(t, repo.ReleaseIdempotencyKey(ctx, stale))
+
+	retry.Status = http.StatusOK
+	retry.Body = []byte(`{}`)
+	retry.ExpiresAt = now.Add(24 * time.Hour)
+	require.NoError(t, repo.SaveIdempotencyKey(ctx, retry))
+
+	existing, err = repo.ClaimIdempotencyKey(ctx, claim("first", now.Add(2*time.Minute)))
+	require.NoError(t, err)
+	require.NotNil(t, existing, "Stored response should be kept for ttl, not the lease")
+	a.Equal(http.StatusOK, existing.Status)
+	a.WithinDuration(retry.ExpiresAt, existing.ExpiresAt, time.Millisecond)
+}
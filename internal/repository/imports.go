@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// CreateImport stores new import job together with actor who started it,
+// so the job can be resumed by another instance on behalf of the actor.
+func (r *Repository) CreateImport(ctx context.Context, job *models.ImportJob) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx,
+		`INSERT INTO import_jobs (id, slug, status, total_rows, failed_rows, created_at, heartbeat_at,
+			actor, actor_name, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $6, $7, $8, $9);`,
+		job.ID, job.Slug, job.Status, job.Total, job.Failed, job.CreatedAt,
+		job.Audit.Actor, job.Audit.ActorName, job.Audit.RequestID,
+	)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// StartImport marks import job as running.
+func (r *Repository) StartImport(ctx context.Context, id string, now time.Time) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("import_jobs").
+		Set("status", models.JobRunning).
+		Set("started_at", sq.Expr("COALESCE(started_at, ?)", now)).
+		Set("heartbeat_at", now).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ProgressImport stores amount of rows already read by import job along with rows rejected among them.
+// Rows rejected again by resumed job are stored once.
+func (r *Repository) ProgressImport(ctx context.Context, id string, processed int, rowErrors []models.ImportRowError) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+
+		for _, rowError := range rowErrors {
+			var slugs, userIDs []string
+			if details := rowError.Details; details != nil {
+				slugs, userIDs = details.Slugs, details.UserIDs
+			}
+
+			batch.Queue(`INSERT INTO import_job_errors (job_id, row_number, user_id, error, code, slugs, user_ids)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				ON CONFLICT (job_id, row_number) DO NOTHING;`,
+				id, rowError.Row, rowError.UserID, rowError.Error, rowError.Code, slugs, userIDs)
+		}
+
+		batch.Queue(`UPDATE import_jobs
+			SET processed_rows = $2,
+				failed_rows = (SELECT COUNT(*) FROM import_job_errors WHERE job_id = $1)
+			WHERE id = $1;`, id, processed)
+
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		r.logger.Error("Error while storing import progress", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// FinishImport sets final status of import job. Total is the amount of rows read by then.
+func (r *Repository) FinishImport(ctx context.Context, id, status, message string, now time.Time) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("import_jobs").
+		Set("status", status).
+		Set("error", message).
+		Set("total_rows", sq.Expr("processed_rows")).
+		Set("finished_at", now).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// HeartbeatImports prolongs lease of queued and running import jobs.
+func (r *Repository) HeartbeatImports(ctx context.Context, ids []string, now time.Time) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("import_jobs").
+		Set("heartbeat_at", now).
+		Where(sq.Eq{"id": ids, "status": []string{models.JobQueued, models.JobRunning}}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ClaimStaleImports takes over queued and running import jobs with lease expired before staleBefore.
+// Each job is claimed by a single caller, as claiming renews its lease.
+// Jobs are returned with audit entry of the actor who started them.
+func (r *Repository) ClaimStaleImports(ctx context.Context, staleBefore, now time.Time) ([]models.ImportJob, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("import_jobs").
+		Set("heartbeat_at", now).
+		Where(sq.Eq{"status": []string{models.JobQueued, models.JobRunning}}).
+		Where(sq.Or{sq.Eq{"heartbeat_at": nil}, sq.Lt{"heartbeat_at": staleBefore}}).
+		Suffix(`RETURNING id::text, slug, status, total_rows, processed_rows, failed_rows, created_at,
+			actor, actor_name, request_id`).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.ImportJob
+
+	for rows.Next() {
+		job := models.ImportJob{Audit: &models.AuditEntry{Operation: models.AuditSegmentImport}}
+
+		err = rows.Scan(&job.ID, &job.Slug, &job.Status, &job.Total, &job.Processed, &job.Failed, &job.CreatedAt,
+			&job.Audit.Actor, &job.Audit.ActorName, &job.Audit.RequestID)
+		if err != nil {
+			r.logger.Error("Error while scanning query", zap.Error(err))
+			return nil, err
+		}
+
+		job.Audit.Slugs = []string{job.Slug}
+
+		jobs = append(jobs, job)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// GetImport returns import job with a page of its row errors or nil if there is no such job.
+func (r *Repository) GetImport(ctx context.Context, id string, limit, offset int) (*models.ImportJob, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	job := &models.ImportJob{}
+
+	queryString, queryArgs := sq.Select("id::text", "slug", "status", "total_rows", "processed_rows",
+		"failed_rows", "error", "created_at", "started_at", "finished_at").
+		From("import_jobs").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	err = conn.QueryRow(ctx, queryString, queryArgs...).
+		Scan(&job.ID, &job.Slug, &job.Status, &job.Total, &job.Processed,
+			&job.Failed, &job.Error, &job.CreatedAt, &job.StartedAt, &job.FinishedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+
+	queryString, queryArgs = sq.Select("row_number", "user_id", "error", "code", "slugs", "user_ids").
+		From("import_job_errors").
+		Where(sq.Eq{"job_id": id}).
+		OrderBy("row_number").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			rowError       models.ImportRowError
+			slugs, userIDs []string
+		)
+
+		err = rows.Scan(&rowError.Row, &rowError.UserID, &rowError.Error, &rowError.Code, &slugs, &userIDs)
+		if err != nil {
+			r.logger.Error("Error while scanning query", zap.Error(err))
+			return nil, err
+		}
+
+		if len(slugs) > 0 || len(userIDs) > 0 {
+			rowError.Details = &models.ProblemDetails{Slugs: slugs, UserIDs: userIDs}
+		}
+
+		job.Errors = append(job.Errors, rowError)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return nil, err
+	}
+
+	return job, nil
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// bulkChunkSize is the amount of memberships upserted by one statement in bulk operations.
+const bulkChunkSize = 5000
+
+// membership is a single user to segment assignment.
+type membership struct {
+	userID string
+	slug   string
+	expire time.Time
+}
+
+// upsertMembershipsQuery upserts memberships given as arrays of user ids, slugs and expiries.
+const upsertMembershipsQuery = `
+	WITH input AS (
+		SELECT DISTINCT ON (user_id, slug) user_id, slug, expired_at
+		FROM unnest($1::text[], $2::text[], $3::timestamptz[]) AS i(user_id, slug, expired_at)
+	), prev AS (
+		SELECT us.user_id, us.slug
+		FROM user_segments us
+		JOIN input ON input.user_id = us.user_id AND input.slug = us.slug
+		WHERE us.deleted_at IS NULL
+		  AND NOT us.expired
+		  AND (us.expired_at IS NULL OR us.expired_at > $4)
+	), lapsed AS (
+		SELECT us.user_id, us.slug, us.expired_at
+		FROM user_segments us
+		JOIN input ON input.user_id = us.user_id AND input.slug = us.slug
+		WHERE us.deleted_at IS NULL
+		  AND NOT us.expired
+		  AND us.expired_at <= $4
+	), upsert AS (
+		INSERT INTO user_segments (slug, user_id, created_at, expired_at)
+		SELECT slug, user_id, $4, expired_at
+		FROM input
+		ON CONFLICT (slug, user_id) DO UPDATE
+		SET created_at = CASE
+		        WHEN user_segments.deleted_at IS NULL
+		         AND NOT user_segments.expired
+		         AND (user_segments.expired_at IS NULL OR user_segments.expired_at > $4)
+		        THEN user_segments.created_at
+		        ELSE excluded.created_at
+		    END,
+		    expired_at = excluded.expired_at,
+		    deleted_at = NULL,
+		    expired = false
+		RETURNING user_id, slug, expired_at
+	), events AS (
+		INSERT INTO user_segment_events (user_id, slug, method, expire_at, created_at)
+		SELECT user_id, slug, 'expired', NULL, expired_at
+		FROM lapsed
+		UNION ALL
+		SELECT upsert.user_id,
+		       upsert.slug,
+		       CASE WHEN prev.slug IS NULL THEN 'added' ELSE 'updated' END,
+		       upsert.expired_at,
+		       $4
+		FROM upsert
+		LEFT JOIN prev ON prev.user_id = upsert.user_id AND prev.slug = upsert.slug
+		RETURNING user_id, slug, method, expire_at, created_at
+	), ` + outboxMembershipEvents
+
+// outboxMembershipEvents continues query having "events" CTE of inserted membership events.
+// It copies them into outbox, so the relay publishes them once the transaction commits,
+// and queues webhook deliveries for additions, removals and expirations in subscribed segments.
+// Query returns amount of events.
+const outboxMembershipEvents = `outboxed AS (
+		INSERT INTO outbox (kind, user_id, slug, method, expire_at, created_at)
+		SELECT 'membership', user_id, slug, method, expire_at, created_at
+		FROM events
+		ORDER BY user_id, created_at, slug
+		RETURNING id, user_id, slug, method, expire_at, created_at
+	), deliveries AS (
+		INSERT INTO webhook_deliveries (webhook_id, event_id, user_id, slug, method, expire_at, event_at,
+		                                status, next_attempt_at, created_at)
+		SELECT webhooks.id, outboxed.id, outboxed.user_id, outboxed.slug, outboxed.method,
+		       outboxed.expire_at, outboxed.created_at, 'pending', now(), now()
+		FROM outboxed
+		JOIN webhooks ON webhooks.slugs @> ARRAY[outboxed.slug]
+		WHERE outboxed.method IN ('added', 'deleted', 'expired')
+	)
+	SELECT COUNT(*) FROM outboxed;
+`
+
+// outboxSegmentEvent writes segment catalog change into outbox.
+const outboxSegmentEvent = `
+	INSERT INTO outbox (kind, slug, method, created_at)
+	VALUES ('segment', $1, $2, $3);
+`
+
+const (
+	// userLockClass namespaces user locks among advisory locks.
+	userLockClass = 0x75736572
+	// userLockStripes is the amount of advisory locks users are spread over. It bounds locks held
+	// by a transaction changing many users, so such transaction does not exhaust the lock table.
+	userLockStripes = 1024
+	// userCreationLock is the key, apart from user stripes, of the lock which serializes
+	// creation of users against percentage enrollment.
+	userCreationLock = userLockStripes
+)
+
+// lockUser serializes concurrent changes of user's memberships until the transaction ends.
+// Every path writing membership outbox events takes it before writing, so events of a user
+// get outbox ids in the order the changes are committed and the relay publishes them in that order.
+func lockUser(ctx context.Context, tx pgx.Tx, userID string) error {
+	return lockUsers(ctx, tx, []string{userID})
+}
+
+// lockUsers takes locks of all users at once. Locks are taken in ascending order,
+// so transactions locking intersecting sets of users do not deadlock. If some of the users
+// do not exist yet, creation lock is shared first, so the change waits for percentage enrollment
+// in progress and enrollment in turn waits for the change, see lockEnrollment.
+func lockUsers(ctx context.Context, tx pgx.Tx, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT pg_advisory_xact_lock_shared($1, $2)
+		WHERE EXISTS (
+			SELECT 1
+			FROM unnest($3::text[]) AS user_id
+			WHERE NOT EXISTS (SELECT 1 FROM users WHERE users.id = user_id)
+		);
+	`
+
+	if _, err := tx.Exec(ctx, query, userLockClass, userCreationLock, userIDs); err != nil {
+		return err
+	}
+
+	query = `
+		SELECT pg_advisory_xact_lock($1, stripe)
+		FROM (
+			SELECT DISTINCT hashtext(user_id) & $2 AS stripe
+			FROM unnest($3::text[]) AS user_id
+			ORDER BY stripe
+		) stripes;
+	`
+
+	_, err := tx.Exec(ctx, query, userLockClass, userLockStripes-1, userIDs)
+
+	return err
+}
+
+// lockEnrollment holds off creation of users until the transaction ends. Users created before
+// are committed by the time it is taken, so enrollment sees them, and users created after
+// are enrolled by the trigger, which then sees the committed segment. Changes of existing users
+// are not blocked, as the lock is always taken before user stripes, no deadlock is possible.
+func lockEnrollment(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1, $2);", userLockClass, userCreationLock)
+
+	return err
+}
+
+// enrollCreatedUsers fires deferred percentage enrollment of users created by the transaction
+// right away instead of at commit, so audit snapshot taken after it has their automatic memberships.
+// Explicit memberships are written by then, so they still win over automatic ones.
+func enrollCreatedUsers(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, "SET CONSTRAINTS enroll_user IMMEDIATE;")
+
+	return err
+}
+
+// upsertMemberships adds or prolongs memberships and records their history and outbox events.
+// Membership that was not active before is reactivated and gets an "added" event,
+// already active one only gets its expiry updated and an "updated" event.
+// Memberships that lapsed before the expiry worker noticed them get their "expired" event first.
+func upsertMemberships(ctx context.Context, tx pgx.Tx, memberships []membership, now time.Time) error {
+	if len(memberships) == 0 {
+		return nil
+	}
+
+	userIDs, slugs, expires := membershipArgs(memberships)
+
+	_, err := tx.Exec(ctx, upsertMembershipsQuery, userIDs, slugs, expires, now)
+
+	return err
+}
+
+// membershipArgs splits memberships into user id, slug and expiry arrays for upsertMembershipsQuery.
+func membershipArgs(memberships []membership) ([]string, []string, []*time.Time) {
+	var (
+		userIDs = make([]string, 0, len(memberships))
+		slugs   = make([]string, 0, len(memberships))
+		expires = make([]*time.Time, 0, len(memberships))
+	)
+
+	for _, m := range memberships {
+		userIDs = append(userIDs, m.userID)
+		slugs = append(slugs, m.slug)
+		expires = append(expires, nullableTime(m.expire))
+	}
+
+	return userIDs, slugs, expires
+}
+
+// deleteMemberships removes user's active memberships and records their history and outbox events.
+func deleteMemberships(ctx context.Context, tx pgx.Tx, userID string, slugs []string, now time.Time) error {
+	if len(slugs) == 0 {
+		return nil
+	}
+
+	query := `
+		WITH deleted AS (
+			UPDATE user_segments
+			SET deleted_at = $3
+			WHERE user_id = $1
+			  AND slug = ANY($2)
+			  AND deleted_at IS NULL
+			  AND NOT expired
+			  AND (expired_at IS NULL OR expired_at > $3)
+			RETURNING user_id, slug
+		), events AS (
+			INSERT INTO user_segment_events (user_id, slug, method, created_at)
+			SELECT user_id, slug, 'deleted', $3
+			FROM deleted
+			RETURNING user_id, slug, method, expire_at, created_at
+		), ` + outboxMembershipEvents
+
+	_, err := tx.Exec(ctx, query, userID, slugs, now)
+
+	return err
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// outboxRelayLock is the advisory lock key held by the relay which currently publishes outbox.
+const outboxRelayLock = 0x6f7574626f78
+
+// PublishOutbox passes up to limit oldest unpublished events to fn and marks them published if it succeeds.
+// Only one relay among all instances publishes at a time, others get zero events,
+// so events are published in the order they were written. If fn fails, events are passed again next time.
+func (r *Repository) PublishOutbox(ctx context.Context, limit int, fn func(events []models.OutboxEvent) error) (int, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return 0, err
+	}
+	defer conn.Release()
+
+	var published int
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		var locked bool
+
+		if err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock($1)", outboxRelayLock).Scan(&locked); err != nil {
+			return err
+		}
+
+		if !locked {
+			return nil
+		}
+
+		queryString, queryArgs := sq.Select("id", "kind", "COALESCE(user_id, '')", "slug", "method", "expire_at", "created_at").
+			From("outbox").
+			Where(sq.Eq{"published_at": nil}).
+			OrderBy("id").
+			Limit(uint64(limit)).
+			PlaceholderFormat(sq.Dollar).
+			MustSql()
+
+		rows, err := tx.Query(ctx, queryString, queryArgs...)
+		if err != nil {
+			return err
+		}
+
+		events, err := pgx.CollectRows(rows, scanOutboxEvent)
+		if err != nil {
+			return err
+		}
+
+		if len(events) == 0 {
+			return nil
+		}
+
+		if err = fn(events); err != nil {
+			return err
+		}
+
+		ids := make([]int64, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+
+		queryString, queryArgs = sq.Update("outbox").
+			Set("published_at", time.Now()).
+			Where(sq.Eq{"id": ids}).
+			PlaceholderFormat(sq.Dollar).
+			MustSql()
+
+		if _, err = tx.Exec(ctx, queryString, queryArgs...); err != nil {
+			return err
+		}
+
+		published = len(events)
+
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Error while publishing outbox", zap.Error(err))
+		return 0, err
+	}
+
+	return published, nil
+}
+
+// PurgeOutbox deletes events published before the moment.
+func (r *Repository) PurgeOutbox(ctx context.Context, before time.Time) (int64, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return 0, err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Delete("outbox").
+		Where(sq.Lt{"published_at": before}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	tag, err := conn.Exec(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func scanOutboxEvent(row pgx.CollectableRow) (models.OutboxEvent, error) {
+	var event models.OutboxEvent
+
+	err := row.Scan(&event.ID, &event.Kind, &event.UserID, &event.Slug, &event.Method, &event.ExpireAt, &event.Timestamp)
+
+	return event, err
+}
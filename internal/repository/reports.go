@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// CreateReportJob stores new report job.
+func (r *Repository) CreateReportJob(ctx context.Context, job *models.ReportJob) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Insert("report_jobs").
+		Columns("id", "report_id", "year", "month", "format", "status", "range_from", "range_to",
+			"slugs", "user_ids", "methods", "created_at", "heartbeat_at").
+		Values(job.ID, job.ReportID, job.Year, job.Month, job.Format, job.Status, job.From, job.To,
+			job.Slugs, job.UserIDs, job.Methods, job.CreatedAt, job.CreatedAt).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// StartReportJob marks report job as running.
+func (r *Repository) StartReportJob(ctx context.Context, id string, now time.Time) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("report_jobs").
+		Set("status", models.JobRunning).
+		Set("started_at", now).
+		Set("heartbeat_at", now).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ProgressReportJob stores amount of rows already written by report job.
+func (r *Repository) ProgressReportJob(ctx context.Context, id string, rows int) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("report_jobs").
+		Set("row_count", rows).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// FinishReportJob stores outcome of report job.
+func (r *Repository) FinishReportJob(ctx context.Context, job *models.ReportJob) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("report_jobs").
+		Set("status", job.Status).
+		Set("row_count", job.Rows).
+		Set("range_to", job.To).
+		Set("generated_at", job.GeneratedAt).
+		Set("file", job.File).
+		Set("error", job.Error).
+		Set("finished_at", job.FinishedAt).
+		Where(sq.Eq{"id": job.ID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// PendingReportJob returns queued or running job of the report in the format or nil if there is none.
+// Jobs with lease expired before staleBefore are abandoned and are not returned.
+func (r *Repository) PendingReportJob(ctx context.Context, reportID, format string, staleBefore time.Time) (*models.ReportJob, error) {
+	return r.reportJob(ctx, reportJobQuery().
+		Where(sq.Eq{"report_id": reportID, "format": format, "status": []string{models.JobQueued, models.JobRunning}}).
+		Where(sq.GtOrEq{"heartbeat_at": staleBefore}).
+		OrderBy("created_at DESC").
+		Limit(1))
+}
+
+// HeartbeatReportJobs prolongs lease of queued and running report jobs.
+func (r *Repository) HeartbeatReportJobs(ctx context.Context, ids []string, now time.Time) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("report_jobs").
+		Set("heartbeat_at", now).
+		Where(sq.Eq{"id": ids, "status": []string{models.JobQueued, models.JobRunning}}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ClaimStaleReportJobs takes over queued and running report jobs with lease expired before staleBefore.
+// Each job is claimed by a single caller, as claiming renews its lease.
+func (r *Repository) ClaimStaleReportJobs(ctx context.Context, staleBefore, now time.Time) ([]models.ReportJob, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("report_jobs").
+		Set("heartbeat_at", now).
+		Where(sq.Eq{"status": []string{models.JobQueued, models.JobRunning}}).
+		Where(sq.Or{sq.Eq{"heartbeat_at": nil}, sq.Lt{"heartbeat_at": staleBefore}}).
+		Suffix("RETURNING " + strings.Join(reportJobColumns, ", ")).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.ReportJob
+
+	for rows.Next() {
+		var job models.ReportJob
+
+		if err = scanReportJob(rows, &job); err != nil {
+			r.logger.Error("Error while scanning query", zap.Error(err))
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// GetReportJob returns report job or nil if there is no such job.
+func (r *Repository) GetReportJob(ctx context.Context, id string) (*models.ReportJob, error) {
+	return r.reportJob(ctx, reportJobQuery().Where(sq.Eq{"id": id}))
+}
+
+// LatestReportJob returns the last successfully finished job of the report in the format or nil if there is none.
+func (r *Repository) LatestReportJob(ctx context.Context, reportID, format string) (*models.ReportJob, error) {
+	return r.reportJob(ctx, reportJobQuery().
+		Where(sq.Eq{"report_id": reportID, "format": format, "status": models.JobDone}).
+		OrderBy("finished_at DESC").
+		Limit(1))
+}
+
+func (r *Repository) reportJob(ctx context.Context, query sq.SelectBuilder) (*models.ReportJob, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	job := &models.ReportJob{}
+
+	queryString, queryArgs := query.PlaceholderFormat(sq.Dollar).MustSql()
+
+	err = scanReportJob(conn.QueryRow(ctx, queryString, queryArgs...), job)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// reportJobColumns are report job columns. Jobs stored before ranges were tracked
+// are treated as covering their month and generated when they finished.
+var reportJobColumns = []string{
+	"id::text", "COALESCE(report_id, '')", "year", "month", "format", "status", "row_count",
+	"COALESCE(range_from, make_timestamptz(year, month, 1, 0, 0, 0, 'UTC'))",
+	"COALESCE(range_to, make_timestamptz(year, month, 1, 0, 0, 0, 'UTC') + interval '1 month')",
+	"slugs", "user_ids", "methods",
+	"COALESCE(generated_at, CASE WHEN status = 'done' THEN finished_at END)",
+	"file", "error", "created_at", "started_at", "finished_at",
+}
+
+// reportJobQuery selects report jobs.
+func reportJobQuery() sq.SelectBuilder {
+	return sq.Select(reportJobColumns...).From("report_jobs")
+}
+
+// scanReportJob reads report job selected with reportJobColumns.
+func scanReportJob(row pgx.Row, job *models.ReportJob) error {
+	return row.Scan(&job.ID, &job.ReportID, &job.Year, &job.Month, &job.Format, &job.Status, &job.Rows, &job.From, &job.To,
+		&job.Slugs, &job.UserIDs, &job.Methods, &job.GeneratedAt, &job.File, &job.Error,
+		&job.CreatedAt, &job.StartedAt, &job.FinishedAt)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/fx"
@@ -36,9 +39,10 @@ func New(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) *Repository
 	}
 
 	var (
-		schema []byte
-		pool   *pgxpool.Pool
-		repo   = &Repository{
+		schema     []byte
+		migrations []string
+		pool       *pgxpool.Pool
+		repo       = &Repository{
 			pool:   pool,
 			logger: logger,
 		}
@@ -52,16 +56,25 @@ func New(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) *Repository
 				return err
 			}
 
-			schema, err = os.ReadFile(config.Database.MigrationPath)
+			migrations, err = migrationFiles(config.Database.MigrationPath)
 			if err != nil {
 				logger.Error("Error occurred while getting migration schema", zap.Error(err))
 				return err
 			}
 
-			_, err = pool.Exec(context.Background(), string(schema))
-			if err != nil {
-				logger.Error("Error occurred while executing schema", zap.Error(err))
-				return err
+			for _, migration := range migrations {
+				schema, err = os.ReadFile(migration)
+				if err != nil {
+					logger.Error("Error occurred while getting migration schema", zap.Error(err))
+					return err
+				}
+
+				_, err = pool.Exec(context.Background(), string(schema))
+				if err != nil {
+					logger.Error("Error occurred while executing schema",
+						zap.String("migration", migration), zap.Error(err))
+					return err
+				}
 			}
 
 			repo.pool = pool
@@ -76,3 +89,34 @@ func New(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) *Repository
 
 	return repo
 }
+
+// migrationFiles returns the migration itself if path is a file,
+// or every up migration in the directory ordered by version.
+func migrationFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.up.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// nullableTime maps zero time to NULL.
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// envTestDatabase holds URL of the database repository tests run against.
+const envTestDatabase = "TEST_DATABASE_URL"
+
+// testRepository connects to the test database and migrates it, the test is skipped without one.
+func testRepository(t *testing.T) *Repository {
+	t.Helper()
+
+	uri := os.Getenv(envTestDatabase)
+	if uri == "" {
+		t.Skipf("%s is not set", envTestDatabase)
+	}
+
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, uri)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	migrations, err := migrationFiles("../../migration")
+	require.NoError(t, err)
+
+	for _, migration := range migrations {
+		schema, err := os.ReadFile(migration)
+		require.NoError(t, err)
+
+		_, err = pool.Exec(ctx, string(schema))
+		require.NoError(t, err, migration)
+	}
+
+	return &Repository{pool: pool, logger: zap.NewNop()}
+}
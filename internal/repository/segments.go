@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -13,7 +14,9 @@ import (
 	"github.com/dupreehkuda/avito-segments/internal/models"
 )
 
-func (r *Repository) Add(ctx context.Context, segment *models.Segment) error {
+// Add creates new segment. If segment has a percentage, share of existing users
+// is enrolled into it in the same transaction. Created segment is recorded to audit log.
+func (r *Repository) Add(ctx context.Context, segment *models.Segment, audit *models.AuditEntry) error {
 	conn, err := r.pool.Acquire(ctx)
 	if err != nil {
 		r.logger.Error("Error while acquiring connection", zap.Error(err))
@@ -21,19 +24,91 @@ func (r *Repository) Add(ctx context.Context, segment *models.Segment) error {
 	}
 	defer conn.Release()
 
-	queryString, queryArgs := sq.Insert("segments").Columns("slug", "description", "created_at").
-		Values(segment.Slug, segment.Description, time.Now()).
-		PlaceholderFormat(sq.Dollar).
-		MustSql()
+	now := time.Now()
+
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		queryString, queryArgs := sq.Insert("segments").Columns("slug", "description", "percentage", "created_at").
+			Values(segment.Slug, segment.Description, segment.Percentage, now).
+			PlaceholderFormat(sq.Dollar).
+			MustSql()
+
+		if _, err = tx.Exec(ctx, queryString, queryArgs...); err != nil {
+			return err
+		}
+
+		if err = recordSegmentEvent(ctx, tx, segment.Slug, models.SegmentCreated, now); err != nil {
+			return err
+		}
+
+		if segment.Percentage != 0 {
+			if err = r.enrollPercentage(ctx, tx, segment.Slug, segment.Percentage, now); err != nil {
+				return err
+			}
+		}
+
+		if audit.After, err = segmentSnapshot(ctx, tx, segment.Slug); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, audit, now)
+	})
+}
+
+// enrollPercentage adds users whose bucket falls below percentage to the segment.
+// Bucket is a stable hash of user id and slug, so the same user always gets the same decision.
+// Only stripes of enrolled users are locked, creation of new users waits for the transaction.
+func (r *Repository) enrollPercentage(ctx context.Context, tx pgx.Tx, slug string, percentage int, now time.Time) error {
+	if err := lockEnrollment(ctx, tx); err != nil {
+		r.logger.Error("Error while locking enrollment", zap.Error(err))
+		return err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT id
+		FROM users
+		WHERE segment_bucket(id, $1) < $2
+		  AND NOT EXISTS (SELECT 1 FROM user_segments WHERE slug = $1 AND user_id = users.id);
+	`, slug, percentage)
+	if err != nil {
+		r.logger.Error("Error while selecting users to enroll", zap.Error(err))
+		return err
+	}
+
+	userIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil || len(userIDs) == 0 {
+		return err
+	}
+
+	if err = lockUsers(ctx, tx, userIDs); err != nil {
+		r.logger.Error("Error while locking users", zap.Error(err))
+		return err
+	}
 
-	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+	// Users removed from the segment meanwhile keep their membership row, so they are not enrolled again.
+	query := `
+		WITH enrolled AS (
+			INSERT INTO user_segments (slug, user_id, created_at)
+			SELECT $1, user_id, $3
+			FROM unnest($2::text[]) AS user_id
+			ON CONFLICT (slug, user_id) DO NOTHING
+			RETURNING user_id, slug
+		), events AS (
+			INSERT INTO user_segment_events (user_id, slug, method, created_at)
+			SELECT user_id, slug, 'added', $3
+			FROM enrolled
+			RETURNING user_id, slug, method, expire_at, created_at
+		), ` + outboxMembershipEvents
+
+	if _, err = tx.Exec(ctx, query, slug, userIDs, now); err != nil {
+		r.logger.Error("Error while enrolling users", zap.Error(err))
 		return err
 	}
 
 	return nil
 }
 
-func (r *Repository) Delete(ctx context.Context, slug string) error {
+// Delete marks segment as deleted and records it to audit log.
+func (r *Repository) Delete(ctx context.Context, slug string, audit *models.AuditEntry) error {
 	conn, err := r.pool.Acquire(ctx)
 	if err != nil {
 		r.logger.Error("Error while acquiring connection", zap.Error(err))
@@ -41,17 +116,189 @@ func (r *Repository) Delete(ctx context.Context, slug string) error {
 	}
 	defer conn.Release()
 
+	now := time.Now()
+
 	queryString, queryArgs := sq.Update("segments").
-		Set("deleted_at", sq.Expr("NOW()")).
+		Set("deleted_at", now).
 		Where(sq.Eq{"slug": slug}).
 		PlaceholderFormat(sq.Dollar).
 		MustSql()
 
-	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if audit.Before, err = segmentSnapshot(ctx, tx, slug); err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, queryString, queryArgs...); err != nil {
+			return err
+		}
+
+		if err = recordSegmentEvent(ctx, tx, slug, models.SegmentDeleted, now); err != nil {
+			return err
+		}
+
+		if audit.After, err = segmentSnapshot(ctx, tx, slug); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, audit, now)
+	})
+}
+
+// Update changes segment's metadata, enrolling users newly covered by raised percentage,
+// and records segment before and after to audit log. Lowered percentage removes nobody:
+// memberships do not tell enrolled users from ones added explicitly, so members stay.
+func (r *Repository) Update(ctx context.Context, slug string, req *models.SegmentUpdateRequest, audit *models.AuditEntry) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
 		return err
 	}
+	defer conn.Release()
 
-	return nil
+	query := sq.Update("segments").
+		Where(sq.Eq{"slug": slug}).
+		PlaceholderFormat(sq.Dollar)
+
+	if req.Description != nil {
+		query = query.Set("description", *req.Description)
+	}
+
+	if req.Percentage != nil {
+		query = query.Set("percentage", *req.Percentage)
+	}
+
+	queryString, queryArgs := query.MustSql()
+
+	now := time.Now()
+
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if audit.Before, err = segmentSnapshot(ctx, tx, slug); err != nil {
+			return err
+		}
+
+		var percentage int
+
+		err = tx.QueryRow(ctx, "SELECT percentage FROM segments WHERE slug = $1 FOR UPDATE;", slug).Scan(&percentage)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, queryString, queryArgs...); err != nil {
+			return err
+		}
+
+		if req.Percentage != nil && *req.Percentage > percentage {
+			if err = r.enrollPercentage(ctx, tx, slug, *req.Percentage, now); err != nil {
+				return err
+			}
+		}
+
+		if audit.After, err = segmentSnapshot(ctx, tx, slug); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, audit, now)
+	})
+}
+
+// Restore clears segment's deletion. Unless restoreMembers is set, memberships that were active
+// at the moment of deletion are deleted as of the restore: history is never written in the past,
+// so reports already generated for earlier periods stay correct. Segment before and after is recorded to audit log.
+func (r *Repository) Restore(ctx context.Context, slug string, restoreMembers bool, audit *models.AuditEntry) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	now := time.Now()
+
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if audit.Before, err = segmentSnapshot(ctx, tx, slug); err != nil {
+			return err
+		}
+
+		if !restoreMembers {
+			rows, err := tx.Query(ctx, `
+				SELECT user_id
+				FROM user_segments
+				WHERE slug = $1
+				  AND deleted_at IS NULL
+				  AND NOT expired;
+			`, slug)
+			if err != nil {
+				return err
+			}
+
+			userIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			if err != nil {
+				return err
+			}
+
+			if err = lockUsers(ctx, tx, userIDs); err != nil {
+				return err
+			}
+
+			query := `
+				WITH deleted AS (
+					UPDATE user_segments
+					SET deleted_at = $2
+					WHERE slug = $1
+					  AND deleted_at IS NULL
+					  AND NOT expired
+					  AND user_id = ANY($3)
+					RETURNING user_id, slug, deleted_at
+				), events AS (
+					INSERT INTO user_segment_events (user_id, slug, method, created_at)
+					SELECT user_id, slug, 'deleted', deleted_at
+					FROM deleted
+					RETURNING user_id, slug, method, expire_at, created_at
+				), ` + outboxMembershipEvents
+
+			if _, err = tx.Exec(ctx, query, slug, now, userIDs); err != nil {
+				return err
+			}
+		}
+
+		queryString, queryArgs := sq.Update("segments").
+			Set("deleted_at", nil).
+			Where(sq.Eq{"slug": slug}).
+			PlaceholderFormat(sq.Dollar).
+			MustSql()
+
+		if _, err = tx.Exec(ctx, queryString, queryArgs...); err != nil {
+			return err
+		}
+
+		if err = recordSegmentEvent(ctx, tx, slug, models.SegmentRestored, now); err != nil {
+			return err
+		}
+
+		if audit.After, err = segmentSnapshot(ctx, tx, slug); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, audit, now)
+	})
+}
+
+// recordSegmentEvent appends segment catalog change to its history and outbox.
+func recordSegmentEvent(ctx context.Context, tx pgx.Tx, slug, method string, now time.Time) error {
+	queryString, queryArgs := sq.Insert("segment_events").
+		Columns("slug", "method", "created_at").
+		Values(slug, method, now).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err := tx.Exec(ctx, queryString, queryArgs...); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(ctx, outboxSegmentEvent, slug, method, now)
+
+	return err
 }
 
 func (r *Repository) Get(ctx context.Context, slug string) (*models.Segment, error) {
@@ -65,14 +312,14 @@ func (r *Repository) Get(ctx context.Context, slug string) (*models.Segment, err
 	res := &models.Segment{}
 	var deletedAt sql.NullTime
 
-	queryString, queryArgs := sq.Select("slug", "description", "deleted_at").
+	queryString, queryArgs := sq.Select("slug", "description", "percentage", "deleted_at").
 		From("segments").
 		Where(sq.Eq{"slug": slug}).
 		PlaceholderFormat(sq.Dollar).
 		MustSql()
 
 	err = conn.QueryRow(ctx, queryString, queryArgs...).
-		Scan(&res.Slug, &res.Description, &deletedAt)
+		Scan(&res.Slug, &res.Description, &res.Percentage, &deletedAt)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -87,28 +334,233 @@ func (r *Repository) Get(ctx context.Context, slug string) (*models.Segment, err
 	return res, nil
 }
 
-func (r *Repository) Count(ctx context.Context, slugs []string) (int, error) {
+// List returns a page of segment catalog and total amount of segments matching filter.
+func (r *Repository) List(ctx context.Context, filter *models.SegmentFilter) ([]models.SegmentInfo, int, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, 0, err
+	}
+	defer conn.Release()
+
+	query := segmentInfoQuery(time.Now()).
+		Column("COUNT(*) OVER()").
+		OrderBy("segments.slug").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+
+	switch filter.Status {
+	case models.SegmentStatusActive:
+		query = query.Where(sq.Eq{"segments.deleted_at": nil})
+	case models.SegmentStatusDeleted:
+		query = query.Where(sq.NotEq{"segments.deleted_at": nil})
+	}
+
+	if filter.Prefix != "" {
+		query = query.Where(sq.Like{"segments.slug": escapeLike(filter.Prefix) + "%"})
+	}
+
+	queryString, queryArgs := query.PlaceholderFormat(sq.Dollar).MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		total int
+		resp  = make([]models.SegmentInfo, 0)
+	)
+
+	for rows.Next() {
+		var info models.SegmentInfo
+
+		err = rows.Scan(&info.Slug, &info.Description, &info.Percentage, &info.CreatedAt, &info.DeletedAt, &info.Members, &total)
+		if err != nil {
+			r.logger.Error("Error while scanning query", zap.Error(err))
+			return nil, 0, err
+		}
+
+		resp = append(resp, info)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return resp, total, nil
+}
+
+// Info returns segment with its current member count or nil if there is no such segment.
+func (r *Repository) Info(ctx context.Context, slug string) (*models.SegmentInfo, error) {
 	conn, err := r.pool.Acquire(ctx)
 	if err != nil {
 		r.logger.Error("Error while acquiring connection", zap.Error(err))
-		return 0, err
+		return nil, err
 	}
 	defer conn.Release()
 
-	var count int
+	queryString, queryArgs := segmentInfoQuery(time.Now()).
+		Where(sq.Eq{"segments.slug": slug}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	info := &models.SegmentInfo{}
+
+	err = conn.QueryRow(ctx, queryString, queryArgs...).
+		Scan(&info.Slug, &info.Description, &info.Percentage, &info.CreatedAt, &info.DeletedAt, &info.Members)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// segmentInfoQuery selects segments along with amount of their active members.
+func segmentInfoQuery(now time.Time) sq.SelectBuilder {
+	members := sq.Select("COUNT(*)").
+		From("user_segments").
+		Where("user_segments.slug = segments.slug").
+		Where(activeMembership(now))
+
+	return sq.Select("segments.slug", "segments.description", "segments.percentage",
+		"segments.created_at", "segments.deleted_at").
+		Column(sq.Alias(members, "members")).
+		From("segments")
+}
+
+// escapeLike escapes LIKE wildcards in user input.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// Existing returns those of provided slugs that exist in catalog.
+func (r *Repository) Existing(ctx context.Context, slugs []string) ([]string, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
 
-	queryString, queryArgs := sq.Select("COUNT(*)").
+	queryString, queryArgs := sq.Select("slug").
 		From("segments").
 		Where(sq.Eq{"slug": slugs}).
 		PlaceholderFormat(sq.Dollar).
 		MustSql()
 
-	err = conn.QueryRow(ctx, queryString, queryArgs...).
-		Scan(&count)
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
 
+	existing, err := pgx.CollectRows(rows, pgx.RowTo[string])
 	if err != nil {
-		return 0, err
+		r.logger.Error("Error while scanning query", zap.Error(err))
+		return nil, err
 	}
 
-	return count, nil
+	return existing, nil
+}
+
+// Members returns up to limit active members of the segment with user id greater than after.
+func (r *Repository) Members(ctx context.Context, slug, after string, limit int) ([]models.SegmentMember, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	query := membersQuery(slug, time.Now()).Limit(uint64(limit))
+
+	if after != "" {
+		query = query.Where(sq.Gt{"user_segments.user_id": after})
+	}
+
+	queryString, queryArgs := query.PlaceholderFormat(sq.Dollar).MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := make([]models.SegmentMember, 0, limit)
+
+	for rows.Next() {
+		var member models.SegmentMember
+
+		if err = rows.Scan(&member.UserID, &member.CreatedAt, &member.ExpiredAt); err != nil {
+			r.logger.Error("Error while scanning query", zap.Error(err))
+			return nil, err
+		}
+
+		resp = append(resp, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// StreamMembers passes every active member of the segment to fn without loading them all in memory.
+func (r *Repository) StreamMembers(ctx context.Context, slug string, fn func(member models.SegmentMember) error) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := membersQuery(slug, time.Now()).PlaceholderFormat(sq.Dollar).MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member models.SegmentMember
+
+		if err = rows.Scan(&member.UserID, &member.CreatedAt, &member.ExpiredAt); err != nil {
+			r.logger.Error("Error while scanning query", zap.Error(err))
+			return err
+		}
+
+		if err = fn(member); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// membersQuery selects active members of the segment ordered by user id.
+func membersQuery(slug string, now time.Time) sq.SelectBuilder {
+	return sq.Select("user_segments.user_id", "user_segments.created_at", "user_segments.expired_at").
+		From("user_segments").
+		Where(sq.Eq{"user_segments.slug": slug}).
+		Where(activeMembership(now)).
+		OrderBy("user_segments.user_id")
 }
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+func TestRepository_Update_Percentage(t *testing.T) {
+	a := assert.New(t)
+	repo := testRepository(t)
+	ctx := context.Background()
+
+	suffix := strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", ""))
+	slug, plain := "TEST_PERCENTAGE_"+suffix, "TEST_PLAIN_"+suffix
+
+	audit := func(operation string) *models.AuditEntry {
+		return &models.AuditEntry{Actor: "test", Operation: operation}
+	}
+
+	rows, err := repo.pool.Query(ctx, `
+		INSERT INTO users (id, created_at)
+		SELECT gen_random_uuid()::text, now()
+		FROM generate_series(1, 200)
+		RETURNING id;
+	`)
+	require.NoError(t, err)
+
+	userIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	require.NoError(t, err)
+
+	members := func() int {
+		var count int
+
+		err := repo.pool.QueryRow(ctx, `
+			SELECT COUNT(*)
+			FROM user_segments
+			WHERE slug = $1
+			  AND user_id = ANY($2)
+			  AND deleted_at IS NULL;
+		`, slug, userIDs).Scan(&count)
+		require.NoError(t, err)
+
+		return count
+	}
+
+	covered := func(percentage int) int {
+		var count int
+
+		err := repo.pool.QueryRow(ctx, `
+			SELECT COUNT(*)
+			FROM unnest($2::text[]) AS user_id
+			WHERE segment_bucket(user_id, $1) < $3;
+		`, slug, userIDs, percentage).Scan(&count)
+		require.NoError(t, err)
+
+		return count
+	}
+
+	require.NoError(t, repo.Add(ctx, &models.Segment{Slug: slug, Percentage: 50}, audit(models.AuditSegmentAdd)))
+	require.NoError(t, repo.Add(ctx, &models.Segment{Slug: plain}, audit(models.AuditSegmentAdd)))
+
+	// Segments are left deleted, so users created later are not enrolled into them.
+	t.Cleanup(func() {
+		_ = repo.Delete(ctx, slug, audit(models.AuditSegmentDelete))
+		_ = repo.Delete(ctx, plain, audit(models.AuditSegmentDelete))
+	})
+
+	a.Equal(covered(50), members())
+
+	percentage := 20
+	require.NoError(t, repo.Update(ctx, slug, &models.SegmentUpdateRequest{Percentage: &percentage}, audit(models.AuditSegmentUpdate)))
+	a.Equal(covered(50), members(), "Lowered percentage should keep enrolled users")
+
+	percentage = 80
+	require.NoError(t, repo.Update(ctx, slug, &models.SegmentUpdateRequest{Percentage: &percentage}, audit(models.AuditSegmentUpdate)))
+	a.Equal(covered(80), members(), "Raised percentage should enroll newly covered users")
+
+	percentage = 100
+	require.NoError(t, repo.Update(ctx, slug, &models.SegmentUpdateRequest{Percentage: &percentage}, audit(models.AuditSegmentUpdate)))
+
+	// User created by explicit assignment is enrolled automatically in the same transaction.
+	entry := audit(models.AuditUserSetSegments)
+	userID := uuid.NewString()
+
+	err = repo.SetSegments(ctx, &models.UserSetRequest{UserID: userID, Segments: []models.UserSegment{{Slug: plain}}}, entry)
+	require.NoError(t, err)
+
+	a.Contains(string(entry.After), `"slug": "`+slug+`"`, "Automatic membership should be in audit snapshot")
+	a.Contains(string(entry.After), `"slug": "`+plain+`"`)
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// dailyStatsQuery joins every day of the range with its events, so days without events are kept.
+// The series is generated over UTC timestamps: stepping timestamptz by a day follows the session
+// TimeZone, and days around a DST switch would be 23 or 25 hours long.
+const dailyStatsQuery = `
+SELECT days.day AT TIME ZONE 'UTC',
+       COUNT(*) FILTER (WHERE events.method = 'added'),
+       COUNT(*) FILTER (WHERE events.method = 'deleted'),
+       COUNT(*) FILTER (WHERE events.method = 'expired')
+FROM generate_series(
+         $1::timestamptz AT TIME ZONE 'UTC',
+         $2::timestamptz AT TIME ZONE 'UTC' - interval '1 day',
+         interval '1 day'
+     ) AS days(day)
+LEFT JOIN user_segment_events AS events
+       ON events.slug = $3
+      AND events.created_at >= days.day AT TIME ZONE 'UTC'
+      AND events.created_at < (days.day + interval '1 day') AT TIME ZONE 'UTC'
+GROUP BY days.day
+ORDER BY days.day;`
+
+// DailyStats counts membership events of the segment for every UTC day in [from, to).
+// Days without events are present with zero counts.
+func (r *Repository) DailyStats(ctx context.Context, slug string, from, to time.Time) ([]models.SegmentDayStats, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	rows, err := conn.Query(ctx, dailyStatsQuery, from, to, slug)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := make([]models.SegmentDayStats, 0)
+
+	for rows.Next() {
+		var day models.SegmentDayStats
+
+		if err = rows.Scan(&day.Day, &day.Added, &day.Deleted, &day.Expired); err != nil {
+			r.logger.Error("Error while scanning query", zap.Error(err))
+			return nil, err
+		}
+
+		day.Day = day.Day.UTC()
+		day.Net = day.Added - day.Deleted - day.Expired
+		resp = append(resp, day)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Ranking returns a page of active segments ordered by amount of active members, the biggest first.
+func (r *Repository) Ranking(ctx context.Context, limit, offset int) ([]models.SegmentInfo, int, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, 0, err
+	}
+	defer conn.Release()
+
+	members := sq.Select("user_segments.slug", "COUNT(*) AS members").
+		From("user_segments").
+		Where(activeMembership(time.Now())).
+		GroupBy("user_segments.slug")
+
+	queryString, queryArgs := sq.Select("segments.slug", "segments.description", "segments.percentage",
+		"segments.created_at", "segments.deleted_at", "COALESCE(sizes.members, 0) AS size", "COUNT(*) OVER()").
+		From("segments").
+		JoinClause(members.Prefix("LEFT JOIN (").Suffix(") AS sizes ON sizes.slug = segments.slug")).
+		Where(sq.Eq{"segments.deleted_at": nil}).
+		OrderBy("size DESC", "segments.slug").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		total int
+		resp  = make([]models.SegmentInfo, 0)
+	)
+
+	for rows.Next() {
+		var info models.SegmentInfo
+
+		err = rows.Scan(&info.Slug, &info.Description, &info.Percentage, &info.CreatedAt, &info.DeletedAt, &info.Members, &total)
+		if err != nil {
+			r.logger.Error("Error while scanning query", zap.Error(err))
+			return nil, 0, err
+		}
+
+		resp = append(resp, info)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return resp, total, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRepository_DailyStats_SessionTimeZone(t *testing.T) {
+	a := assert.New(t)
+	repo := testRepository(t)
+	ctx := context.Background()
+
+	// Session in a zone with DST, 2023-03-26 is 23 hours long there.
+	config, err := pgxpool.ParseConfig(repo.pool.Config().ConnString())
+	require.NoError(t, err)
+	config.ConnConfig.RuntimeParams["timezone"] = "Europe/Berlin"
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	berlin := &Repository{pool: pool, logger: zap.NewNop()}
+
+	slug := "TEST_STATS_" + strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", ""))
+	from := time.Date(2023, time.March, 25, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 4)
+
+	// Events right before the end of every UTC day.
+	_, err = repo.pool.Exec(ctx, `
+		INSERT INTO user_segment_events (user_id, slug, method, created_at)
+		SELECT 'TEST_USER', $1, 'added', $2::timestamptz + (day + 1) * interval '24 hours' - interval '1 minute'
+		FROM generate_series(0, 3) AS day;
+	`, slug, from)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, _ = repo.pool.Exec(ctx, `DELETE FROM user_segment_events WHERE slug = $1;`, slug)
+	})
+
+	stats, err := berlin.DailyStats(ctx, slug, from, to)
+	require.NoError(t, err)
+	require.Len(t, stats, 4)
+
+	for i, day := range stats {
+		a.Equal(from.AddDate(0, 0, i), day.Day)
+		a.Equal(1, day.Added, day.Day.String())
+	}
+}
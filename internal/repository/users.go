@@ -7,13 +7,15 @@ import (
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
 	errs "github.com/dupreehkuda/avito-segments/internal/errors"
 	"github.com/dupreehkuda/avito-segments/internal/models"
 )
 
-func (r *Repository) SetSegments(ctx context.Context, segments *models.UserSetRequest) error {
+// SetSegments adds or prolongs user's memberships and records user's segments before and after to audit log.
+func (r *Repository) SetSegments(ctx context.Context, segments *models.UserSetRequest, audit *models.AuditEntry) error {
 	conn, err := r.pool.Acquire(ctx)
 	if err != nil {
 		r.logger.Error("Error while acquiring connection", zap.Error(err))
@@ -21,17 +23,40 @@ func (r *Repository) SetSegments(ctx context.Context, segments *models.UserSetRe
 	}
 	defer conn.Release()
 
-	query := sq.Insert("user_segments").
-		Columns("slug", "user_id", "created_at", "expired_at").
-		Suffix("ON CONFLICT (slug, user_id) DO UPDATE SET expired_at = excluded.expired_at").
-		PlaceholderFormat(sq.Dollar)
-
+	memberships := make([]membership, 0, len(segments.Segments))
 	for _, segment := range segments.Segments {
-		query = query.Values(segment.Slug, segments.UserID, time.Now(), segment.Expire)
+		memberships = append(memberships, membership{
+			userID: segments.UserID,
+			slug:   segment.Slug,
+			expire: segment.Expire,
+		})
 	}
 
-	queryString, queryArgs := query.MustSql()
-	_, err = conn.Exec(ctx, queryString, queryArgs...)
+	now := time.Now()
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err = lockUser(ctx, tx, segments.UserID); err != nil {
+			return err
+		}
+
+		if audit.Before, err = membershipSnapshot(ctx, tx, segments.UserID, now); err != nil {
+			return err
+		}
+
+		if err = upsertMemberships(ctx, tx, memberships, now); err != nil {
+			return err
+		}
+
+		if err = enrollCreatedUsers(ctx, tx); err != nil {
+			return err
+		}
+
+		if audit.After, err = membershipSnapshot(ctx, tx, segments.UserID, now); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, audit, now)
+	})
 	if err != nil {
 		r.logger.Error("Error while executing query", zap.Error(err))
 		return err
@@ -40,7 +65,8 @@ func (r *Repository) SetSegments(ctx context.Context, segments *models.UserSetRe
 	return nil
 }
 
-func (r *Repository) DeleteSegments(ctx context.Context, segments *models.UserDeleteRequest) error {
+// DeleteSegments removes user's memberships and records user's segments before and after to audit log.
+func (r *Repository) DeleteSegments(ctx context.Context, segments *models.UserDeleteRequest, audit *models.AuditEntry) error {
 	conn, err := r.pool.Acquire(ctx)
 	if err != nil {
 		r.logger.Error("Error while acquiring connection", zap.Error(err))
@@ -48,16 +74,27 @@ func (r *Repository) DeleteSegments(ctx context.Context, segments *models.UserDe
 	}
 	defer conn.Release()
 
-	queryString, queryArgs := sq.Update("user_segments").
-		Set("deleted_at", time.Now()).
-		Where(sq.Eq{
-			"user_id": segments.UserID,
-			"slug":    segments.Slugs,
-		}).
-		PlaceholderFormat(sq.Dollar).
-		MustSql()
+	now := time.Now()
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err = lockUser(ctx, tx, segments.UserID); err != nil {
+			return err
+		}
+
+		if audit.Before, err = membershipSnapshot(ctx, tx, segments.UserID, now); err != nil {
+			return err
+		}
+
+		if err = deleteMemberships(ctx, tx, segments.UserID, segments.Slugs, now); err != nil {
+			return err
+		}
 
-	_, err = conn.Exec(ctx, queryString, queryArgs...)
+		if audit.After, err = membershipSnapshot(ctx, tx, segments.UserID, now); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, audit, now)
+	})
 	if err != nil {
 		r.logger.Error("Error while executing query", zap.Error(err))
 		return err
@@ -66,6 +103,146 @@ func (r *Repository) DeleteSegments(ctx context.Context, segments *models.UserDe
 	return nil
 }
 
+// UpdateSegments adds and removes user's segments in one transaction
+// and records user's segments before and after to audit log.
+func (r *Repository) UpdateSegments(ctx context.Context, req *models.UserUpdateRequest, audit *models.AuditEntry) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	memberships := make([]membership, 0, len(req.Add))
+	for _, segment := range req.Add {
+		memberships = append(memberships, membership{
+			userID: req.UserID,
+			slug:   segment.Slug,
+			expire: segment.Expire,
+		})
+	}
+
+	now := time.Now()
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err = lockUser(ctx, tx, req.UserID); err != nil {
+			return err
+		}
+
+		if audit.Before, err = membershipSnapshot(ctx, tx, req.UserID, now); err != nil {
+			return err
+		}
+
+		if err = deleteMemberships(ctx, tx, req.UserID, req.Remove, now); err != nil {
+			return err
+		}
+
+		if err = upsertMemberships(ctx, tx, memberships, now); err != nil {
+			return err
+		}
+
+		if err = enrollCreatedUsers(ctx, tx); err != nil {
+			return err
+		}
+
+		if audit.After, err = membershipSnapshot(ctx, tx, req.UserID, now); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, audit, now)
+	})
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// SetSegmentsBulk assigns segments to many users in one transaction,
+// sending memberships to database in batched chunks. Audit entries, one per user,
+// are recorded with user's segments before and after.
+func (r *Repository) SetSegmentsBulk(ctx context.Context, reqs []models.UserSetRequest, audit []models.AuditEntry) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	var (
+		now         = time.Now()
+		batch       = &pgx.Batch{}
+		memberships = make([]membership, 0, bulkChunkSize)
+	)
+
+	queue := func() {
+		userIDs, slugs, expires := membershipArgs(memberships)
+		batch.Queue(upsertMembershipsQuery, userIDs, slugs, expires, now)
+		memberships = make([]membership, 0, bulkChunkSize)
+	}
+
+	for _, req := range reqs {
+		for _, segment := range req.Segments {
+			memberships = append(memberships, membership{
+				userID: req.UserID,
+				slug:   segment.Slug,
+				expire: segment.Expire,
+			})
+
+			if len(memberships) == bulkChunkSize {
+				queue()
+			}
+		}
+	}
+
+	if len(memberships) > 0 {
+		queue()
+	}
+
+	userIDs := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		userIDs = append(userIDs, req.UserID)
+	}
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if err := lockUsers(ctx, tx, userIDs); err != nil {
+			return err
+		}
+
+		before, err := membershipSnapshots(ctx, tx, userIDs, now)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.SendBatch(ctx, batch).Close(); err != nil {
+			return err
+		}
+
+		if err = enrollCreatedUsers(ctx, tx); err != nil {
+			return err
+		}
+
+		after, err := membershipSnapshots(ctx, tx, userIDs, now)
+		if err != nil {
+			return err
+		}
+
+		for i := range audit {
+			audit[i].Before = snapshotOf(before, audit[i].UserID)
+			audit[i].After = snapshotOf(after, audit[i].UserID)
+		}
+
+		return recordAuditEntries(ctx, tx, audit, now)
+	})
+	if err != nil {
+		r.logger.Error("Error while executing batch", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
 func (r *Repository) GetSegments(ctx context.Context, userID string) (*models.UserResponse, error) {
 	conn, err := r.pool.Acquire(ctx)
 	if err != nil {
@@ -77,16 +254,11 @@ func (r *Repository) GetSegments(ctx context.Context, userID string) (*models.Us
 	queryString, queryArgs := sq.Select("user_segments.slug").
 		From("user_segments").
 		Join("segments on segments.slug = user_segments.slug").
-		Where(
-			sq.Eq{
-				"segments.deleted_at":      nil,
-				"user_segments.deleted_at": nil,
-				"user_segments.user_id":    userID,
-			},
-			sq.LtOrEq{
-				"user_segments.expired_at": time.Now(),
-			},
-		).
+		Where(sq.Eq{
+			"segments.deleted_at":   nil,
+			"user_segments.user_id": userID,
+		}).
+		Where(activeMembership(time.Now())).
 		PlaceholderFormat(sq.Dollar).
 		MustSql()
 
@@ -120,7 +292,9 @@ func (r *Repository) GetSegments(ctx context.Context, userID string) (*models.Us
 	return resp, nil
 }
 
-func (r *Repository) GetReportData(ctx context.Context, year, month int) ([]models.ReportRow, error) {
+// GetSegmentsAt returns segments user was in at the given moment, evaluated from membership
+// and segment catalog history: deletes and expirations are taken as of that moment.
+func (r *Repository) GetSegmentsAt(ctx context.Context, userID string, at time.Time) (*models.UserResponse, error) {
 	conn, err := r.pool.Acquire(ctx)
 	if err != nil {
 		r.logger.Error("Error while acquiring connection", zap.Error(err))
@@ -129,51 +303,218 @@ func (r *Repository) GetReportData(ctx context.Context, year, month int) ([]mode
 	defer conn.Release()
 
 	query := `
-		SELECT user_id, slug, 'added' as method, created_at AS timestamp
-		FROM user_segments
-		WHERE EXTRACT(YEAR FROM created_at) = $1
-		  AND EXTRACT(MONTH FROM created_at) = $2
-		
-		UNION
-		
-		SELECT user_id, slug, 'deleted', deleted_at AS timestamp
-		FROM user_segments
-		WHERE EXTRACT(YEAR FROM deleted_at) = $1
-		  AND EXTRACT(MONTH FROM deleted_at) = $2
-		
-		UNION
-		
-		SELECT user_id, slug, 'expired', expired_at AS timestamp
-		FROM user_segments
-		WHERE EXTRACT(YEAR FROM expired_at) = $1
-		  AND EXTRACT(MONTH FROM expired_at) = $2
-		
-		ORDER BY timestamp;
+		SELECT membership.slug
+		FROM (
+			SELECT DISTINCT ON (slug) slug, method, expire_at
+			FROM user_segment_events
+			WHERE user_id = $1
+			  AND created_at <= $2
+			ORDER BY slug, created_at DESC, id DESC
+		) membership
+		JOIN LATERAL (
+			SELECT method
+			FROM segment_events
+			WHERE segment_events.slug = membership.slug
+			  AND segment_events.created_at <= $2
+			ORDER BY segment_events.created_at DESC, segment_events.id DESC
+			LIMIT 1
+		) segment ON true
+		WHERE membership.method IN ('added', 'updated')
+		  AND (membership.expire_at IS NULL OR membership.expire_at > $2)
+		  AND segment.method IN ('created', 'restored')
+		ORDER BY membership.slug;
 	`
 
-	rows, err := conn.Query(ctx, query, year, month)
+	rows, err := conn.Query(ctx, query, userID, at)
 	if err != nil {
 		r.logger.Error("Error while executing query", zap.Error(err))
 		return nil, err
 	}
+	defer rows.Close()
 
-	resp := make([]models.ReportRow, 0)
+	resp := &models.UserResponse{
+		UserID: userID,
+		Slugs:  make([]string, 0),
+	}
 
 	for rows.Next() {
-		var row models.ReportRow
+		var slug string
 
-		err = rows.Scan(&row.UserID, &row.Slug, &row.Method, &row.Timestamp)
+		err = rows.Scan(&slug)
 		if err != nil {
 			r.logger.Error("Error while scanning query", zap.Error(err))
 			return nil, err
 		}
 
-		resp = append(resp, row)
+		resp.Slugs = append(resp.Slugs, slug)
 	}
 
-	if len(resp) == 0 {
-		return nil, errs.ErrDataNotFound
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return nil, err
 	}
 
 	return resp, nil
 }
+
+// ExpireSegments marks memberships past their expiry as expired
+// and records expiration and outbox events at the moment they actually expired.
+func (r *Repository) ExpireSegments(ctx context.Context, now time.Time) (int64, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return 0, err
+	}
+	defer conn.Release()
+
+	query := `
+		WITH expired AS (
+			UPDATE user_segments
+			SET expired = true
+			WHERE NOT expired
+			  AND deleted_at IS NULL
+			  AND expired_at <= $1
+			  AND user_id = ANY($2)
+			RETURNING user_id, slug, expired_at
+		), events AS (
+			INSERT INTO user_segment_events (user_id, slug, method, created_at)
+			SELECT user_id, slug, 'expired', expired_at
+			FROM expired
+			RETURNING user_id, slug, method, expire_at, created_at
+		), ` + outboxMembershipEvents
+
+	var count int64
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT DISTINCT user_id
+			FROM user_segments
+			WHERE NOT expired
+			  AND deleted_at IS NULL
+			  AND expired_at <= $1;
+		`, now)
+		if err != nil {
+			return err
+		}
+
+		userIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil || len(userIDs) == 0 {
+			return err
+		}
+
+		if err = lockUsers(ctx, tx, userIDs); err != nil {
+			return err
+		}
+
+		return tx.QueryRow(ctx, query, now, userIDs).Scan(&count)
+	})
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// activeMembership is a predicate for memberships that are neither deleted nor expired at the moment.
+func activeMembership(now time.Time) sq.Sqlizer {
+	return sq.And{
+		sq.Eq{
+			"user_segments.deleted_at": nil,
+			"user_segments.expired":    false,
+		},
+		sq.Or{
+			sq.Eq{"user_segments.expired_at": nil},
+			sq.Gt{"user_segments.expired_at": now},
+		},
+	}
+}
+
+// ReportRows returns cursor over membership events matching the filter which happened in [From, To).
+// Rows are read from connection as the cursor advances, so memory does not depend on report size.
+func (r *Repository) ReportRows(ctx context.Context, filter *models.ReportFilter) (models.ReportCursor, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+
+	methods := filter.Methods
+	if len(methods) == 0 {
+		methods = []string{models.MethodAdded, models.MethodDeleted, models.MethodExpired}
+	}
+
+	query := sq.Select("user_id", "slug", "method", "created_at").
+		From("user_segment_events").
+		Where(sq.Eq{"method": methods}).
+		Where(sq.GtOrEq{"created_at": filter.From}).
+		Where(sq.Lt{"created_at": filter.To})
+
+	if len(filter.Slugs) != 0 {
+		query = query.Where(sq.Eq{"slug": filter.Slugs})
+	}
+
+	if len(filter.UserIDs) != 0 {
+		query = query.Where(sq.Eq{"user_id": filter.UserIDs})
+	}
+
+	queryString, queryArgs := query.
+		OrderBy("created_at", "id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		conn.Release()
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+
+	return &reportCursor{conn: conn, rows: rows, logger: r.logger}, nil
+}
+
+// reportCursor holds connection of the query until it is closed.
+type reportCursor struct {
+	conn   *pgxpool.Conn
+	rows   pgx.Rows
+	row    models.ReportRow
+	err    error
+	logger *zap.Logger
+}
+
+func (c *reportCursor) Next() bool {
+	if c.err != nil || !c.rows.Next() {
+		return false
+	}
+
+	if err := c.rows.Scan(&c.row.UserID, &c.row.Slug, &c.row.Method, &c.row.Timestamp); err != nil {
+		c.logger.Error("Error while scanning query", zap.Error(err))
+		c.err = err
+
+		return false
+	}
+
+	return true
+}
+
+func (c *reportCursor) Row() models.ReportRow {
+	return c.row
+}
+
+func (c *reportCursor) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+
+	if err := c.rows.Err(); err != nil {
+		c.logger.Error("Error while reading rows", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *reportCursor) Close() {
+	c.rows.Close()
+	c.conn.Release()
+}
@@ -0,0 +1,349 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// CreateWebhook stores new webhook subscription. Webhook without its secret is recorded to audit log.
+func (r *Repository) CreateWebhook(ctx context.Context, hook *models.Webhook, audit *models.AuditEntry) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Insert("webhooks").
+		Columns("id", "url", "slugs", "secret", "created_at").
+		Values(hook.ID, hook.URL, hook.Slugs, hook.Secret, hook.CreatedAt).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err = tx.Exec(ctx, queryString, queryArgs...); err != nil {
+			return err
+		}
+
+		if audit.After, err = webhookSnapshot(ctx, tx, hook.ID); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, audit, hook.CreatedAt)
+	})
+	if err != nil {
+		r.logger.Error("Error while creating webhook", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ListWebhooks returns all webhook subscriptions without their secrets.
+func (r *Repository) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := webhookQuery().
+		OrderBy("created_at", "id").
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+
+	hooks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Webhook, error) {
+		var hook models.Webhook
+		err := row.Scan(&hook.ID, &hook.URL, &hook.Slugs, &hook.CreatedAt)
+
+		return hook, err
+	})
+	if err != nil {
+		r.logger.Error("Error while scanning query", zap.Error(err))
+		return nil, err
+	}
+
+	return hooks, nil
+}
+
+// GetWebhook returns webhook without its secret or nil if there is no such webhook.
+func (r *Repository) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := webhookQuery().
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	hook := &models.Webhook{}
+
+	err = conn.QueryRow(ctx, queryString, queryArgs...).
+		Scan(&hook.ID, &hook.URL, &hook.Slugs, &hook.CreatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+
+	return hook, nil
+}
+
+func webhookQuery() sq.SelectBuilder {
+	return sq.Select("id", "url", "slugs", "created_at").From("webhooks")
+}
+
+// DeleteWebhook removes webhook along with its delivery log. Webhook before deletion is recorded to audit log.
+func (r *Repository) DeleteWebhook(ctx context.Context, id string, audit *models.AuditEntry) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Delete("webhooks").
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if audit.Before, err = webhookSnapshot(ctx, tx, id); err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, queryString, queryArgs...); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, tx, audit, time.Now())
+	})
+	if err != nil {
+		r.logger.Error("Error while deleting webhook", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Deliveries returns a page of webhook's delivery log, newest first, and total amount of deliveries matching filter.
+func (r *Repository) Deliveries(ctx context.Context, webhookID string, filter *models.DeliveryFilter) ([]models.WebhookDelivery, int, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, 0, err
+	}
+	defer conn.Release()
+
+	query := deliveryQuery().
+		Column("COUNT(*) OVER()").
+		Where(sq.Eq{"webhook_id": webhookID}).
+		OrderBy("id DESC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+
+	if filter.Status != "" {
+		query = query.Where(sq.Eq{"status": filter.Status})
+	}
+
+	queryString, queryArgs := query.PlaceholderFormat(sq.Dollar).MustSql()
+
+	rows, err := conn.Query(ctx, queryString, queryArgs...)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		total int
+		resp  = make([]models.WebhookDelivery, 0)
+	)
+
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+
+		if err = rows.Scan(append(deliveryFields(&delivery), &total)...); err != nil {
+			r.logger.Error("Error while scanning query", zap.Error(err))
+			return nil, 0, err
+		}
+
+		resp = append(resp, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Error while reading rows", zap.Error(err))
+		return nil, 0, err
+	}
+
+	return resp, total, nil
+}
+
+// Delivery returns webhook's delivery or nil if there is no such delivery.
+func (r *Repository) Delivery(ctx context.Context, webhookID string, id int64) (*models.WebhookDelivery, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := deliveryQuery().
+		Where(sq.Eq{"webhook_id": webhookID, "id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	delivery := &models.WebhookDelivery{}
+
+	err = conn.QueryRow(ctx, queryString, queryArgs...).Scan(deliveryFields(delivery)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// RetryDelivery returns delivery into the queue to be sent as soon as possible.
+func (r *Repository) RetryDelivery(ctx context.Context, id int64, now time.Time) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("webhook_deliveries").
+		Set("status", models.DeliveryPending).
+		Set("attempts", 0).
+		Set("next_attempt_at", now).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDeliveries returns up to limit pending deliveries due by now along with webhook's url and secret.
+// Claimed deliveries are postponed until leaseUntil, so other instances do not send them meanwhile.
+func (r *Repository) ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return nil, err
+	}
+	defer conn.Release()
+
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM webhooks w
+		WHERE w.id = d.webhook_id
+		  AND d.id IN (
+		      SELECT id
+		      FROM webhook_deliveries
+		      WHERE status = 'pending'
+		        AND next_attempt_at <= $1
+		      ORDER BY next_attempt_at, id
+		      LIMIT $3
+		      FOR UPDATE SKIP LOCKED
+		  )
+		RETURNING d.id, d.webhook_id, d.event_id, d.user_id, d.slug, d.method, d.expire_at, d.event_at,
+		          d.status, d.attempts, d.next_attempt_at, d.last_status, d.last_error, d.created_at,
+		          d.delivered_at, w.url, w.secret;
+	`
+
+	rows, err := conn.Query(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return nil, err
+	}
+
+	deliveries, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.WebhookDelivery, error) {
+		var delivery models.WebhookDelivery
+		err := row.Scan(append(deliveryFields(&delivery), &delivery.URL, &delivery.Secret)...)
+
+		return delivery, err
+	})
+	if err != nil {
+		r.logger.Error("Error while scanning query", zap.Error(err))
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// FinishDelivery stores outcome of delivery attempt.
+func (r *Repository) FinishDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		r.logger.Error("Error while acquiring connection", zap.Error(err))
+		return err
+	}
+	defer conn.Release()
+
+	queryString, queryArgs := sq.Update("webhook_deliveries").
+		Set("status", delivery.Status).
+		Set("attempts", delivery.Attempts).
+		Set("next_attempt_at", delivery.NextAttemptAt).
+		Set("last_status", delivery.LastStatus).
+		Set("last_error", delivery.LastError).
+		Set("delivered_at", delivery.DeliveredAt).
+		Where(sq.Eq{"id": delivery.ID}).
+		PlaceholderFormat(sq.Dollar).
+		MustSql()
+
+	if _, err = conn.Exec(ctx, queryString, queryArgs...); err != nil {
+		r.logger.Error("Error while executing query", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// deliveryQuery selects deliveries in the order of deliveryFields.
+func deliveryQuery() sq.SelectBuilder {
+	return sq.Select("id", "webhook_id", "event_id", "user_id", "slug", "method", "expire_at", "event_at",
+		"status", "attempts", "next_attempt_at", "last_status", "last_error", "created_at", "delivered_at").
+		From("webhook_deliveries")
+}
+
+// deliveryFields returns scan destinations of deliveryQuery columns.
+func deliveryFields(d *models.WebhookDelivery) []any {
+	d.Event.Kind = models.EventMembership
+
+	return []any{&d.ID, &d.WebhookID, &d.Event.ID, &d.Event.UserID, &d.Event.Slug, &d.Event.Method,
+		&d.Event.ExpireAt, &d.Event.Timestamp, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.LastStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt}
+}
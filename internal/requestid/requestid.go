@@ -0,0 +1,64 @@
+// Package requestid passes id of the request being served down to the layers below handlers.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// MaxLength is the longest request id taken from client.
+const MaxLength = 64
+
+type contextKey struct{}
+
+// Middleware takes request id from X-Request-ID header or generates one, echoes it in response
+// and puts it in request context. Ids not passing IsValid are replaced, so clients can not put
+// arbitrary data to logs and audit log.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			id := req.Header.Get(echo.HeaderXRequestID)
+			if !IsValid(id) {
+				id = uuid.NewString()
+			}
+
+			req.Header.Set(echo.HeaderXRequestID, id)
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+			c.SetRequest(req.WithContext(NewContext(req.Context(), id)))
+
+			return next(c)
+		}
+	}
+}
+
+// IsValid checks that request id is up to MaxLength of latin letters, digits and dashes.
+func IsValid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewContext returns context carrying the request id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns id of the request the context belongs to or empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
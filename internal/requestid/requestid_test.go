@@ -0,0 +1,72 @@
+package requestid_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dupreehkuda/avito-segments/internal/requestid"
+)
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name      string
+		requestID string
+		replaced  bool
+	}{
+		{
+			name:      "Request id from client",
+			requestID: "f2b1c0de-0d6a-4f1e-9d4b-6c3f0a2e7b1a",
+		},
+		{
+			name: "Generated request id",
+		},
+		{
+			name:      "Request id too long",
+			requestID: strings.Repeat("a", requestid.MaxLength+1),
+			replaced:  true,
+		},
+		{
+			name:      "Request id with forbidden characters",
+			requestID: `f2b1c0de","actor":"admin`,
+			replaced:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var seen string
+
+			e := echo.New()
+			e.Use(requestid.Middleware())
+			e.GET("/", func(c echo.Context) error {
+				seen = requestid.FromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.requestID != "" {
+				req.Header.Set(echo.HeaderXRequestID, tc.requestID)
+			}
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			a.NotEmpty(seen)
+			a.Equal(seen, rec.Header().Get(echo.HeaderXRequestID))
+
+			if tc.requestID != "" {
+				a.Equal(!tc.replaced, tc.requestID == seen)
+			}
+
+			a.True(requestid.IsValid(seen))
+		})
+	}
+}
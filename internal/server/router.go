@@ -4,18 +4,62 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/auth"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/problem"
+	"github.com/dupreehkuda/avito-segments/internal/requestid"
 )
 
 type Handlers interface {
 	SegmentAdd(c echo.Context) error
 	SegmentDelete(c echo.Context) error
+	SegmentUpdate(c echo.Context) error
+	SegmentRestore(c echo.Context) error
+	SegmentList(c echo.Context) error
+	SegmentGet(c echo.Context) error
+	SegmentStats(c echo.Context) error
+	SegmentRanking(c echo.Context) error
 
 	UserSetSegments(c echo.Context) error
 	UserDeleteSegments(c echo.Context) error
+	UserUpdateSegments(c echo.Context) error
+	UserBulkSetSegments(c echo.Context) error
+	SegmentMembers(c echo.Context) error
+	SegmentExport(c echo.Context) error
+	SegmentImport(c echo.Context) error
+	SegmentImportGet(c echo.Context) error
 	UserGetSegments(c echo.Context) error
 
 	ReportCreate(c echo.Context) error
 	ReportGet(c echo.Context) error
+	ReportJobGet(c echo.Context) error
+	ReportStream(c echo.Context) error
+
+	WebhookCreate(c echo.Context) error
+	WebhookList(c echo.Context) error
+	WebhookGet(c echo.Context) error
+	WebhookDelete(c echo.Context) error
+	WebhookDeliveries(c echo.Context) error
+	WebhookRedeliver(c echo.Context) error
+
+	EventStream(c echo.Context) error
+
+	APIKeyCreate(c echo.Context) error
+	APIKeyList(c echo.Context) error
+	APIKeyRevoke(c echo.Context) error
+
+	AuditLog(c echo.Context) error
+}
+
+// Authenticator identifies the principal making request.
+type Authenticator interface {
+	Authenticate(next echo.HandlerFunc) echo.HandlerFunc
+}
+
+// Idempotency makes retries of requests with Idempotency-Key header safe.
+type Idempotency interface {
+	Idempotent(next echo.HandlerFunc) echo.HandlerFunc
 }
 
 func (a *API) handler(logger *zap.Logger) *echo.Echo {
@@ -23,14 +67,18 @@ func (a *API) handler(logger *zap.Logger) *echo.Echo {
 
 	e.HideBanner = true
 	e.HidePort = true
+	e.HTTPErrorHandler = problem.Handler(logger)
 
 	e.Use(middleware.Gzip())
 	e.Use(middleware.Recover())
+	e.Use(requestid.Middleware())
 	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
-		LogURI:    true,
-		LogStatus: true,
+		LogURI:       true,
+		LogStatus:    true,
+		LogRequestID: true,
 		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
 			logger.Info("request",
+				zap.String("requestID", v.RequestID),
 				zap.String("URI", v.URI),
 				zap.Int("status", v.Status),
 				zap.String("remote", v.RemoteIP),
@@ -42,23 +90,65 @@ func (a *API) handler(logger *zap.Logger) *echo.Echo {
 	}))
 
 	api := e.Group("/api")
-	v1 := api.Group("/v1")
-
-	segment := v1.Group("/segment")
-
-	segment.POST("", a.handlers.SegmentAdd)
+	v1 := api.Group("/v1", a.auth.Authenticate)
+
+	segment := v1.Group("/segment", auth.RequireReadWrite(models.ScopeSegmentsRead, models.ScopeSegmentsWrite))
+
+	segment.GET("", a.handlers.SegmentList)
+	segment.GET("/:slug", a.handlers.SegmentGet)
+	segment.POST("", a.handlers.SegmentAdd, a.idempotency.Idempotent)
+	segment.PATCH("/:slug", a.handlers.SegmentUpdate)
+	segment.POST("/:slug/restore", a.handlers.SegmentRestore)
+	segment.GET("/:slug/stats", a.handlers.SegmentStats)
+	// Membership routes expose and write users, so they need user scopes as well.
+	segment.GET("/:slug/users", a.handlers.SegmentMembers, auth.Require(models.ScopeUsersRead))
+	segment.GET("/:slug/users/export", a.handlers.SegmentExport, auth.Require(models.ScopeUsersRead))
+	segment.POST("/:slug/import", a.handlers.SegmentImport, auth.Require(models.ScopeUsersWrite))
+	segment.GET("/:slug/import/:id", a.handlers.SegmentImportGet, auth.Require(models.ScopeUsersRead))
 	segment.DELETE("/:slug", a.handlers.SegmentDelete)
 
-	user := v1.Group("/user")
+	user := v1.Group("/user", auth.RequireReadWrite(models.ScopeUsersRead, models.ScopeUsersWrite))
 
 	user.GET("/:id", a.handlers.UserGetSegments)
-	user.POST("", a.handlers.UserSetSegments)
+	user.POST("", a.handlers.UserSetSegments, a.idempotency.Idempotent)
+	user.POST("/bulk", a.handlers.UserBulkSetSegments)
 	user.DELETE("", a.handlers.UserDeleteSegments)
+	user.PATCH("/:id", a.handlers.UserUpdateSegments)
 
-	report := v1.Group("/report")
+	stats := v1.Group("/stats", auth.Require(models.ScopeSegmentsRead))
 
+	stats.GET("/segments", a.handlers.SegmentRanking)
+
+	report := v1.Group("/report", auth.Require(models.ScopeReportsRead))
+
+	report.GET("/jobs/:id", a.handlers.ReportJobGet)
+	report.GET("/stream", a.handlers.ReportStream)
 	report.GET("/:file", a.handlers.ReportGet)
 	report.POST("", a.handlers.ReportCreate)
 
+	webhooks := v1.Group("/webhooks", auth.Require(models.ScopeWebhooksWrite))
+
+	webhooks.GET("", a.handlers.WebhookList)
+	// Subscriptions deliver membership events, which are only streamed with users:read.
+	webhooks.POST("", a.handlers.WebhookCreate, auth.Require(models.ScopeUsersRead))
+	webhooks.GET("/:id", a.handlers.WebhookGet)
+	webhooks.DELETE("/:id", a.handlers.WebhookDelete)
+	webhooks.GET("/:id/deliveries", a.handlers.WebhookDeliveries)
+	webhooks.POST("/:id/deliveries/:delivery/retry", a.handlers.WebhookRedeliver)
+
+	events := v1.Group("/events", auth.Require(models.ScopeUsersRead), a.endOnShutdown)
+
+	events.GET("/stream", a.handlers.EventStream)
+
+	keys := v1.Group("/keys", auth.Require(models.ScopeAdmin))
+
+	keys.GET("", a.handlers.APIKeyList)
+	keys.POST("", a.handlers.APIKeyCreate)
+	keys.DELETE("/:id", a.handlers.APIKeyRevoke)
+
+	audit := v1.Group("/audit", auth.Require(models.ScopeAuditRead))
+
+	audit.GET("", a.handlers.AuditLog)
+
 	return e
 }
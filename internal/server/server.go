@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 
+	"github.com/labstack/echo/v4"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 
@@ -10,19 +11,34 @@ import (
 )
 
 type API struct {
-	handlers Handlers
-	config   *config.Config
-	logger   *zap.Logger
+	handlers    Handlers
+	auth        Authenticator
+	idempotency Idempotency
+	config      *config.Config
+	logger      *zap.Logger
+	// shutdown is closed when server starts shutting down.
+	shutdown chan struct{}
 }
 
-func RegisterServer(lc fx.Lifecycle, handlers Handlers, config *config.Config, logger *zap.Logger) *API {
+func RegisterServer(
+	lc fx.Lifecycle,
+	handlers Handlers,
+	auth Authenticator,
+	idempotency Idempotency,
+	config *config.Config,
+	logger *zap.Logger,
+) *API {
 	api := &API{
-		handlers: handlers,
-		config:   config,
-		logger:   logger,
+		handlers:    handlers,
+		auth:        auth,
+		idempotency: idempotency,
+		config:      config,
+		logger:      logger,
+		shutdown:    make(chan struct{}),
 	}
 
 	serv := api.handler(logger)
+	serv.Server.RegisterOnShutdown(func() { close(api.shutdown) })
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
@@ -52,3 +68,24 @@ func RegisterServer(lc fx.Lifecycle, handlers Handlers, config *config.Config, l
 
 	return api
 }
+
+// endOnShutdown cancels request context when server starts shutting down.
+// Shutdown waits for active requests, so long-lived streams have to end on their own.
+func (a *API) endOnShutdown(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, cancel := context.WithCancel(c.Request().Context())
+		defer cancel()
+
+		go func() {
+			select {
+			case <-a.shutdown:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		c.SetRequest(c.Request().WithContext(ctx))
+
+		return next(c)
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dupreehkuda/avito-segments/internal/auth"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// APIKeyCreate issues new api key with the scopes. Only the hash of the key is stored, so it is returned only here.
+func (s *Service) APIKeyCreate(ctx context.Context, req *models.APIKeyRequest) (*models.APIKey, error) {
+	if err := IsValidAPIKeyRequest(req); err != nil {
+		return nil, err
+	}
+
+	secret, err := auth.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &models.APIKey{
+		ID:        uuid.NewString(),
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    secret[:auth.PrefixLength],
+		Scopes:    uniqueSorted(req.Scopes),
+		Key:       secret,
+		CreatedAt: time.Now(),
+		Hash:      auth.HashKey(secret),
+	}
+
+	if err = s.keyRepo.CreateAPIKey(ctx, key, auditEntry(ctx, models.AuditAPIKeyCreate, "", nil)); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// APIKeyList returns all api keys without the keys themselves.
+func (s *Service) APIKeyList(ctx context.Context) (*models.APIKeyListResponse, error) {
+	keys, err := s.keyRepo.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.APIKeyListResponse{Keys: keys}, nil
+}
+
+// APIKeyRevoke revokes api key, requests with it are rejected right away.
+func (s *Service) APIKeyRevoke(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return errors.ErrAPIKeyNotFound
+	}
+
+	found, err := s.keyRepo.RevokeAPIKey(ctx, id, time.Now(), auditEntry(ctx, models.AuditAPIKeyRevoke, "", nil))
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return errors.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// IsValidAPIKeyRequest checks that api key has a name and only known scopes.
+func IsValidAPIKeyRequest(req *models.APIKeyRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > MaxKeyNameLength || len(req.Scopes) == 0 {
+		return errors.ErrInvalidAPIKey
+	}
+
+	for _, scope := range req.Scopes {
+		if !isKnownScope(scope) {
+			return errors.ErrInvalidAPIKey
+		}
+	}
+
+	return nil
+}
+
+func isKnownScope(scope string) bool {
+	for _, known := range models.Scopes {
+		if scope == known {
+			return true
+		}
+	}
+
+	return false
+}
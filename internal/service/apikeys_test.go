@@ -0,0 +1,168 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/auth"
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/service"
+)
+
+const testKeyID = "0d7e6c2a-9b1f-4e57-8c3d-6a2b1f0e9d84"
+
+func TestService_APIKeyCreate(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name string
+		req  *models.APIKeyRequest
+
+		createError error
+
+		expectedScopes []string
+		expectedError  error
+
+		expectingCreateCall bool
+	}{
+		{
+			name: "Key created",
+			req: &models.APIKeyRequest{
+				Name:   " reporting ",
+				Scopes: []string{models.ScopeReportsRead, models.ScopeSegmentsRead, models.ScopeReportsRead},
+			},
+			expectedScopes:      []string{models.ScopeReportsRead, models.ScopeSegmentsRead},
+			expectingCreateCall: true,
+		},
+		{
+			name:          "No name",
+			req:           &models.APIKeyRequest{Name: " ", Scopes: []string{models.ScopeUsersRead}},
+			expectedError: errors.ErrInvalidAPIKey,
+		},
+		{
+			name:          "Long name",
+			req:           &models.APIKeyRequest{Name: strings.Repeat("a", service.MaxKeyNameLength+1), Scopes: []string{models.ScopeUsersRead}},
+			expectedError: errors.ErrInvalidAPIKey,
+		},
+		{
+			name:          "No scopes",
+			req:           &models.APIKeyRequest{Name: "reporting"},
+			expectedError: errors.ErrInvalidAPIKey,
+		},
+		{
+			name:          "Unknown scope",
+			req:           &models.APIKeyRequest{Name: "reporting", Scopes: []string{"reports:write"}},
+			expectedError: errors.ErrInvalidAPIKey,
+		},
+		{
+			name:                "Some internal error",
+			req:                 &models.APIKeyRequest{Name: "reporting", Scopes: []string{models.ScopeReportsRead}},
+			createError:         os.ErrInvalid,
+			expectedScopes:      []string{models.ScopeReportsRead},
+			expectedError:       os.ErrInvalid,
+			expectingCreateCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			keyRepo := NewMockAPIKeyRepository(ctrl)
+
+			if tc.expectingCreateCall {
+				keyRepo.EXPECT().CreateAPIKey(context.Background(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, key *models.APIKey, _ *models.AuditEntry) error {
+						a.Equal("reporting", key.Name)
+						a.Equal(tc.expectedScopes, key.Scopes)
+						a.Equal(auth.HashKey(key.Key), key.Hash)
+						a.True(strings.HasPrefix(key.Key, key.Prefix))
+						a.NotEmpty(key.ID)
+
+						return tc.createError
+					})
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, nil, nil, nil, nil, keyRepo, nil, nil, nil, &config.Config{}, zp)
+
+			res, err := serv.APIKeyCreate(context.Background(), tc.req)
+
+			a.Equal(tc.expectedError, err)
+
+			if tc.expectedError == nil {
+				a.NotEmpty(res.Key)
+			} else {
+				a.Nil(res)
+			}
+		})
+	}
+}
+
+func TestService_APIKeyRevoke(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name string
+		id   string
+
+		revokeReturn bool
+		revokeError  error
+
+		expectedError error
+
+		expectingRevokeCall bool
+	}{
+		{
+			name:                "Key revoked",
+			id:                  testKeyID,
+			revokeReturn:        true,
+			expectingRevokeCall: true,
+		},
+		{
+			name:                "Key not found",
+			id:                  testKeyID,
+			expectedError:       errors.ErrAPIKeyNotFound,
+			expectingRevokeCall: true,
+		},
+		{
+			name:          "Invalid key id",
+			id:            "key",
+			expectedError: errors.ErrAPIKeyNotFound,
+		},
+		{
+			name:                "Some internal error",
+			id:                  testKeyID,
+			revokeError:         os.ErrInvalid,
+			expectedError:       os.ErrInvalid,
+			expectingRevokeCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			keyRepo := NewMockAPIKeyRepository(ctrl)
+
+			if tc.expectingRevokeCall {
+				keyRepo.EXPECT().RevokeAPIKey(context.Background(), tc.id, gomock.Any(), gomock.Any()).Return(tc.revokeReturn, tc.revokeError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, nil, nil, nil, nil, keyRepo, nil, nil, nil, &config.Config{}, zp)
+
+			a.Equal(tc.expectedError, serv.APIKeyRevoke(context.Background(), tc.id))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/dupreehkuda/avito-segments/internal/auth"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/requestid"
+)
+
+// SystemActor is the actor of changes made outside of authenticated requests.
+const SystemActor = "system"
+
+// AuditLog returns a page of audit log matching filter, newest entries first.
+func (s *Service) AuditLog(ctx context.Context, filter *models.AuditFilter) (*models.AuditLogResponse, error) {
+	if err := IsValidAuditFilter(filter); err != nil {
+		return nil, err
+	}
+
+	entries, total, err := s.auditRepo.AuditLog(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.AuditLogResponse{
+		Entries: entries,
+		Total:   total,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+	}, nil
+}
+
+// auditEntry describes the operation made by principal of the request the context belongs to.
+// Repository fills in the state before and after the change.
+func auditEntry(ctx context.Context, operation, userID string, slugs []string) *models.AuditEntry {
+	entry := &models.AuditEntry{
+		Actor:     SystemActor,
+		RequestID: requestid.FromContext(ctx),
+		Operation: operation,
+		Slugs:     uniqueSorted(slugs),
+		UserID:    userID,
+	}
+
+	if principal := auth.FromContext(ctx); principal != nil {
+		entry.Actor, entry.ActorName = principal.ID, principal.Name
+	}
+
+	return entry
+}
+
+// bulkAuditEntries describes assignment of segments to many users with an entry per user,
+// made by the same actor and request as audit.
+func bulkAuditEntries(audit *models.AuditEntry, reqs []models.UserSetRequest) []models.AuditEntry {
+	var (
+		userIDs = make([]string, 0, len(reqs))
+		slugs   = make(map[string][]string, len(reqs))
+	)
+
+	for _, req := range reqs {
+		if _, ok := slugs[req.UserID]; !ok {
+			userIDs = append(userIDs, req.UserID)
+		}
+
+		for _, segment := range req.Segments {
+			slugs[req.UserID] = append(slugs[req.UserID], segment.Slug)
+		}
+	}
+
+	entries := make([]models.AuditEntry, 0, len(userIDs))
+
+	for _, userID := range userIDs {
+		entry := *audit
+		entry.UserID, entry.Slugs = userID, uniqueSorted(slugs[userID])
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// IsValidAuditFilter checks audit log filter, period and pagination.
+func IsValidAuditFilter(filter *models.AuditFilter) error {
+	if filter.Slug != "" && !IsValidSlug(filter.Slug) {
+		return errors.ErrInvalidSegmentSlug
+	}
+
+	if filter.UserID != "" {
+		if _, err := uuid.Parse(filter.UserID); err != nil {
+			return errors.ErrInvalidUserID
+		}
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return errors.ErrInvalidPeriod
+	}
+
+	if filter.Limit < 1 || filter.Limit > MaxPageLimit || filter.Offset < 0 {
+		return errors.ErrInvalidPagination
+	}
+
+	return nil
+}
@@ -0,0 +1,390 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/auth"
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/requestid"
+	"github.com/dupreehkuda/avito-segments/internal/service"
+	"github.com/dupreehkuda/avito-segments/internal/worker"
+)
+
+func TestService_AuditLog(t *testing.T) {
+	a := assert.New(t)
+
+	from := time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		filter *models.AuditFilter
+
+		repositoryReturn []models.AuditEntry
+		repositoryError  error
+
+		expectedError error
+
+		expectingRepositoryCall bool
+	}{
+		{
+			name: "Filtered page",
+			filter: &models.AuditFilter{
+				Actor:  "jwt:https://id.example.com:staff-42",
+				Slug:   "AVITO_TEST",
+				UserID: "d9b4e1c4-3b1f-4f1e-9d4b-6c3f0a2e7b1a",
+				From:   from,
+				To:     from.AddDate(0, 1, 0),
+				Limit:  100,
+			},
+			repositoryReturn:        []models.AuditEntry{{ID: 1, Actor: "jwt:https://id.example.com:staff-42", Operation: models.AuditSegmentAdd}},
+			expectingRepositoryCall: true,
+		},
+		{
+			name:          "Invalid slug",
+			filter:        &models.AuditFilter{Slug: "avito test", Limit: 100},
+			expectedError: errors.ErrInvalidSegmentSlug,
+		},
+		{
+			name:          "Invalid user id",
+			filter:        &models.AuditFilter{UserID: "user", Limit: 100},
+			expectedError: errors.ErrInvalidUserID,
+		},
+		{
+			name:          "Inverted period",
+			filter:        &models.AuditFilter{From: from, To: from, Limit: 100},
+			expectedError: errors.ErrInvalidPeriod,
+		},
+		{
+			name:          "Invalid pagination",
+			filter:        &models.AuditFilter{Limit: service.MaxPageLimit + 1},
+			expectedError: errors.ErrInvalidPagination,
+		},
+		{
+			name:                    "Some internal error",
+			filter:                  &models.AuditFilter{Limit: 100},
+			repositoryError:         os.ErrInvalid,
+			expectedError:           os.ErrInvalid,
+			expectingRepositoryCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			auditRepo := NewMockAuditRepository(ctrl)
+
+			if tc.expectingRepositoryCall {
+				auditRepo.EXPECT().AuditLog(context.Background(), tc.filter).
+					Return(tc.repositoryReturn, len(tc.repositoryReturn), tc.repositoryError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, nil, nil, nil, nil, nil, auditRepo, nil, nil, &config.Config{}, zp)
+
+			res, err := serv.AuditLog(context.Background(), tc.filter)
+
+			a.Equal(tc.expectedError, err)
+
+			if tc.expectedError == nil {
+				a.Equal(tc.repositoryReturn, res.Entries)
+				a.Equal(len(tc.repositoryReturn), res.Total)
+				a.Equal(tc.filter.Limit, res.Limit)
+			}
+		})
+	}
+}
+
+func TestService_AuditActor(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name      string
+		principal *models.Principal
+		requestID string
+
+		expectedEntry *models.AuditEntry
+	}{
+		{
+			name:      "Authenticated request",
+			principal: &models.Principal{ID: "jwt:https://id.example.com:staff-42", Name: "staff@example.com"},
+			requestID: "f2b1c0de",
+			expectedEntry: &models.AuditEntry{
+				Actor:     "jwt:https://id.example.com:staff-42",
+				ActorName: "staff@example.com",
+				RequestID: "f2b1c0de",
+				Operation: models.AuditUserDeleteSegments,
+				Slugs:     []string{"AVITO_BAR", "AVITO_FOO"},
+				UserID:    "d9b4e1c4-3b1f-4f1e-9d4b-6c3f0a2e7b1a",
+			},
+		},
+		{
+			name: "Change outside of request",
+			expectedEntry: &models.AuditEntry{
+				Actor:     service.SystemActor,
+				Operation: models.AuditUserDeleteSegments,
+				Slugs:     []string{"AVITO_BAR", "AVITO_FOO"},
+				UserID:    "d9b4e1c4-3b1f-4f1e-9d4b-6c3f0a2e7b1a",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+
+			if tc.principal != nil {
+				ctx = auth.NewContext(ctx, tc.principal)
+			}
+
+			if tc.requestID != "" {
+				ctx = requestid.NewContext(ctx, tc.requestID)
+			}
+
+			req := &models.UserDeleteRequest{
+				UserID: "d9b4e1c4-3b1f-4f1e-9d4b-6c3f0a2e7b1a",
+				Slugs:  []string{"AVITO_FOO", "AVITO_BAR"},
+			}
+
+			segmentRepo := NewMockSegmentRepository(ctrl)
+			segmentRepo.EXPECT().Existing(ctx, req.Slugs).Return(req.Slugs, nil)
+
+			userRepo := NewMockUserRepository(ctrl)
+			userRepo.EXPECT().DeleteSegments(ctx, req, tc.expectedEntry).Return(nil)
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			a.NoError(serv.UserDeleteSegments(ctx, req))
+		})
+	}
+}
+
+// auditMocks are repositories audited operations write through.
+type auditMocks struct {
+	userRepo    *MockUserRepository
+	segmentRepo *MockSegmentRepository
+	jobRepo     *MockJobRepository
+	webhookRepo *MockWebhookRepository
+	keyRepo     *MockAPIKeyRepository
+	storage     *MockReportStorage
+	scheduler   *MockScheduler
+}
+
+func TestService_AuditOperations(t *testing.T) {
+	a := assert.New(t)
+
+	const (
+		userID  = "d9b4e1c4-3b1f-4f1e-9d4b-6c3f0a2e7b1a"
+		otherID = "80b0b88d-379e-11ee-8bf7-0242c0a80002"
+	)
+
+	percentage := 10
+
+	actor := models.AuditEntry{
+		Actor:     "key:80b0b88d-379e-11ee-8bf7-0242c0a80009",
+		ActorName: "reporting",
+		RequestID: "f2b1c0de",
+	}
+
+	entry := func(operation, userID string, slugs ...string) models.AuditEntry {
+		entry := actor
+		entry.Operation, entry.UserID, entry.Slugs = operation, userID, slugs
+
+		return entry
+	}
+
+	testCases := []struct {
+		name    string
+		prepare func(ctx context.Context, m auditMocks)
+		call    func(ctx context.Context, serv *service.Service) error
+	}{
+		{
+			name: "Update user segments",
+			prepare: func(ctx context.Context, m auditMocks) {
+				expected := entry(models.AuditUserUpdateSegments, userID, "AVITO_BAR", "AVITO_FOO")
+
+				m.segmentRepo.EXPECT().Existing(ctx, gomock.Any()).Return([]string{"AVITO_FOO", "AVITO_BAR"}, nil)
+				m.userRepo.EXPECT().UpdateSegments(ctx, gomock.Any(), &expected).Return(nil)
+			},
+			call: func(ctx context.Context, serv *service.Service) error {
+				return serv.UserUpdateSegments(ctx, &models.UserUpdateRequest{
+					UserID: userID,
+					Add:    []models.UserSegment{{Slug: "AVITO_FOO"}},
+					Remove: []string{"AVITO_BAR"},
+				})
+			},
+		},
+		{
+			name: "Bulk set segments",
+			prepare: func(ctx context.Context, m auditMocks) {
+				m.segmentRepo.EXPECT().Existing(ctx, gomock.Any()).Return([]string{"AVITO_FOO", "AVITO_BAR"}, nil)
+				m.userRepo.EXPECT().SetSegmentsBulk(ctx, gomock.Any(), []models.AuditEntry{
+					entry(models.AuditUserBulkSetSegments, userID, "AVITO_BAR", "AVITO_FOO"),
+					entry(models.AuditUserBulkSetSegments, otherID, "AVITO_FOO"),
+				}).Return(nil)
+			},
+			call: func(ctx context.Context, serv *service.Service) error {
+				_, err := serv.UserBulkSetSegments(ctx, []models.UserSetRequest{
+					{UserID: userID, Segments: []models.UserSegment{{Slug: "AVITO_FOO"}, {Slug: "AVITO_BAR"}}},
+					{UserID: otherID, Segments: []models.UserSegment{{Slug: "AVITO_FOO"}}},
+				})
+
+				return err
+			},
+		},
+		{
+			name: "Import segment",
+			prepare: func(ctx context.Context, m auditMocks) {
+				expected := entry(models.AuditSegmentImport, "", "AVITO_FOO")
+
+				m.segmentRepo.EXPECT().Get(ctx, "AVITO_FOO").Return(&models.Segment{Slug: "AVITO_FOO"}, nil)
+				m.storage.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, fn func(w io.Writer) error) error {
+						return fn(io.Discard)
+					})
+				m.jobRepo.EXPECT().CreateImport(ctx, gomock.Any()).
+					DoAndReturn(func(_ context.Context, job *models.ImportJob) error {
+						a.Equal(&expected, job.Audit)
+						return nil
+					})
+
+				// Memberships are written in background, still on behalf of the user who started the import.
+				m.scheduler.EXPECT().Submit(gomock.Any()).DoAndReturn(func(task worker.Task) error {
+					task(context.Background())
+					return nil
+				})
+
+				m.jobRepo.EXPECT().StartImport(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				m.storage.EXPECT().Open(gomock.Any(), gomock.Any()).Return(io.NopCloser(strings.NewReader(userID+"\n"+otherID+"\n")), nil)
+				m.userRepo.EXPECT().SetSegmentsBulk(gomock.Any(), gomock.Any(), []models.AuditEntry{
+					entry(models.AuditSegmentImport, userID, "AVITO_FOO"),
+					entry(models.AuditSegmentImport, otherID, "AVITO_FOO"),
+				}).Return(nil)
+				m.jobRepo.EXPECT().ProgressImport(gomock.Any(), gomock.Any(), 2, gomock.Any()).Return(nil)
+				m.jobRepo.EXPECT().FinishImport(gomock.Any(), gomock.Any(), models.JobDone, gomock.Any(), gomock.Any()).Return(nil)
+				m.storage.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			call: func(ctx context.Context, serv *service.Service) error {
+				_, err := serv.SegmentImport(ctx, "AVITO_FOO", strings.NewReader(userID+"\n"+otherID+"\n"))
+
+				return err
+			},
+		},
+		{
+			name: "Update segment",
+			prepare: func(ctx context.Context, m auditMocks) {
+				expected := entry(models.AuditSegmentUpdate, "", "AVITO_FOO")
+
+				m.segmentRepo.EXPECT().Get(ctx, "AVITO_FOO").Return(&models.Segment{Slug: "AVITO_FOO"}, nil)
+				m.segmentRepo.EXPECT().Update(ctx, "AVITO_FOO", gomock.Any(), &expected).Return(nil)
+			},
+			call: func(ctx context.Context, serv *service.Service) error {
+				return serv.SegmentUpdate(ctx, "AVITO_FOO", &models.SegmentUpdateRequest{Percentage: &percentage})
+			},
+		},
+		{
+			name: "Restore segment",
+			prepare: func(ctx context.Context, m auditMocks) {
+				expected := entry(models.AuditSegmentRestore, "", "AVITO_FOO")
+
+				m.segmentRepo.EXPECT().Get(ctx, "AVITO_FOO").Return(&models.Segment{Slug: "AVITO_FOO", DeletedAt: time.Now()}, nil)
+				m.segmentRepo.EXPECT().Restore(ctx, "AVITO_FOO", false, &expected).Return(nil)
+			},
+			call: func(ctx context.Context, serv *service.Service) error {
+				return serv.SegmentRestore(ctx, "AVITO_FOO", false)
+			},
+		},
+		{
+			name: "Create api key",
+			prepare: func(ctx context.Context, m auditMocks) {
+				expected := entry(models.AuditAPIKeyCreate, "")
+
+				m.keyRepo.EXPECT().CreateAPIKey(ctx, gomock.Any(), &expected).Return(nil)
+			},
+			call: func(ctx context.Context, serv *service.Service) error {
+				_, err := serv.APIKeyCreate(ctx, &models.APIKeyRequest{Name: "ci", Scopes: []string{models.ScopeUsersRead}})
+				return err
+			},
+		},
+		{
+			name: "Revoke api key",
+			prepare: func(ctx context.Context, m auditMocks) {
+				expected := entry(models.AuditAPIKeyRevoke, "")
+
+				m.keyRepo.EXPECT().RevokeAPIKey(ctx, otherID, gomock.Any(), &expected).Return(true, nil)
+			},
+			call: func(ctx context.Context, serv *service.Service) error {
+				return serv.APIKeyRevoke(ctx, otherID)
+			},
+		},
+		{
+			name: "Create webhook",
+			prepare: func(ctx context.Context, m auditMocks) {
+				expected := entry(models.AuditWebhookCreate, "", "AVITO_FOO")
+
+				m.segmentRepo.EXPECT().Existing(ctx, []string{"AVITO_FOO"}).Return([]string{"AVITO_FOO"}, nil)
+				m.webhookRepo.EXPECT().CreateWebhook(ctx, gomock.Any(), &expected).Return(nil)
+			},
+			call: func(ctx context.Context, serv *service.Service) error {
+				_, err := serv.WebhookCreate(ctx, &models.WebhookRequest{URL: "https://example.com/hook", Slugs: []string{"AVITO_FOO"}})
+				return err
+			},
+		},
+		{
+			name: "Delete webhook",
+			prepare: func(ctx context.Context, m auditMocks) {
+				expected := entry(models.AuditWebhookDelete, "", "AVITO_FOO")
+
+				m.webhookRepo.EXPECT().GetWebhook(ctx, otherID).Return(&models.Webhook{ID: otherID, Slugs: []string{"AVITO_FOO"}}, nil)
+				m.webhookRepo.EXPECT().DeleteWebhook(ctx, otherID, &expected).Return(nil)
+			},
+			call: func(ctx context.Context, serv *service.Service) error {
+				return serv.WebhookDelete(ctx, otherID)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := auth.NewContext(context.Background(), &models.Principal{ID: actor.Actor, Name: actor.ActorName})
+			ctx = requestid.NewContext(ctx, actor.RequestID)
+
+			m := auditMocks{
+				userRepo:    NewMockUserRepository(ctrl),
+				segmentRepo: NewMockSegmentRepository(ctrl),
+				jobRepo:     NewMockJobRepository(ctrl),
+				webhookRepo: NewMockWebhookRepository(ctrl),
+				keyRepo:     NewMockAPIKeyRepository(ctrl),
+				storage:     NewMockReportStorage(ctrl),
+				scheduler:   NewMockScheduler(ctrl),
+			}
+
+			tc.prepare(ctx, m)
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(m.userRepo, m.segmentRepo, m.jobRepo, m.webhookRepo, nil, m.keyRepo, nil, m.storage,
+				m.scheduler, &config.Config{}, zp)
+
+			a.NoError(tc.call(ctx, serv))
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// StreamEvents passes membership and segment catalog changes matching the filter to fn as they are committed.
+// Stream continues after lastEventID or starts from now if it is empty. Events are read from the outbox
+// written along with the changes, so client resuming with its last event id misses nothing,
+// unless it was away longer than outbox retention.
+// fn is called after every poll, with no events if nothing happened, so it can keep connection alive.
+// Stream ends without error when ctx is done, otherwise with the error of fn or repository.
+func (s *Service) StreamEvents(ctx context.Context, filter *models.EventFilter, lastEventID string, fn func(events []models.StreamEvent) error) error {
+	if err := IsValidEventFilter(filter); err != nil {
+		return err
+	}
+
+	filter.Slugs = uniqueSorted(filter.Slugs)
+	filter.UserIDs = uniqueSorted(filter.UserIDs)
+	filter.Limit = EventBatchSize
+
+	if lastEventID != "" {
+		after, ok := parseEventID(lastEventID)
+		if !ok {
+			return errors.ErrInvalidEventID
+		}
+
+		filter.After = after
+	} else {
+		head, err := s.eventRepo.EventsHead(ctx)
+		if err != nil {
+			return err
+		}
+
+		filter.After = head
+	}
+
+	for {
+		events, err := s.eventRepo.Events(ctx, filter)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		for i := range events {
+			events[i].ID = formatEventID(events[i].Position)
+		}
+
+		if err = fn(events); err != nil {
+			return err
+		}
+
+		if len(events) > 0 {
+			filter.After = events[len(events)-1].Position
+		}
+
+		// Full batch means there are more events waiting already.
+		if len(events) == filter.Limit {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(EventPollInterval):
+		}
+	}
+}
+
+// IsValidEventFilter checks slugs and user ids of event stream filter.
+func IsValidEventFilter(filter *models.EventFilter) error {
+	if len(filter.Slugs)+len(filter.UserIDs) > MaxBulkSize {
+		return errors.ErrBatchTooLarge
+	}
+
+	for _, slug := range filter.Slugs {
+		if !IsValidSlug(slug) {
+			return errors.ErrInvalidSegmentSlug
+		}
+	}
+
+	for _, userID := range filter.UserIDs {
+		if _, err := uuid.Parse(userID); err != nil {
+			return errors.ErrInvalidUserID
+		}
+	}
+
+	return nil
+}
+
+// formatEventID formats stream position as event id in the form of "tx-id".
+func formatEventID(position models.EventPosition) string {
+	return strconv.FormatInt(position.Tx, 10) + "-" + strconv.FormatInt(position.ID, 10)
+}
+
+// parseEventID parses event id made by formatEventID.
+func parseEventID(id string) (models.EventPosition, bool) {
+	var position models.EventPosition
+
+	tx, seq, found := strings.Cut(id, "-")
+	if !found {
+		return position, false
+	}
+
+	var err error
+
+	if position.Tx, err = strconv.ParseInt(tx, 10, 64); err != nil || position.Tx < 0 {
+		return position, false
+	}
+
+	if position.ID, err = strconv.ParseInt(seq, 10, 64); err != nil || position.ID < 0 {
+		return position, false
+	}
+
+	return position, true
+}
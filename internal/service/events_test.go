@@ -0,0 +1,209 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/service"
+)
+
+func TestService_StreamEvents(t *testing.T) {
+	a := assert.New(t)
+
+	event := models.StreamEvent{
+		Position: models.EventPosition{Tx: 101, ID: 7},
+		Event: models.OutboxEvent{
+			ID:     7,
+			Kind:   models.EventMembership,
+			UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			Slug:   "AVITO_TEST",
+			Method: models.MethodAdded,
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		filter      *models.EventFilter
+		lastEventID string
+
+		headReturn   models.EventPosition
+		eventsInput  *models.EventFilter
+		eventsReturn []models.StreamEvent
+		eventsError  error
+		sendError    error
+
+		expectedIDs   []string
+		expectedError error
+
+		expectingHeadCall   bool
+		expectingEventsCall bool
+	}{
+		{
+			name:       "Stream from now",
+			filter:     &models.EventFilter{Slugs: []string{"AVITO_VOICE", "AVITO_TEST", "AVITO_TEST"}},
+			headReturn: models.EventPosition{Tx: 100},
+			eventsInput: &models.EventFilter{
+				Slugs: []string{"AVITO_TEST", "AVITO_VOICE"},
+				After: models.EventPosition{Tx: 100},
+				Limit: service.EventBatchSize,
+			},
+			eventsReturn:        []models.StreamEvent{event},
+			expectedIDs:         []string{"101-7"},
+			expectingHeadCall:   true,
+			expectingEventsCall: true,
+		},
+		{
+			name:        "Resumed after last event",
+			filter:      &models.EventFilter{UserIDs: []string{"80b0b88d-379e-11ee-8bf7-0242c0a80002"}},
+			lastEventID: "101-6",
+			eventsInput: &models.EventFilter{
+				UserIDs: []string{"80b0b88d-379e-11ee-8bf7-0242c0a80002"},
+				After:   models.EventPosition{Tx: 101, ID: 6},
+				Limit:   service.EventBatchSize,
+			},
+			eventsReturn:        []models.StreamEvent{event},
+			expectedIDs:         []string{"101-7"},
+			expectingEventsCall: true,
+		},
+		{
+			name:          "Invalid last event id",
+			filter:        &models.EventFilter{},
+			lastEventID:   "101",
+			expectedError: errors.ErrInvalidEventID,
+		},
+		{
+			name:          "Negative last event id",
+			filter:        &models.EventFilter{},
+			lastEventID:   "101--6",
+			expectedError: errors.ErrInvalidEventID,
+		},
+		{
+			name:          "Invalid slug",
+			filter:        &models.EventFilter{Slugs: []string{"avito"}},
+			expectedError: errors.ErrInvalidSegmentSlug,
+		},
+		{
+			name:          "Invalid user id",
+			filter:        &models.EventFilter{UserIDs: []string{"123"}},
+			expectedError: errors.ErrInvalidUserID,
+		},
+		{
+			name:        "Client gone",
+			filter:      &models.EventFilter{},
+			lastEventID: "101-6",
+			eventsInput: &models.EventFilter{
+				After: models.EventPosition{Tx: 101, ID: 6},
+				Limit: service.EventBatchSize,
+			},
+			eventsReturn:        []models.StreamEvent{event},
+			sendError:           os.ErrClosed,
+			expectedIDs:         []string{"101-7"},
+			expectedError:       os.ErrClosed,
+			expectingEventsCall: true,
+		},
+		{
+			name:        "Some internal error",
+			filter:      &models.EventFilter{},
+			lastEventID: "101-6",
+			eventsInput: &models.EventFilter{
+				After: models.EventPosition{Tx: 101, ID: 6},
+				Limit: service.EventBatchSize,
+			},
+			eventsError:         os.ErrInvalid,
+			expectedError:       os.ErrInvalid,
+			expectingEventsCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			eventRepo := NewMockEventRepository(ctrl)
+
+			if tc.expectingHeadCall {
+				eventRepo.EXPECT().EventsHead(ctx).Return(tc.headReturn, nil)
+			}
+
+			if tc.expectingEventsCall {
+				eventRepo.EXPECT().Events(ctx, tc.eventsInput).Return(tc.eventsReturn, tc.eventsError)
+			}
+
+			var ids []string
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, nil, nil, nil, eventRepo, nil, nil, nil, nil, &config.Config{}, zp)
+
+			err := serv.StreamEvents(ctx, tc.filter, tc.lastEventID, func(events []models.StreamEvent) error {
+				for _, event := range events {
+					ids = append(ids, event.ID)
+				}
+
+				cancel()
+
+				return tc.sendError
+			})
+
+			a.Equal(tc.expectedError, err)
+			a.Equal(tc.expectedIDs, ids)
+		})
+	}
+}
+
+func TestService_StreamEventsFollowsStream(t *testing.T) {
+	a := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	batch := make([]models.StreamEvent, service.EventBatchSize)
+	for i := range batch {
+		batch[i].Position = models.EventPosition{Tx: 101, ID: int64(i + 1)}
+	}
+
+	eventRepo := NewMockEventRepository(ctrl)
+	gomock.InOrder(
+		eventRepo.EXPECT().Events(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, filter *models.EventFilter) ([]models.StreamEvent, error) {
+				a.Equal(models.EventPosition{Tx: 100, ID: 1}, filter.After)
+				return batch, nil
+			}),
+		eventRepo.EXPECT().Events(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, filter *models.EventFilter) ([]models.StreamEvent, error) {
+				a.Equal(models.EventPosition{Tx: 101, ID: service.EventBatchSize}, filter.After)
+				return nil, nil
+			}),
+	)
+
+	var polls int
+
+	zp, _ := zap.NewDevelopment()
+	serv := service.New(nil, nil, nil, nil, eventRepo, nil, nil, nil, nil, &config.Config{}, zp)
+
+	err := serv.StreamEvents(ctx, &models.EventFilter{}, "100-1", func(events []models.StreamEvent) error {
+		// Empty poll means the stream caught up.
+		if polls++; len(events) == 0 {
+			cancel()
+		}
+
+		return nil
+	})
+
+	a.NoError(err)
+	a.Equal(2, polls)
+}
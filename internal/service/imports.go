@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	goerrors "errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/problem"
+)
+
+// byteOrderMark is prepended to CSV files by spreadsheet editors.
+const byteOrderMark = "\ufeff"
+
+// SegmentImport spools uploaded CSV of user ids to storage and creates tracked job which reads it
+// and adds the users to the segment in background, so the upload is neither parsed nor kept in memory here.
+func (s *Service) SegmentImport(ctx context.Context, slug string, file io.Reader) (*models.ImportJob, error) {
+	err := s.activeSegment(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.ImportJob{
+		ID:        uuid.NewString(),
+		Slug:      slug,
+		Status:    models.JobQueued,
+		CreatedAt: time.Now(),
+		Audit:     auditEntry(ctx, models.AuditSegmentImport, "", []string{slug}),
+	}
+
+	upload := &uploadReader{reader: file}
+
+	err = s.storage.Put(ctx, importFileName(job.ID), func(w io.Writer) error {
+		size, err := io.Copy(w, upload)
+		if err == nil && size == 0 {
+			// Failing the write keeps empty file out of storage.
+			return errors.ErrInvalidImportFile
+		}
+
+		return err
+	})
+	if err != nil {
+		if upload.err != nil || goerrors.Is(err, errors.ErrInvalidImportFile) {
+			s.logger.Info("Unable to read import file", zap.Error(err))
+			return nil, errors.ErrInvalidImportFile
+		}
+
+		s.logger.Error("Unable to store import file", zap.Error(err))
+
+		return nil, err
+	}
+
+	if err = s.jobRepo.CreateImport(ctx, job); err != nil {
+		s.deleteImportFile(job.ID)
+		return nil, err
+	}
+
+	s.imports.add(job.ID)
+
+	err = s.scheduler.Submit(func(ctx context.Context) {
+		s.runImport(ctx, job.ID, slug, job.Audit, 0)
+	})
+	if err != nil {
+		s.imports.remove(job.ID)
+		s.finishImport(job.ID, models.JobFailed, err.Error())
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// SegmentImportGet returns import job state with a page of rejected rows.
+func (s *Service) SegmentImportGet(ctx context.Context, slug, id string, limit, offset int) (*models.ImportJob, error) {
+	if !IsValidSlug(slug) {
+		return nil, errors.ErrInvalidSegmentSlug
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.ErrImportNotFound
+	}
+
+	if limit <= 0 || limit > MaxPageLimit || offset < 0 {
+		return nil, errors.ErrInvalidPagination
+	}
+
+	job, err := s.jobRepo.GetImport(ctx, id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if job == nil || job.Slug != slug {
+		return nil, errors.ErrImportNotFound
+	}
+
+	return job, nil
+}
+
+// runImport reads stored import file in chunks, writing memberships and rejected rows of each chunk
+// and reporting progress after it. Rows up to processed ones are skipped. Every imported membership
+// is recorded to audit log as made by the actor of audit entry. Import stopped along with the application
+// is left unfinished, so it is resumed once its lease expires.
+func (s *Service) runImport(ctx context.Context, id, slug string, audit *models.AuditEntry, processed int) {
+	defer s.imports.remove(id)
+
+	if err := s.jobRepo.StartImport(ctx, id, time.Now()); err != nil {
+		s.logger.Error("Unable to start import", zap.String("job", id), zap.Error(err))
+	}
+
+	// fail finishes the job unless it was interrupted, so it is resumed later.
+	fail := func(err error) {
+		if ctx.Err() != nil {
+			s.logger.Warn("Import interrupted", zap.String("job", id), zap.Int("processed", processed))
+			return
+		}
+
+		s.logger.Error("Import failed", zap.String("job", id), zap.Error(err))
+		s.finishImport(id, models.JobFailed, err.Error())
+	}
+
+	file, err := s.storage.Open(ctx, importFileName(id))
+	if err != nil {
+		if goerrors.Is(err, errors.ErrReportNotFound) {
+			err = errors.ErrJobInterrupted
+		}
+
+		fail(err)
+
+		return
+	}
+	defer file.Close()
+
+	reader := newImportReader(file, slug)
+
+	// Rows written before the job was interrupted are skipped chunk by chunk.
+	for reader.read < processed {
+		size := processed - reader.read
+		if size > ImportChunkSize {
+			size = ImportChunkSize
+		}
+
+		before := reader.read
+
+		if _, _, err = reader.next(size); err != nil || reader.read == before {
+			fail(errors.ErrJobInterrupted)
+			return
+		}
+	}
+
+	for {
+		reqs, rowErrors, err := reader.next(ImportChunkSize)
+		if err != nil {
+			s.logger.Info("Unable to parse import file", zap.String("job", id), zap.Error(err))
+			fail(errors.ErrInvalidImportFile)
+
+			return
+		}
+
+		if reader.read == processed {
+			break
+		}
+
+		if len(reqs) > 0 {
+			if err = s.userRepo.SetSegmentsBulk(ctx, reqs, bulkAuditEntries(audit, reqs)); err != nil {
+				fail(err)
+				return
+			}
+		}
+
+		if err = s.jobRepo.ProgressImport(ctx, id, reader.read, rowErrors); err != nil {
+			fail(err)
+			return
+		}
+
+		processed = reader.read
+	}
+
+	if processed == 0 {
+		s.finishImport(id, models.JobFailed, errors.ErrInvalidImportFile.Error())
+		return
+	}
+
+	s.finishImport(id, models.JobDone, "")
+}
+
+// finishImport stores final job status even if the application is already stopping
+// and drops import file which is no longer needed.
+func (s *Service) finishImport(id, status, message string) {
+	if err := s.jobRepo.FinishImport(context.Background(), id, status, message, time.Now()); err != nil {
+		s.logger.Error("Unable to finish import", zap.String("job", id), zap.Error(err))
+		return
+	}
+
+	s.deleteImportFile(id)
+}
+
+func (s *Service) deleteImportFile(id string) {
+	if err := s.storage.Delete(context.Background(), importFileName(id)); err != nil {
+		s.logger.Error("Unable to delete import file", zap.String("job", id), zap.Error(err))
+	}
+}
+
+// importFileName keeps import files apart from reports, which names have no prefix.
+func importFileName(id string) string {
+	return "imports/" + id + ".csv"
+}
+
+// uploadReader remembers error of reading the upload, telling it apart from errors of storage.
+type uploadReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (u *uploadReader) Read(p []byte) (int, error) {
+	n, err := u.reader.Read(p)
+	if err != nil && !goerrors.Is(err, io.EOF) {
+		u.err = err
+	}
+
+	return n, err
+}
+
+// importReader reads CSV of user ids with optional RFC3339 expiry in the second column.
+// Header row is skipped, malformed rows become row errors.
+type importReader struct {
+	reader *csv.Reader
+	slug   string
+	// read is the amount of rows read, not counting the header.
+	read  int
+	first bool
+}
+
+func newImportReader(r io.Reader, slug string) *importReader {
+	reader := csv.NewReader(r)
+
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	return &importReader{reader: reader, slug: slug, first: true}
+}
+
+// next reads up to size rows into memberships and rejected rows. Nothing is read once the file is over.
+func (i *importReader) next(size int) ([]models.UserSetRequest, []models.ImportRowError, error) {
+	var (
+		reqs      = make([]models.UserSetRequest, 0, size)
+		rowErrors = make([]models.ImportRowError, 0)
+	)
+
+	for start := i.read; i.read-start < size; {
+		record, err := i.reader.Read()
+		if goerrors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line, _ := i.reader.FieldPos(0)
+		userID := strings.TrimSpace(record[0])
+
+		if i.first {
+			i.first = false
+			userID = strings.TrimSpace(strings.TrimPrefix(record[0], byteOrderMark))
+
+			if isImportHeader(userID) {
+				continue
+			}
+		}
+
+		i.read++
+
+		segment, err := i.segment(userID, record)
+		if err != nil {
+			rowErrors = append(rowErrors, problem.RowError(line, userID, err))
+			continue
+		}
+
+		reqs = append(reqs, models.UserSetRequest{UserID: userID, Segments: []models.UserSegment{segment}})
+	}
+
+	return reqs, rowErrors, nil
+}
+
+// segment checks user id and expiry of the row and returns membership it adds.
+func (i *importReader) segment(userID string, record []string) (models.UserSegment, error) {
+	segment := models.UserSegment{Slug: i.slug}
+
+	if userID == "" {
+		return segment, errors.ErrInvalidUserID
+	}
+
+	if _, err := uuid.Parse(userID); err != nil {
+		return segment, errors.WithUserIDs(errors.ErrInvalidUserID, userID)
+	}
+
+	if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+		expire, err := time.Parse(time.RFC3339, strings.TrimSpace(record[1]))
+		if err != nil {
+			return segment, errors.ErrInvalidExpiry
+		}
+
+		segment.Expire = expire
+	}
+
+	return segment, IsValidSegment(segment)
+}
+
+func isImportHeader(field string) bool {
+	switch strings.ToLower(field) {
+	case "user_id", "userid", "id":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,433 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/service"
+	"github.com/dupreehkuda/avito-segments/internal/worker"
+)
+
+// newFileStorage returns storage mock keeping files in memory.
+func newFileStorage(ctrl *gomock.Controller) (*MockReportStorage, map[string]string) {
+	files := make(map[string]string)
+	storage := NewMockReportStorage(ctrl)
+
+	storage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, name string, fn func(w io.Writer) error) error {
+			buf := &bytes.Buffer{}
+			if err := fn(buf); err != nil {
+				return err
+			}
+
+			files[name] = buf.String()
+
+			return nil
+		})
+	storage.EXPECT().Open(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, name string) (io.ReadCloser, error) {
+			file, ok := files[name]
+			if !ok {
+				return nil, errors.ErrReportNotFound
+			}
+
+			return io.NopCloser(strings.NewReader(file)), nil
+		})
+	storage.EXPECT().Delete(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, name string) error {
+			delete(files, name)
+			return nil
+		})
+
+	return storage, files
+}
+
+// importFile makes CSV file with header and given amount of valid rows.
+func importFile(rows int) string {
+	var b strings.Builder
+
+	b.WriteString("user_id,expire\n")
+
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(&b, "80b0b88d-379e-11ee-8bf7-%012d\n", i)
+	}
+
+	return b.String()
+}
+
+func TestService_SegmentImport(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name string
+		slug string
+		file string
+
+		getReturn *models.Segment
+		getError  error
+
+		createError error
+		submitError error
+
+		expectedError error
+
+		expectingGetCall    bool
+		expectingCreateCall bool
+		expectingSubmitCall bool
+	}{
+		{
+			name:                "Import queued",
+			slug:                "TEST_SLUG",
+			file:                importFile(2),
+			getReturn:           &models.Segment{Slug: "TEST_SLUG"},
+			expectingGetCall:    true,
+			expectingCreateCall: true,
+			expectingSubmitCall: true,
+		},
+		{
+			name:          "Invalid slug",
+			slug:          "test-slug",
+			file:          importFile(1),
+			expectedError: errors.ErrInvalidSegmentSlug,
+		},
+		{
+			name:             "Empty file",
+			slug:             "TEST_SLUG",
+			getReturn:        &models.Segment{Slug: "TEST_SLUG"},
+			expectedError:    errors.ErrInvalidImportFile,
+			expectingGetCall: true,
+		},
+		{
+			name:             "Segment not found",
+			slug:             "TEST_SLUG",
+			file:             importFile(1),
+			expectedError:    errors.ErrSegmentNotFound,
+			expectingGetCall: true,
+		},
+		{
+			name:             "Segment deleted",
+			slug:             "TEST_SLUG",
+			file:             importFile(1),
+			getReturn:        &models.Segment{Slug: "TEST_SLUG", DeletedAt: time.Now()},
+			expectedError:    errors.ErrAlreadyDeleted,
+			expectingGetCall: true,
+		},
+		{
+			name:                "Queue is full",
+			slug:                "TEST_SLUG",
+			file:                importFile(1),
+			getReturn:           &models.Segment{Slug: "TEST_SLUG"},
+			submitError:         errors.ErrQueueFull,
+			expectedError:       errors.ErrQueueFull,
+			expectingGetCall:    true,
+			expectingCreateCall: true,
+			expectingSubmitCall: true,
+		},
+		{
+			name:                "Some internal error",
+			slug:                "TEST_SLUG",
+			file:                importFile(1),
+			getReturn:           &models.Segment{Slug: "TEST_SLUG"},
+			createError:         os.ErrInvalid,
+			expectedError:       os.ErrInvalid,
+			expectingGetCall:    true,
+			expectingCreateCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			segmentRepo := NewMockSegmentRepository(ctrl)
+			jobRepo := NewMockJobRepository(ctrl)
+			storage, files := newFileStorage(ctrl)
+			scheduler := NewMockScheduler(ctrl)
+
+			if tc.expectingGetCall {
+				segmentRepo.EXPECT().Get(context.Background(), tc.slug).Return(tc.getReturn, tc.getError)
+			}
+
+			if tc.expectingCreateCall {
+				jobRepo.EXPECT().CreateImport(context.Background(), gomock.Any()).Return(tc.createError)
+			}
+
+			if tc.expectingSubmitCall {
+				scheduler.EXPECT().Submit(gomock.Any()).Return(tc.submitError)
+			}
+
+			if tc.submitError != nil {
+				jobRepo.EXPECT().FinishImport(gomock.Any(), gomock.Any(), models.JobFailed, tc.submitError.Error(), gomock.Any()).Return(nil)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, segmentRepo, jobRepo, nil, nil, nil, nil, storage, scheduler, &config.Config{}, zp)
+
+			job, err := serv.SegmentImport(context.Background(), tc.slug, strings.NewReader(tc.file))
+
+			a.Equal(tc.expectedError, err)
+
+			if tc.expectedError != nil {
+				// File of import which is not queued is not kept.
+				a.Empty(files)
+				return
+			}
+
+			a.Equal(models.JobQueued, job.Status)
+			a.Equal(map[string]string{"imports/" + job.ID + ".csv": tc.file}, files)
+		})
+	}
+}
+
+func TestService_SegmentImportRun(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name      string
+		file      string
+		bulkError error
+		cancelled bool
+		removed   bool
+
+		expectedChunks    int
+		expectedProgress  []int
+		expectedRowErrors []models.ImportRowError
+		expectedStatus    string
+		expectedMessage   string
+	}{
+		{
+			name:             "Written in chunks",
+			file:             importFile(service.ImportChunkSize + 1),
+			expectedChunks:   2,
+			expectedProgress: []int{service.ImportChunkSize, service.ImportChunkSize + 1},
+			expectedStatus:   models.JobDone,
+		},
+		{
+			name: "Rows rejected",
+			file: "\ufeffuser_id,expire\n" +
+				"80b0b88d-379e-11ee-8bf7-0242c0a80002,2099-08-26T19:00:00Z\n" +
+				"123\n" +
+				",\n" +
+				"80b0b88d-379e-11ee-8bf7-0242c0a80003,tomorrow\n" +
+				"80b0b88d-379e-11ee-8bf7-0242c0a80004,2022-08-26T19:00:00Z\n",
+			expectedChunks:   1,
+			expectedProgress: []int{5},
+			expectedRowErrors: []models.ImportRowError{
+				{Row: 3, UserID: "123", Code: "invalid_user_id"},
+				{Row: 4, Code: "invalid_user_id"},
+				{Row: 5, UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80003", Code: "invalid_expiry"},
+				{Row: 6, UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80004", Code: "segment_expired"},
+			},
+			expectedStatus: models.JobDone,
+		},
+		{
+			name:            "No rows",
+			file:            "user_id\n",
+			expectedStatus:  models.JobFailed,
+			expectedMessage: errors.ErrInvalidImportFile.Error(),
+		},
+		{
+			name:            "Malformed file",
+			file:            "user_id\n\"80b0b88d-379e-11ee-8bf7-0242c0a80002\n",
+			expectedStatus:  models.JobFailed,
+			expectedMessage: errors.ErrInvalidImportFile.Error(),
+		},
+		{
+			name:            "Import file is gone",
+			file:            importFile(1),
+			removed:         true,
+			expectedStatus:  models.JobFailed,
+			expectedMessage: errors.ErrJobInterrupted.Error(),
+		},
+		{
+			name:            "Some internal error",
+			file:            importFile(service.ImportChunkSize + 1),
+			bulkError:       os.ErrInvalid,
+			expectedChunks:  1,
+			expectedStatus:  models.JobFailed,
+			expectedMessage: os.ErrInvalid.Error(),
+		},
+		{
+			name:           "Interrupted on stop",
+			file:           importFile(service.ImportChunkSize + 1),
+			bulkError:      context.Canceled,
+			cancelled:      true,
+			expectedChunks: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := NewMockUserRepository(ctrl)
+			segmentRepo := NewMockSegmentRepository(ctrl)
+			jobRepo := NewMockJobRepository(ctrl)
+			storage, files := newFileStorage(ctrl)
+			scheduler := NewMockScheduler(ctrl)
+
+			var task worker.Task
+
+			segmentRepo.EXPECT().Get(gomock.Any(), "TEST_SLUG").Return(&models.Segment{Slug: "TEST_SLUG"}, nil)
+			jobRepo.EXPECT().CreateImport(gomock.Any(), gomock.Any()).Return(nil)
+			scheduler.EXPECT().Submit(gomock.Any()).DoAndReturn(func(t worker.Task) error {
+				task = t
+				return nil
+			})
+
+			jobRepo.EXPECT().StartImport(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			userRepo.EXPECT().SetSegmentsBulk(gomock.Any(), gomock.Any(), gomock.Any()).Return(tc.bulkError).Times(tc.expectedChunks)
+
+			var rowErrors []models.ImportRowError
+
+			for _, processed := range tc.expectedProgress {
+				jobRepo.EXPECT().ProgressImport(gomock.Any(), gomock.Any(), processed, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _ int, errs []models.ImportRowError) error {
+						for _, e := range errs {
+							rowErrors = append(rowErrors, models.ImportRowError{Row: e.Row, UserID: e.UserID, Code: e.Code})
+						}
+
+						return nil
+					})
+			}
+
+			// Import interrupted by stop is left unfinished to be resumed.
+			if !tc.cancelled {
+				jobRepo.EXPECT().FinishImport(gomock.Any(), gomock.Any(), tc.expectedStatus, tc.expectedMessage, gomock.Any()).Return(nil)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, segmentRepo, jobRepo, nil, nil, nil, nil, storage, scheduler, &config.Config{}, zp)
+
+			_, err := serv.SegmentImport(context.Background(), "TEST_SLUG", strings.NewReader(tc.file))
+			a.NoError(err)
+
+			if tc.removed {
+				for name := range files {
+					delete(files, name)
+				}
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tc.cancelled {
+				cancel()
+			}
+
+			task(ctx)
+
+			a.Equal(tc.expectedRowErrors, rowErrors)
+
+			// File is kept only until the job is finished.
+			a.Equal(tc.cancelled, len(files) > 0)
+		})
+	}
+}
+
+func TestService_SegmentImportGet(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name   string
+		slug   string
+		id     string
+		limit  int
+		offset int
+
+		repositoryReturn *models.ImportJob
+		repositoryError  error
+
+		expectedError error
+
+		expectingRepositoryCall bool
+	}{
+		{
+			name:                    "Job found",
+			slug:                    "TEST_SLUG",
+			id:                      "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			limit:                   50,
+			repositoryReturn:        &models.ImportJob{Slug: "TEST_SLUG", Status: models.JobDone},
+			expectingRepositoryCall: true,
+		},
+		{
+			name:          "Invalid job id",
+			slug:          "TEST_SLUG",
+			id:            "123",
+			limit:         50,
+			expectedError: errors.ErrImportNotFound,
+		},
+		{
+			name:          "Invalid pagination",
+			slug:          "TEST_SLUG",
+			id:            "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			limit:         service.MaxPageLimit + 1,
+			expectedError: errors.ErrInvalidPagination,
+		},
+		{
+			name:                    "Job of another segment",
+			slug:                    "TEST_SLUG",
+			id:                      "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			limit:                   50,
+			repositoryReturn:        &models.ImportJob{Slug: "OTHER_SLUG"},
+			expectedError:           errors.ErrImportNotFound,
+			expectingRepositoryCall: true,
+		},
+		{
+			name:                    "Job not found",
+			slug:                    "TEST_SLUG",
+			id:                      "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			limit:                   50,
+			expectedError:           errors.ErrImportNotFound,
+			expectingRepositoryCall: true,
+		},
+		{
+			name:                    "Some internal error",
+			slug:                    "TEST_SLUG",
+			id:                      "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			limit:                   50,
+			repositoryError:         os.ErrInvalid,
+			expectedError:           os.ErrInvalid,
+			expectingRepositoryCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			jobRepo := NewMockJobRepository(ctrl)
+
+			if tc.expectingRepositoryCall {
+				jobRepo.EXPECT().GetImport(context.Background(), tc.id, tc.limit, tc.offset).Return(tc.repositoryReturn, tc.repositoryError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, nil, jobRepo, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			job, err := serv.SegmentImportGet(context.Background(), tc.slug, tc.id, tc.limit, tc.offset)
+
+			a.Equal(tc.expectedError, err)
+
+			if tc.expectedError == nil {
+				a.Equal(tc.repositoryReturn, job)
+			}
+		})
+	}
+}
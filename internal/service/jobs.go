@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// KeepJobs prolongs lease of background jobs queued or run by this instance
+// and takes over jobs which lease has expired, as the instance holding them has stopped.
+func (s *Service) KeepJobs(ctx context.Context) {
+	now := time.Now()
+
+	if ids := s.imports.list(); len(ids) > 0 {
+		if err := s.jobRepo.HeartbeatImports(ctx, ids, now); err != nil {
+			s.logger.Error("Unable to prolong import jobs", zap.Error(err))
+		}
+	}
+
+	if ids := s.reports.list(); len(ids) > 0 {
+		if err := s.jobRepo.HeartbeatReportJobs(ctx, ids, now); err != nil {
+			s.logger.Error("Unable to prolong report jobs", zap.Error(err))
+		}
+	}
+
+	imports, err := s.jobRepo.ClaimStaleImports(ctx, now.Add(-JobLease), now)
+	if err != nil {
+		s.logger.Error("Unable to claim abandoned import jobs", zap.Error(err))
+	}
+
+	for _, job := range imports {
+		s.resumeImport(job)
+	}
+
+	reports, err := s.jobRepo.ClaimStaleReportJobs(ctx, now.Add(-JobLease), now)
+	if err != nil {
+		s.logger.Error("Unable to claim abandoned report jobs", zap.Error(err))
+	}
+
+	for _, job := range reports {
+		s.resumeReport(job)
+	}
+}
+
+// resumeImport queues the rest of abandoned import job, which continues reading its file after processed rows.
+func (s *Service) resumeImport(job models.ImportJob) {
+	s.imports.add(job.ID)
+
+	err := s.scheduler.Submit(func(ctx context.Context) {
+		s.runImport(ctx, job.ID, job.Slug, job.Audit, job.Processed)
+	})
+	if err != nil {
+		// Job is left to be claimed again once its lease expires.
+		s.imports.remove(job.ID)
+		s.logger.Warn("Unable to resume import", zap.String("job", job.ID), zap.Error(err))
+
+		return
+	}
+
+	s.logger.Info("Import resumed", zap.String("job", job.ID), zap.Int("processed", job.Processed))
+}
+
+// resumeReport queues abandoned report job to be generated from scratch.
+func (s *Service) resumeReport(job models.ReportJob) {
+	s.reports.add(job.ID)
+
+	err := s.scheduler.Submit(func(ctx context.Context) {
+		s.runReport(ctx, job)
+	})
+	if err != nil {
+		// Job is left to be claimed again once its lease expires.
+		s.reports.remove(job.ID)
+		s.logger.Warn("Unable to resume report", zap.String("job", job.ID), zap.Error(err))
+
+		return
+	}
+
+	s.logger.Info("Report resumed", zap.String("job", job.ID))
+}
+
+// jobSet keeps ids of jobs held by this instance.
+type jobSet struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+func newJobSet() *jobSet {
+	return &jobSet{ids: make(map[string]struct{})}
+}
+
+func (j *jobSet) add(id string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.ids[id] = struct{}{}
+}
+
+func (j *jobSet) remove(id string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	delete(j.ids, id)
+}
+
+func (j *jobSet) list() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	ids := make([]string, 0, len(j.ids))
+	for id := range j.ids {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
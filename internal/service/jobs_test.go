@@ -0,0 +1,236 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/service"
+	"github.com/dupreehkuda/avito-segments/internal/worker"
+)
+
+// testImportAudit is the audit entry of user who started import, kept with the job.
+var testImportAudit = &models.AuditEntry{
+	Actor:     "key:80b0b88d-379e-11ee-8bf7-0242c0a80009",
+	ActorName: "importer",
+	RequestID: "f2b1c0de",
+	Operation: models.AuditSegmentImport,
+	Slugs:     []string{"TEST_SLUG"},
+}
+
+func TestService_KeepJobs(t *testing.T) {
+	a := assert.New(t)
+
+	const importFile = "user_id\n" +
+		"80b0b88d-379e-11ee-8bf7-0242c0a80000\n" +
+		"80b0b88d-379e-11ee-8bf7-0242c0a80001\n" +
+		"80b0b88d-379e-11ee-8bf7-0242c0a80002\n" +
+		"80b0b88d-379e-11ee-8bf7-0242c0a80003\n"
+
+	testCases := []struct {
+		name string
+
+		claimReturn       []models.ImportJob
+		claimError        error
+		importFile        string
+		claimReportReturn []models.ReportJob
+		submitError       error
+
+		expectedUsers   []string
+		expectedFinish  string
+		expectedMessage string
+
+		expectingSubmitCall  bool
+		expectingImportCalls bool
+		expectingReportCalls bool
+		reportInterrupted    bool
+	}{
+		{
+			name: "Abandoned import resumed",
+			claimReturn: []models.ImportJob{
+				{ID: "80b0b88d-379e-11ee-8bf7-0242c0a80001", Slug: "TEST_SLUG", Audit: testImportAudit, Failed: 1, Processed: 2},
+			},
+			importFile:           importFile,
+			expectedUsers:        []string{"80b0b88d-379e-11ee-8bf7-0242c0a80002", "80b0b88d-379e-11ee-8bf7-0242c0a80003"},
+			expectedFinish:       models.JobDone,
+			expectingSubmitCall:  true,
+			expectingImportCalls: true,
+		},
+		{
+			name: "Import file is gone",
+			claimReturn: []models.ImportJob{
+				{ID: "80b0b88d-379e-11ee-8bf7-0242c0a80001", Slug: "TEST_SLUG", Audit: testImportAudit, Processed: 2},
+			},
+			expectedFinish:       models.JobFailed,
+			expectedMessage:      errors.ErrJobInterrupted.Error(),
+			expectingSubmitCall:  true,
+			expectingImportCalls: true,
+		},
+		{
+			name: "Import file is shorter",
+			claimReturn: []models.ImportJob{
+				{ID: "80b0b88d-379e-11ee-8bf7-0242c0a80001", Slug: "TEST_SLUG", Audit: testImportAudit, Processed: 5},
+			},
+			importFile:           importFile,
+			expectedFinish:       models.JobFailed,
+			expectedMessage:      errors.ErrJobInterrupted.Error(),
+			expectingSubmitCall:  true,
+			expectingImportCalls: true,
+		},
+		{
+			name: "Queue is full",
+			claimReturn: []models.ImportJob{
+				{ID: "80b0b88d-379e-11ee-8bf7-0242c0a80001", Slug: "TEST_SLUG", Audit: testImportAudit},
+			},
+			submitError:         errors.ErrQueueFull,
+			expectingSubmitCall: true,
+		},
+		{
+			name: "Abandoned report rerun",
+			claimReportReturn: []models.ReportJob{
+				{ID: "80b0b88d-379e-11ee-8bf7-0242c0a80004", ReportID: "fcbf375313027e3ca47841baf5736886", Format: models.FormatCSV},
+			},
+			expectingSubmitCall:  true,
+			expectingReportCalls: true,
+		},
+		{
+			name: "Rerun report interrupted on stop",
+			claimReportReturn: []models.ReportJob{
+				{ID: "80b0b88d-379e-11ee-8bf7-0242c0a80004", ReportID: "fcbf375313027e3ca47841baf5736886", Format: models.FormatCSV},
+			},
+			expectingSubmitCall:  true,
+			expectingReportCalls: true,
+			reportInterrupted:    true,
+		},
+		{
+			name: "Nothing abandoned",
+		},
+		{
+			name:       "Some internal error",
+			claimError: os.ErrInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := NewMockUserRepository(ctrl)
+			jobRepo := NewMockJobRepository(ctrl)
+			storage := NewMockReportStorage(ctrl)
+			scheduler := NewMockScheduler(ctrl)
+
+			var task worker.Task
+
+			jobRepo.EXPECT().ClaimStaleImports(context.Background(), gomock.Any(), gomock.Any()).Return(tc.claimReturn, tc.claimError)
+			jobRepo.EXPECT().ClaimStaleReportJobs(context.Background(), gomock.Any(), gomock.Any()).Return(tc.claimReportReturn, nil)
+
+			if tc.expectingSubmitCall {
+				scheduler.EXPECT().Submit(gomock.Any()).DoAndReturn(func(t worker.Task) error {
+					task = t
+					return tc.submitError
+				})
+			}
+
+			if tc.expectingImportCalls {
+				job := tc.claimReturn[0]
+				name := "imports/" + job.ID + ".csv"
+
+				jobRepo.EXPECT().StartImport(gomock.Any(), job.ID, gomock.Any()).Return(nil)
+
+				if tc.importFile == "" {
+					storage.EXPECT().Open(gomock.Any(), name).Return(nil, errors.ErrReportNotFound)
+				} else {
+					storage.EXPECT().Open(gomock.Any(), name).Return(io.NopCloser(strings.NewReader(tc.importFile)), nil)
+				}
+
+				if len(tc.expectedUsers) > 0 {
+					// Resumed import is audited as made by the user who started it.
+					audit := make([]models.AuditEntry, 0, len(tc.expectedUsers))
+					for _, userID := range tc.expectedUsers {
+						entry := *testImportAudit
+						entry.UserID = userID
+
+						audit = append(audit, entry)
+					}
+
+					userRepo.EXPECT().SetSegmentsBulk(gomock.Any(), gomock.Len(len(tc.expectedUsers)), audit).Return(nil)
+					jobRepo.EXPECT().ProgressImport(gomock.Any(), job.ID, job.Processed+len(tc.expectedUsers), gomock.Len(0)).Return(nil)
+				}
+
+				jobRepo.EXPECT().FinishImport(gomock.Any(), job.ID, tc.expectedFinish, tc.expectedMessage, gomock.Any()).Return(nil)
+				storage.EXPECT().Delete(gomock.Any(), name).Return(nil)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tc.expectingReportCalls {
+				job := tc.claimReportReturn[0]
+
+				jobRepo.EXPECT().StartReportJob(gomock.Any(), job.ID, gomock.Any()).Return(nil)
+				// Report interrupted by stop is left unfinished to be run again.
+				if tc.reportInterrupted {
+					storage.EXPECT().Put(gomock.Any(), "80b0b88d-379e-11ee-8bf7-0242c0a80004.csv", gomock.Any()).Return(context.Canceled)
+				} else {
+					storage.EXPECT().Put(gomock.Any(), "80b0b88d-379e-11ee-8bf7-0242c0a80004.csv", gomock.Any()).Return(nil)
+					jobRepo.EXPECT().FinishReportJob(gomock.Any(), gomock.Any()).Return(nil)
+				}
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, nil, jobRepo, nil, nil, nil, nil, storage, scheduler, &config.Config{}, zp)
+
+			serv.KeepJobs(context.Background())
+
+			if tc.reportInterrupted {
+				cancel()
+			}
+
+			if tc.expectingImportCalls || tc.expectingReportCalls {
+				a.NotNil(task)
+				task(ctx)
+			}
+		})
+	}
+}
+
+func TestService_KeepJobsHeartbeat(t *testing.T) {
+	a := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	segmentRepo := NewMockSegmentRepository(ctrl)
+	jobRepo := NewMockJobRepository(ctrl)
+	storage := NewMockReportStorage(ctrl)
+	scheduler := NewMockScheduler(ctrl)
+
+	segmentRepo.EXPECT().Get(gomock.Any(), "TEST_SLUG").Return(&models.Segment{Slug: "TEST_SLUG"}, nil)
+	storage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	jobRepo.EXPECT().CreateImport(gomock.Any(), gomock.Any()).Return(nil)
+	scheduler.EXPECT().Submit(gomock.Any()).Return(nil)
+
+	zp, _ := zap.NewDevelopment()
+	serv := service.New(nil, segmentRepo, jobRepo, nil, nil, nil, nil, storage, scheduler, &config.Config{}, zp)
+
+	job, err := serv.SegmentImport(context.Background(), "TEST_SLUG", strings.NewReader("80b0b88d-379e-11ee-8bf7-0242c0a80002\n"))
+	a.NoError(err)
+
+	// Queued import is kept alive until it is run.
+	jobRepo.EXPECT().HeartbeatImports(context.Background(), []string{job.ID}, gomock.Any()).Return(nil)
+	jobRepo.EXPECT().ClaimStaleImports(context.Background(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	jobRepo.EXPECT().ClaimStaleReportJobs(context.Background(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	serv.KeepJobs(context.Background())
+}
@@ -6,9 +6,12 @@ package service_test
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/dupreehkuda/avito-segments/internal/models"
+	worker "github.com/dupreehkuda/avito-segments/internal/worker"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -36,61 +39,104 @@ func (m *MockUserRepository) EXPECT() *MockUserRepositoryMockRecorder {
 }
 
 // DeleteSegments mocks base method.
-func (m *MockUserRepository) DeleteSegments(ctx context.Context, segments *models.UserDeleteRequest) error {
+func (m *MockUserRepository) DeleteSegments(ctx context.Context, segments *models.UserDeleteRequest, audit *models.AuditEntry) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSegments", ctx, segments)
+	ret := m.ctrl.Call(m, "DeleteSegments", ctx, segments, audit)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSegments indicates an expected call of DeleteSegments.
-func (mr *MockUserRepositoryMockRecorder) DeleteSegments(ctx, segments interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) DeleteSegments(ctx, segments, audit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSegments", reflect.TypeOf((*MockUserRepository)(nil).DeleteSegments), ctx, segments)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSegments", reflect.TypeOf((*MockUserRepository)(nil).DeleteSegments), ctx, segments, audit)
 }
 
-// GetReportData mocks base method.
-func (m *MockUserRepository) GetReportData(ctx context.Context, year, month int) ([]models.ReportRow, error) {
+// GetSegments mocks base method.
+func (m *MockUserRepository) GetSegments(ctx context.Context, userID string) (*models.UserResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReportData", ctx, year, month)
-	ret0, _ := ret[0].([]models.ReportRow)
+	ret := m.ctrl.Call(m, "GetSegments", ctx, userID)
+	ret0, _ := ret[0].(*models.UserResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetReportData indicates an expected call of GetReportData.
-func (mr *MockUserRepositoryMockRecorder) GetReportData(ctx, year, month interface{}) *gomock.Call {
+// GetSegments indicates an expected call of GetSegments.
+func (mr *MockUserRepositoryMockRecorder) GetSegments(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportData", reflect.TypeOf((*MockUserRepository)(nil).GetReportData), ctx, year, month)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegments", reflect.TypeOf((*MockUserRepository)(nil).GetSegments), ctx, userID)
 }
 
-// GetSegments mocks base method.
-func (m *MockUserRepository) GetSegments(ctx context.Context, userID string) (*models.UserResponse, error) {
+// GetSegmentsAt mocks base method.
+func (m *MockUserRepository) GetSegmentsAt(ctx context.Context, userID string, at time.Time) (*models.UserResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSegments", ctx, userID)
+	ret := m.ctrl.Call(m, "GetSegmentsAt", ctx, userID, at)
 	ret0, _ := ret[0].(*models.UserResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSegments indicates an expected call of GetSegments.
-func (mr *MockUserRepositoryMockRecorder) GetSegments(ctx, userID interface{}) *gomock.Call {
+// GetSegmentsAt indicates an expected call of GetSegmentsAt.
+func (mr *MockUserRepositoryMockRecorder) GetSegmentsAt(ctx, userID, at interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegments", reflect.TypeOf((*MockUserRepository)(nil).GetSegments), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegmentsAt", reflect.TypeOf((*MockUserRepository)(nil).GetSegmentsAt), ctx, userID, at)
+}
+
+// ReportRows mocks base method.
+func (m *MockUserRepository) ReportRows(ctx context.Context, filter *models.ReportFilter) (models.ReportCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportRows", ctx, filter)
+	ret0, _ := ret[0].(models.ReportCursor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportRows indicates an expected call of ReportRows.
+func (mr *MockUserRepositoryMockRecorder) ReportRows(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportRows", reflect.TypeOf((*MockUserRepository)(nil).ReportRows), ctx, filter)
 }
 
 // SetSegments mocks base method.
-func (m *MockUserRepository) SetSegments(ctx context.Context, segments *models.UserSetRequest) error {
+func (m *MockUserRepository) SetSegments(ctx context.Context, segments *models.UserSetRequest, audit *models.AuditEntry) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetSegments", ctx, segments)
+	ret := m.ctrl.Call(m, "SetSegments", ctx, segments, audit)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetSegments indicates an expected call of SetSegments.
-func (mr *MockUserRepositoryMockRecorder) SetSegments(ctx, segments interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) SetSegments(ctx, segments, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSegments", reflect.TypeOf((*MockUserRepository)(nil).SetSegments), ctx, segments, audit)
+}
+
+// SetSegmentsBulk mocks base method.
+func (m *MockUserRepository) SetSegmentsBulk(ctx context.Context, reqs []models.UserSetRequest, audit []models.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSegmentsBulk", ctx, reqs, audit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSegmentsBulk indicates an expected call of SetSegmentsBulk.
+func (mr *MockUserRepositoryMockRecorder) SetSegmentsBulk(ctx, reqs, audit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSegments", reflect.TypeOf((*MockUserRepository)(nil).SetSegments), ctx, segments)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSegmentsBulk", reflect.TypeOf((*MockUserRepository)(nil).SetSegmentsBulk), ctx, reqs, audit)
+}
+
+// UpdateSegments mocks base method.
+func (m *MockUserRepository) UpdateSegments(ctx context.Context, req *models.UserUpdateRequest, audit *models.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSegments", ctx, req, audit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSegments indicates an expected call of UpdateSegments.
+func (mr *MockUserRepositoryMockRecorder) UpdateSegments(ctx, req, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSegments", reflect.TypeOf((*MockUserRepository)(nil).UpdateSegments), ctx, req, audit)
 }
 
 // MockSegmentRepository is a mock of SegmentRepository interface.
@@ -117,46 +163,61 @@ func (m *MockSegmentRepository) EXPECT() *MockSegmentRepositoryMockRecorder {
 }
 
 // Add mocks base method.
-func (m *MockSegmentRepository) Add(ctx context.Context, segment *models.Segment) error {
+func (m *MockSegmentRepository) Add(ctx context.Context, segment *models.Segment, audit *models.AuditEntry) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Add", ctx, segment)
+	ret := m.ctrl.Call(m, "Add", ctx, segment, audit)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Add indicates an expected call of Add.
-func (mr *MockSegmentRepositoryMockRecorder) Add(ctx, segment interface{}) *gomock.Call {
+func (mr *MockSegmentRepositoryMockRecorder) Add(ctx, segment, audit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockSegmentRepository)(nil).Add), ctx, segment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockSegmentRepository)(nil).Add), ctx, segment, audit)
 }
 
-// Count mocks base method.
-func (m *MockSegmentRepository) Count(ctx context.Context, slugs []string) (int, error) {
+// DailyStats mocks base method.
+func (m *MockSegmentRepository) DailyStats(ctx context.Context, slug string, from, to time.Time) ([]models.SegmentDayStats, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Count", ctx, slugs)
-	ret0, _ := ret[0].(int)
+	ret := m.ctrl.Call(m, "DailyStats", ctx, slug, from, to)
+	ret0, _ := ret[0].([]models.SegmentDayStats)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Count indicates an expected call of Count.
-func (mr *MockSegmentRepositoryMockRecorder) Count(ctx, slugs interface{}) *gomock.Call {
+// DailyStats indicates an expected call of DailyStats.
+func (mr *MockSegmentRepositoryMockRecorder) DailyStats(ctx, slug, from, to interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockSegmentRepository)(nil).Count), ctx, slugs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DailyStats", reflect.TypeOf((*MockSegmentRepository)(nil).DailyStats), ctx, slug, from, to)
 }
 
 // Delete mocks base method.
-func (m *MockSegmentRepository) Delete(ctx context.Context, slug string) error {
+func (m *MockSegmentRepository) Delete(ctx context.Context, slug string, audit *models.AuditEntry) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, slug)
+	ret := m.ctrl.Call(m, "Delete", ctx, slug, audit)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockSegmentRepositoryMockRecorder) Delete(ctx, slug interface{}) *gomock.Call {
+func (mr *MockSegmentRepositoryMockRecorder) Delete(ctx, slug, audit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSegmentRepository)(nil).Delete), ctx, slug)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSegmentRepository)(nil).Delete), ctx, slug, audit)
+}
+
+// Existing mocks base method.
+func (m *MockSegmentRepository) Existing(ctx context.Context, slugs []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Existing", ctx, slugs)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Existing indicates an expected call of Existing.
+func (mr *MockSegmentRepositoryMockRecorder) Existing(ctx, slugs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Existing", reflect.TypeOf((*MockSegmentRepository)(nil).Existing), ctx, slugs)
 }
 
 // Get mocks base method.
@@ -173,3 +234,778 @@ func (mr *MockSegmentRepositoryMockRecorder) Get(ctx, slug interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSegmentRepository)(nil).Get), ctx, slug)
 }
+
+// Info mocks base method.
+func (m *MockSegmentRepository) Info(ctx context.Context, slug string) (*models.SegmentInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Info", ctx, slug)
+	ret0, _ := ret[0].(*models.SegmentInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockSegmentRepositoryMockRecorder) Info(ctx, slug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockSegmentRepository)(nil).Info), ctx, slug)
+}
+
+// List mocks base method.
+func (m *MockSegmentRepository) List(ctx context.Context, filter *models.SegmentFilter) ([]models.SegmentInfo, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].([]models.SegmentInfo)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockSegmentRepositoryMockRecorder) List(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSegmentRepository)(nil).List), ctx, filter)
+}
+
+// Members mocks base method.
+func (m *MockSegmentRepository) Members(ctx context.Context, slug, after string, limit int) ([]models.SegmentMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Members", ctx, slug, after, limit)
+	ret0, _ := ret[0].([]models.SegmentMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Members indicates an expected call of Members.
+func (mr *MockSegmentRepositoryMockRecorder) Members(ctx, slug, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Members", reflect.TypeOf((*MockSegmentRepository)(nil).Members), ctx, slug, after, limit)
+}
+
+// Ranking mocks base method.
+func (m *MockSegmentRepository) Ranking(ctx context.Context, limit, offset int) ([]models.SegmentInfo, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ranking", ctx, limit, offset)
+	ret0, _ := ret[0].([]models.SegmentInfo)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Ranking indicates an expected call of Ranking.
+func (mr *MockSegmentRepositoryMockRecorder) Ranking(ctx, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ranking", reflect.TypeOf((*MockSegmentRepository)(nil).Ranking), ctx, limit, offset)
+}
+
+// Restore mocks base method.
+func (m *MockSegmentRepository) Restore(ctx context.Context, slug string, restoreMembers bool, audit *models.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, slug, restoreMembers, audit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockSegmentRepositoryMockRecorder) Restore(ctx, slug, restoreMembers, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockSegmentRepository)(nil).Restore), ctx, slug, restoreMembers, audit)
+}
+
+// StreamMembers mocks base method.
+func (m *MockSegmentRepository) StreamMembers(ctx context.Context, slug string, fn func(models.SegmentMember) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamMembers", ctx, slug, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamMembers indicates an expected call of StreamMembers.
+func (mr *MockSegmentRepositoryMockRecorder) StreamMembers(ctx, slug, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamMembers", reflect.TypeOf((*MockSegmentRepository)(nil).StreamMembers), ctx, slug, fn)
+}
+
+// Update mocks base method.
+func (m *MockSegmentRepository) Update(ctx context.Context, slug string, req *models.SegmentUpdateRequest, audit *models.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, slug, req, audit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSegmentRepositoryMockRecorder) Update(ctx, slug, req, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSegmentRepository)(nil).Update), ctx, slug, req, audit)
+}
+
+// MockJobRepository is a mock of JobRepository interface.
+type MockJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRepositoryMockRecorder
+}
+
+// MockJobRepositoryMockRecorder is the mock recorder for MockJobRepository.
+type MockJobRepositoryMockRecorder struct {
+	mock *MockJobRepository
+}
+
+// NewMockJobRepository creates a new mock instance.
+func NewMockJobRepository(ctrl *gomock.Controller) *MockJobRepository {
+	mock := &MockJobRepository{ctrl: ctrl}
+	mock.recorder = &MockJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobRepository) EXPECT() *MockJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimStaleImports mocks base method.
+func (m *MockJobRepository) ClaimStaleImports(ctx context.Context, staleBefore, now time.Time) ([]models.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimStaleImports", ctx, staleBefore, now)
+	ret0, _ := ret[0].([]models.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimStaleImports indicates an expected call of ClaimStaleImports.
+func (mr *MockJobRepositoryMockRecorder) ClaimStaleImports(ctx, staleBefore, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimStaleImports", reflect.TypeOf((*MockJobRepository)(nil).ClaimStaleImports), ctx, staleBefore, now)
+}
+
+// ClaimStaleReportJobs mocks base method.
+func (m *MockJobRepository) ClaimStaleReportJobs(ctx context.Context, staleBefore, now time.Time) ([]models.ReportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimStaleReportJobs", ctx, staleBefore, now)
+	ret0, _ := ret[0].([]models.ReportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimStaleReportJobs indicates an expected call of ClaimStaleReportJobs.
+func (mr *MockJobRepositoryMockRecorder) ClaimStaleReportJobs(ctx, staleBefore, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimStaleReportJobs", reflect.TypeOf((*MockJobRepository)(nil).ClaimStaleReportJobs), ctx, staleBefore, now)
+}
+
+// CreateImport mocks base method.
+func (m *MockJobRepository) CreateImport(ctx context.Context, job *models.ImportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImport", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateImport indicates an expected call of CreateImport.
+func (mr *MockJobRepositoryMockRecorder) CreateImport(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImport", reflect.TypeOf((*MockJobRepository)(nil).CreateImport), ctx, job)
+}
+
+// CreateReportJob mocks base method.
+func (m *MockJobRepository) CreateReportJob(ctx context.Context, job *models.ReportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReportJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReportJob indicates an expected call of CreateReportJob.
+func (mr *MockJobRepositoryMockRecorder) CreateReportJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReportJob", reflect.TypeOf((*MockJobRepository)(nil).CreateReportJob), ctx, job)
+}
+
+// FinishImport mocks base method.
+func (m *MockJobRepository) FinishImport(ctx context.Context, id, status, message string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishImport", ctx, id, status, message, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishImport indicates an expected call of FinishImport.
+func (mr *MockJobRepositoryMockRecorder) FinishImport(ctx, id, status, message, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishImport", reflect.TypeOf((*MockJobRepository)(nil).FinishImport), ctx, id, status, message, now)
+}
+
+// FinishReportJob mocks base method.
+func (m *MockJobRepository) FinishReportJob(ctx context.Context, job *models.ReportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishReportJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishReportJob indicates an expected call of FinishReportJob.
+func (mr *MockJobRepositoryMockRecorder) FinishReportJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishReportJob", reflect.TypeOf((*MockJobRepository)(nil).FinishReportJob), ctx, job)
+}
+
+// GetImport mocks base method.
+func (m *MockJobRepository) GetImport(ctx context.Context, id string, limit, offset int) (*models.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImport", ctx, id, limit, offset)
+	ret0, _ := ret[0].(*models.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImport indicates an expected call of GetImport.
+func (mr *MockJobRepositoryMockRecorder) GetImport(ctx, id, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImport", reflect.TypeOf((*MockJobRepository)(nil).GetImport), ctx, id, limit, offset)
+}
+
+// GetReportJob mocks base method.
+func (m *MockJobRepository) GetReportJob(ctx context.Context, id string) (*models.ReportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportJob", ctx, id)
+	ret0, _ := ret[0].(*models.ReportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportJob indicates an expected call of GetReportJob.
+func (mr *MockJobRepositoryMockRecorder) GetReportJob(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportJob", reflect.TypeOf((*MockJobRepository)(nil).GetReportJob), ctx, id)
+}
+
+// HeartbeatImports mocks base method.
+func (m *MockJobRepository) HeartbeatImports(ctx context.Context, ids []string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeartbeatImports", ctx, ids, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HeartbeatImports indicates an expected call of HeartbeatImports.
+func (mr *MockJobRepositoryMockRecorder) HeartbeatImports(ctx, ids, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeartbeatImports", reflect.TypeOf((*MockJobRepository)(nil).HeartbeatImports), ctx, ids, now)
+}
+
+// HeartbeatReportJobs mocks base method.
+func (m *MockJobRepository) HeartbeatReportJobs(ctx context.Context, ids []string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeartbeatReportJobs", ctx, ids, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HeartbeatReportJobs indicates an expected call of HeartbeatReportJobs.
+func (mr *MockJobRepositoryMockRecorder) HeartbeatReportJobs(ctx, ids, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeartbeatReportJobs", reflect.TypeOf((*MockJobRepository)(nil).HeartbeatReportJobs), ctx, ids, now)
+}
+
+// LatestReportJob mocks base method.
+func (m *MockJobRepository) LatestReportJob(ctx context.Context, reportID, format string) (*models.ReportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestReportJob", ctx, reportID, format)
+	ret0, _ := ret[0].(*models.ReportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestReportJob indicates an expected call of LatestReportJob.
+func (mr *MockJobRepositoryMockRecorder) LatestReportJob(ctx, reportID, format interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestReportJob", reflect.TypeOf((*MockJobRepository)(nil).LatestReportJob), ctx, reportID, format)
+}
+
+// PendingReportJob mocks base method.
+func (m *MockJobRepository) PendingReportJob(ctx context.Context, reportID, format string, staleBefore time.Time) (*models.ReportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingReportJob", ctx, reportID, format, staleBefore)
+	ret0, _ := ret[0].(*models.ReportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PendingReportJob indicates an expected call of PendingReportJob.
+func (mr *MockJobRepositoryMockRecorder) PendingReportJob(ctx, reportID, format, staleBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingReportJob", reflect.TypeOf((*MockJobRepository)(nil).PendingReportJob), ctx, reportID, format, staleBefore)
+}
+
+// ProgressImport mocks base method.
+func (m *MockJobRepository) ProgressImport(ctx context.Context, id string, processed int, rowErrors []models.ImportRowError) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProgressImport", ctx, id, processed, rowErrors)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProgressImport indicates an expected call of ProgressImport.
+func (mr *MockJobRepositoryMockRecorder) ProgressImport(ctx, id, processed, rowErrors interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProgressImport", reflect.TypeOf((*MockJobRepository)(nil).ProgressImport), ctx, id, processed, rowErrors)
+}
+
+// ProgressReportJob mocks base method.
+func (m *MockJobRepository) ProgressReportJob(ctx context.Context, id string, rows int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProgressReportJob", ctx, id, rows)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProgressReportJob indicates an expected call of ProgressReportJob.
+func (mr *MockJobRepositoryMockRecorder) ProgressReportJob(ctx, id, rows interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProgressReportJob", reflect.TypeOf((*MockJobRepository)(nil).ProgressReportJob), ctx, id, rows)
+}
+
+// StartImport mocks base method.
+func (m *MockJobRepository) StartImport(ctx context.Context, id string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartImport", ctx, id, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartImport indicates an expected call of StartImport.
+func (mr *MockJobRepositoryMockRecorder) StartImport(ctx, id, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartImport", reflect.TypeOf((*MockJobRepository)(nil).StartImport), ctx, id, now)
+}
+
+// StartReportJob mocks base method.
+func (m *MockJobRepository) StartReportJob(ctx context.Context, id string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartReportJob", ctx, id, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartReportJob indicates an expected call of StartReportJob.
+func (mr *MockJobRepositoryMockRecorder) StartReportJob(ctx, id, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartReportJob", reflect.TypeOf((*MockJobRepository)(nil).StartReportJob), ctx, id, now)
+}
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookRepository) CreateWebhook(ctx context.Context, hook *models.Webhook, audit *models.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, hook, audit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) CreateWebhook(ctx, hook, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).CreateWebhook), ctx, hook, audit)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookRepository) DeleteWebhook(ctx context.Context, id string, audit *models.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id, audit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteWebhook(ctx, id, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteWebhook), ctx, id, audit)
+}
+
+// Deliveries mocks base method.
+func (m *MockWebhookRepository) Deliveries(ctx context.Context, webhookID string, filter *models.DeliveryFilter) ([]models.WebhookDelivery, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deliveries", ctx, webhookID, filter)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Deliveries indicates an expected call of Deliveries.
+func (mr *MockWebhookRepositoryMockRecorder) Deliveries(ctx, webhookID, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deliveries", reflect.TypeOf((*MockWebhookRepository)(nil).Deliveries), ctx, webhookID, filter)
+}
+
+// Delivery mocks base method.
+func (m *MockWebhookRepository) Delivery(ctx context.Context, webhookID string, id int64) (*models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delivery", ctx, webhookID, id)
+	ret0, _ := ret[0].(*models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delivery indicates an expected call of Delivery.
+func (mr *MockWebhookRepositoryMockRecorder) Delivery(ctx, webhookID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delivery", reflect.TypeOf((*MockWebhookRepository)(nil).Delivery), ctx, webhookID, id)
+}
+
+// GetWebhook mocks base method.
+func (m *MockWebhookRepository) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) GetWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).GetWebhook), ctx, id)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookRepository) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookRepositoryMockRecorder) ListWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookRepository)(nil).ListWebhooks), ctx)
+}
+
+// RetryDelivery mocks base method.
+func (m *MockWebhookRepository) RetryDelivery(ctx context.Context, id int64, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryDelivery", ctx, id, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryDelivery indicates an expected call of RetryDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) RetryDelivery(ctx, id, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).RetryDelivery), ctx, id, now)
+}
+
+// MockEventRepository is a mock of EventRepository interface.
+type MockEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventRepositoryMockRecorder
+}
+
+// MockEventRepositoryMockRecorder is the mock recorder for MockEventRepository.
+type MockEventRepositoryMockRecorder struct {
+	mock *MockEventRepository
+}
+
+// NewMockEventRepository creates a new mock instance.
+func NewMockEventRepository(ctrl *gomock.Controller) *MockEventRepository {
+	mock := &MockEventRepository{ctrl: ctrl}
+	mock.recorder = &MockEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventRepository) EXPECT() *MockEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Events mocks base method.
+func (m *MockEventRepository) Events(ctx context.Context, filter *models.EventFilter) ([]models.StreamEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events", ctx, filter)
+	ret0, _ := ret[0].([]models.StreamEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Events indicates an expected call of Events.
+func (mr *MockEventRepositoryMockRecorder) Events(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockEventRepository)(nil).Events), ctx, filter)
+}
+
+// EventsHead mocks base method.
+func (m *MockEventRepository) EventsHead(ctx context.Context) (models.EventPosition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EventsHead", ctx)
+	ret0, _ := ret[0].(models.EventPosition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EventsHead indicates an expected call of EventsHead.
+func (mr *MockEventRepositoryMockRecorder) EventsHead(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EventsHead", reflect.TypeOf((*MockEventRepository)(nil).EventsHead), ctx)
+}
+
+// MockAPIKeyRepository is a mock of APIKeyRepository interface.
+type MockAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyRepositoryMockRecorder
+}
+
+// MockAPIKeyRepositoryMockRecorder is the mock recorder for MockAPIKeyRepository.
+type MockAPIKeyRepositoryMockRecorder struct {
+	mock *MockAPIKeyRepository
+}
+
+// NewMockAPIKeyRepository creates a new mock instance.
+func NewMockAPIKeyRepository(ctrl *gomock.Controller) *MockAPIKeyRepository {
+	mock := &MockAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyRepository) EXPECT() *MockAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAPIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey, audit *models.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key, audit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) CreateAPIKey(ctx, key, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).CreateAPIKey), ctx, key, audit)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockAPIKeyRepository) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockAPIKeyRepositoryMockRecorder) ListAPIKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockAPIKeyRepository)(nil).ListAPIKeys), ctx)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, now time.Time, audit *models.AuditEntry) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id, now, audit)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAPIKeyRepositoryMockRecorder) RevokeAPIKey(ctx, id, now, audit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAPIKeyRepository)(nil).RevokeAPIKey), ctx, id, now, audit)
+}
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AuditLog mocks base method.
+func (m *MockAuditRepository) AuditLog(ctx context.Context, filter *models.AuditFilter) ([]models.AuditEntry, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuditLog", ctx, filter)
+	ret0, _ := ret[0].([]models.AuditEntry)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AuditLog indicates an expected call of AuditLog.
+func (mr *MockAuditRepositoryMockRecorder) AuditLog(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuditLog", reflect.TypeOf((*MockAuditRepository)(nil).AuditLog), ctx, filter)
+}
+
+// MockReportStorage is a mock of ReportStorage interface.
+type MockReportStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportStorageMockRecorder
+}
+
+// MockReportStorageMockRecorder is the mock recorder for MockReportStorage.
+type MockReportStorageMockRecorder struct {
+	mock *MockReportStorage
+}
+
+// NewMockReportStorage creates a new mock instance.
+func NewMockReportStorage(ctrl *gomock.Controller) *MockReportStorage {
+	mock := &MockReportStorage{ctrl: ctrl}
+	mock.recorder = &MockReportStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportStorage) EXPECT() *MockReportStorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockReportStorage) Delete(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockReportStorageMockRecorder) Delete(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReportStorage)(nil).Delete), ctx, name)
+}
+
+// Exists mocks base method.
+func (m *MockReportStorage) Exists(ctx context.Context, name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockReportStorageMockRecorder) Exists(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockReportStorage)(nil).Exists), ctx, name)
+}
+
+// Open mocks base method.
+func (m *MockReportStorage) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, name)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockReportStorageMockRecorder) Open(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockReportStorage)(nil).Open), ctx, name)
+}
+
+// Put mocks base method.
+func (m *MockReportStorage) Put(ctx context.Context, name string, fn func(io.Writer) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, name, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockReportStorageMockRecorder) Put(ctx, name, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockReportStorage)(nil).Put), ctx, name, fn)
+}
+
+// URL mocks base method.
+func (m *MockReportStorage) URL(ctx context.Context, name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "URL", ctx, name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// URL indicates an expected call of URL.
+func (mr *MockReportStorageMockRecorder) URL(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "URL", reflect.TypeOf((*MockReportStorage)(nil).URL), ctx, name)
+}
+
+// MockScheduler is a mock of Scheduler interface.
+type MockScheduler struct {
+	ctrl     *gomock.Controller
+	recorder *MockSchedulerMockRecorder
+}
+
+// MockSchedulerMockRecorder is the mock recorder for MockScheduler.
+type MockSchedulerMockRecorder struct {
+	mock *MockScheduler
+}
+
+// NewMockScheduler creates a new mock instance.
+func NewMockScheduler(ctrl *gomock.Controller) *MockScheduler {
+	mock := &MockScheduler{ctrl: ctrl}
+	mock.recorder = &MockSchedulerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduler) EXPECT() *MockSchedulerMockRecorder {
+	return m.recorder
+}
+
+// Submit mocks base method.
+func (m *MockScheduler) Submit(task worker.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockSchedulerMockRecorder) Submit(task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockScheduler)(nil).Submit), task)
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/report"
+)
+
+var (
+	// reportName matches names of generated reports.
+	reportName = regexp.MustCompile(`^[A-Za-z0-9_-]+\.[a-z]+$`)
+	// reportIDPattern matches ids of reports, which address the last report generated for the filter.
+	reportIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+)
+
+// CreateReport queues generation of report matching the filter in the format and returns its job.
+// Report covering a period that is over and settled never changes, so once generated it is always reused.
+// Report for a period that is not over yet is reused until it gets stale or regeneration is forced.
+func (s *Service) CreateReport(ctx context.Context, filter *models.ReportFilter, format string, force bool) (*models.ReportJob, error) {
+	format, err := prepareReport(filter, format)
+	if err != nil {
+		return nil, err
+	}
+
+	id := reportID(filter)
+
+	latest, err := s.jobRepo.LatestReportJob(ctx, id, format)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if latest != nil && isFreshReport(latest, force, now, s.reportSettle) {
+		exists, err := s.storage.Exists(ctx, latest.File)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			return latest, nil
+		}
+	}
+
+	pending, err := s.jobRepo.PendingReportJob(ctx, id, format, now.Add(-JobLease))
+	if err != nil {
+		return nil, err
+	}
+
+	if pending != nil {
+		return pending, nil
+	}
+
+	job := &models.ReportJob{
+		ID:           uuid.NewString(),
+		ReportID:     id,
+		ReportFilter: *filter,
+		Format:       format,
+		Status:       models.JobQueued,
+		CreatedAt:    now,
+	}
+
+	if err = s.jobRepo.CreateReportJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.reports.add(job.ID)
+
+	err = s.scheduler.Submit(func(ctx context.Context) {
+		s.runReport(ctx, *job)
+	})
+	if err != nil {
+		s.reports.remove(job.ID)
+		job.Status, job.Error = models.JobFailed, err.Error()
+		s.finishReport(job)
+
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// isFreshReport tells whether generated report can be returned instead of a new one.
+func isFreshReport(job *models.ReportJob, force bool, now time.Time, settle time.Duration) bool {
+	if job.GeneratedAt == nil {
+		return false
+	}
+
+	// Report generated once its period was over and settled covers all of it.
+	// Report generated earlier has its range cut at generation time.
+	if job.To.Before(*job.GeneratedAt) && !job.GeneratedAt.Before(job.To.Add(settle)) {
+		return true
+	}
+
+	return !force && now.Sub(*job.GeneratedAt) < ReportStaleAfter
+}
+
+// normalizeReportFilter resolves month into range and orders filter values,
+// so equal filters always produce the same report.
+func normalizeReportFilter(filter *models.ReportFilter) {
+	if filter.Year != 0 {
+		filter.From = time.Date(filter.Year, time.Month(filter.Month), 1, 0, 0, 0, 0, time.UTC)
+		filter.To = filter.From.AddDate(0, 1, 0)
+	}
+
+	filter.From, filter.To = filter.From.UTC(), filter.To.UTC()
+	filter.Slugs = uniqueSorted(filter.Slugs)
+	filter.UserIDs = uniqueSorted(filter.UserIDs)
+	filter.Methods = uniqueSorted(filter.Methods)
+
+	// Filter by every reported method is the same as no filter.
+	if len(filter.Methods) == 3 {
+		filter.Methods = nil
+	}
+}
+
+// reportID addresses report by its normalized filter.
+func reportID(filter *models.ReportFilter) string {
+	hash := sha256.New()
+
+	fmt.Fprintf(hash, "%s\n%s\n%s\n%s\n%s",
+		filter.From.Format(time.RFC3339Nano),
+		filter.To.Format(time.RFC3339Nano),
+		strings.Join(filter.Slugs, ","),
+		strings.Join(filter.UserIDs, ","),
+		strings.Join(filter.Methods, ","),
+	)
+
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+func uniqueSorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+
+	unique := sorted[:1]
+	for _, value := range sorted[1:] {
+		if value != unique[len(unique)-1] {
+			unique = append(unique, value)
+		}
+	}
+
+	return unique
+}
+
+// StreamReport writes report matching the filter in the format straight to w without storing it.
+// Nothing is written to w if the report can not be started.
+func (s *Service) StreamReport(ctx context.Context, filter *models.ReportFilter, format string, w io.Writer) error {
+	format, err := prepareReport(filter, format)
+	if err != nil {
+		return err
+	}
+
+	if now := time.Now(); now.Before(filter.To) {
+		filter.To = now
+	}
+
+	_, err = s.writeReport(ctx, w, format, filter, nil)
+
+	return err
+}
+
+// prepareReport validates and normalizes report request and returns its format, CSV by default.
+func prepareReport(filter *models.ReportFilter, format string) (string, error) {
+	if err := IsValidReportFilter(filter); err != nil {
+		return "", err
+	}
+
+	if format == "" {
+		format = models.FormatCSV
+	}
+
+	if !report.IsValidFormat(format) {
+		return "", errors.ErrInvalidFormat
+	}
+
+	normalizeReportFilter(filter)
+
+	return format, nil
+}
+
+// ReportJob returns state of report job.
+func (s *Service) ReportJob(ctx context.Context, id string) (*models.ReportJob, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.ErrReportJobNotFound
+	}
+
+	job, err := s.jobRepo.GetReportJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job == nil {
+		return nil, errors.ErrReportJobNotFound
+	}
+
+	return job, nil
+}
+
+// runReport generates report file and stores job outcome.
+// Report stopped along with the application is left unfinished, so it is run again once its lease expires.
+func (s *Service) runReport(ctx context.Context, job models.ReportJob) {
+	defer s.reports.remove(job.ID)
+
+	if err := s.jobRepo.StartReportJob(ctx, job.ID, time.Now()); err != nil {
+		s.logger.Error("Unable to start report", zap.String("job", job.ID), zap.Error(err))
+	}
+
+	// Data is read up to generation time, so report for a period that is not over covers only its elapsed part.
+	generatedAt := time.Now()
+
+	filter := job.ReportFilter
+	if generatedAt.Before(filter.To) {
+		filter.To = generatedAt
+	}
+
+	// File is named by the job, so regenerated report never overwrites file of an earlier job.
+	var (
+		name = reportFileName(job.ID, job.Format)
+		rows int
+	)
+
+	progress := func(rows int) {
+		if err := s.jobRepo.ProgressReportJob(ctx, job.ID, rows); err != nil {
+			s.logger.Error("Unable to store report progress", zap.String("job", job.ID), zap.Error(err))
+		}
+	}
+
+	err := s.storage.Put(ctx, name, func(w io.Writer) error {
+		var err error
+
+		rows, err = s.writeReport(ctx, w, job.Format, &filter, progress)
+		if err == nil && rows == 0 {
+			// Failing the write keeps empty report out of storage.
+			return errors.ErrDataNotFound
+		}
+
+		return err
+	})
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		s.logger.Warn("Report interrupted", zap.String("job", job.ID))
+		return
+	case stdErrors.Is(err, errors.ErrDataNotFound):
+		job.Status, job.Error = models.JobFailed, "no data for report"
+	case err != nil:
+		s.logger.Error("Report failed", zap.String("job", job.ID), zap.Error(err))
+		job.Status, job.Error = models.JobFailed, err.Error()
+	default:
+		job.Status, job.Rows, job.File = models.JobDone, rows, name
+		job.To, job.GeneratedAt = filter.To, &generatedAt
+	}
+
+	s.finishReport(&job)
+}
+
+// finishReport stores final job status even if the application is already stopping.
+func (s *Service) finishReport(job *models.ReportJob) {
+	now := time.Now()
+	job.FinishedAt = &now
+
+	if err := s.jobRepo.FinishReportJob(context.Background(), job); err != nil {
+		s.logger.Error("Unable to finish report", zap.String("job", job.ID), zap.Error(err))
+	}
+}
+
+// writeReport streams rows matching the filter to w in the format and returns amount of written rows.
+// Rows are not kept in memory. Progress, if set, is called every ReportProgressEvery rows.
+func (s *Service) writeReport(ctx context.Context, w io.Writer, format string, filter *models.ReportFilter, progress func(rows int)) (int, error) {
+	cursor, err := s.userRepo.ReportRows(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close()
+
+	writer, err := report.NewWriter(format, w)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := 0
+
+	for cursor.Next() {
+		if err = writer.Write(cursor.Row()); err != nil {
+			return rows, fmt.Errorf("error writing report: %w", err)
+		}
+
+		if rows++; progress != nil && rows%ReportProgressEvery == 0 {
+			progress(rows)
+		}
+	}
+
+	if err = cursor.Err(); err != nil {
+		return rows, err
+	}
+
+	if err = writer.Close(); err != nil {
+		return rows, fmt.Errorf("error writing report: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ReportFile returns stored report either as a direct link or as its contents.
+// Report named by its report id is the last one generated in the format.
+func (s *Service) ReportFile(ctx context.Context, name string) (*models.ReportFile, error) {
+	if !IsValidReportName(name) {
+		return nil, errors.ErrReportNotFound
+	}
+
+	file := name
+
+	if ext := path.Ext(name); reportIDPattern.MatchString(strings.TrimSuffix(name, ext)) {
+		latest, err := s.jobRepo.LatestReportJob(ctx, strings.TrimSuffix(name, ext), strings.TrimPrefix(ext, "."))
+		if err != nil {
+			return nil, err
+		}
+
+		if latest == nil {
+			return nil, errors.ErrReportNotFound
+		}
+
+		file = latest.File
+	}
+
+	link, err := s.storage.URL(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	if link != "" {
+		exists, err := s.storage.Exists(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			return nil, errors.ErrReportNotFound
+		}
+
+		return &models.ReportFile{Name: name, URL: link}, nil
+	}
+
+	content, err := s.storage.Open(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ReportFile{Name: name, Content: content}, nil
+}
+
+// IsValidReportFilter checks report period and filter values.
+// Period is either a month or a [From, To) range which has already started.
+func IsValidReportFilter(filter *models.ReportFilter) error {
+	if filter.Year != 0 || filter.Month != 0 {
+		if !filter.From.IsZero() || !filter.To.IsZero() || !IsValidReportTime(filter.Year, filter.Month) {
+			return errors.ErrInvalidPeriod
+		}
+	} else if filter.From.IsZero() || !filter.From.Before(filter.To) || filter.From.After(time.Now()) {
+		return errors.ErrInvalidPeriod
+	}
+
+	if len(filter.Slugs)+len(filter.UserIDs) > MaxBulkSize {
+		return errors.ErrBatchTooLarge
+	}
+
+	for _, slug := range filter.Slugs {
+		if !IsValidSlug(slug) {
+			return errors.ErrInvalidSegmentSlug
+		}
+	}
+
+	for _, userID := range filter.UserIDs {
+		if _, err := uuid.Parse(userID); err != nil {
+			return errors.ErrInvalidUserID
+		}
+	}
+
+	for _, method := range filter.Methods {
+		switch method {
+		case models.MethodAdded, models.MethodDeleted, models.MethodExpired:
+		default:
+			return errors.ErrInvalidFilter
+		}
+	}
+
+	return nil
+}
+
+// IsValidReportName checks that report name can not point outside of report storage.
+func IsValidReportName(name string) bool {
+	return reportName.MatchString(name)
+}
+
+func reportFileName(id, format string) string {
+	return id + "." + format
+}
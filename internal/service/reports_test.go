@@ -0,0 +1,850 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/service"
+	"github.com/dupreehkuda/avito-segments/internal/worker"
+)
+
+func TestService_CreateReport(t *testing.T) {
+	a := assert.New(t)
+
+	var (
+		now        = time.Now().UTC()
+		monthStart = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		nextMonth  = monthStart.AddDate(0, 1, 0)
+		closedAt   = time.Date(2023, time.September, 2, 0, 0, 0, 0, time.UTC)
+		unsettled  = time.Date(2023, time.September, 1, 0, 0, 10, 0, time.UTC)
+		freshAt    = now.Add(-time.Minute)
+		staleAt    = now.Add(-service.ReportStaleAfter - time.Minute)
+
+		closedFilter = models.ReportFilter{
+			From: time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2023, time.September, 1, 0, 0, 0, 0, time.UTC),
+		}
+		openFilter = models.ReportFilter{From: monthStart, To: monthStart.AddDate(0, 1, 0)}
+
+		closedReport = &models.ReportJob{ReportFilter: closedFilter, Status: models.JobDone, File: "closed.csv", GeneratedAt: &closedAt}
+		closedEarly  = &models.ReportJob{ReportFilter: closedFilter, Status: models.JobDone, File: "closed.csv", GeneratedAt: &unsettled}
+		// Report for a period that is not over has its range cut at generation time.
+		openFresh = &models.ReportJob{ReportFilter: models.ReportFilter{From: monthStart, To: freshAt},
+			Status: models.JobDone, File: "open.csv", GeneratedAt: &freshAt}
+		openStale = &models.ReportJob{ReportFilter: models.ReportFilter{From: monthStart, To: staleAt},
+			Status: models.JobDone, File: "open.csv", GeneratedAt: &staleAt}
+	)
+
+	testCases := []struct {
+		name   string
+		filter models.ReportFilter
+		format string
+		force  bool
+
+		latestReturn  *models.ReportJob
+		existsReturn  bool
+		pendingReturn *models.ReportJob
+		createError   error
+		submitError   error
+
+		expectedStatus string
+		expectedError  error
+
+		expectingLatestCall  bool
+		expectingExistsCall  bool
+		expectingPendingCall bool
+		expectingCreateCall  bool
+		expectingSubmitCall  bool
+	}{
+		{
+			name:                 "Report queued",
+			filter:               models.ReportFilter{Year: 2023, Month: 8},
+			expectedStatus:       models.JobQueued,
+			expectingLatestCall:  true,
+			expectingPendingCall: true,
+			expectingCreateCall:  true,
+			expectingSubmitCall:  true,
+		},
+		{
+			name:                "Closed month already generated",
+			filter:              models.ReportFilter{Year: 2023, Month: 8},
+			latestReturn:        closedReport,
+			existsReturn:        true,
+			expectedStatus:      models.JobDone,
+			expectingLatestCall: true,
+			expectingExistsCall: true,
+		},
+		{
+			name:                "Closed month is not regenerated on force",
+			filter:              models.ReportFilter{Year: 2023, Month: 8},
+			force:               true,
+			latestReturn:        closedReport,
+			existsReturn:        true,
+			expectedStatus:      models.JobDone,
+			expectingLatestCall: true,
+			expectingExistsCall: true,
+		},
+		{
+			name:                 "Month generated before its history settled",
+			filter:               models.ReportFilter{Year: 2023, Month: 8},
+			latestReturn:         closedEarly,
+			expectedStatus:       models.JobQueued,
+			expectingLatestCall:  true,
+			expectingPendingCall: true,
+			expectingCreateCall:  true,
+			expectingSubmitCall:  true,
+		},
+		{
+			name:                "Current month report is fresh",
+			filter:              openFilter,
+			latestReturn:        openFresh,
+			existsReturn:        true,
+			expectedStatus:      models.JobDone,
+			expectingLatestCall: true,
+			expectingExistsCall: true,
+		},
+		{
+			name:                 "Current month report is stale",
+			filter:               openFilter,
+			latestReturn:         openStale,
+			expectedStatus:       models.JobQueued,
+			expectingLatestCall:  true,
+			expectingPendingCall: true,
+			expectingCreateCall:  true,
+			expectingSubmitCall:  true,
+		},
+		{
+			name:                 "Current month report regeneration forced",
+			filter:               openFilter,
+			force:                true,
+			latestReturn:         openFresh,
+			expectedStatus:       models.JobQueued,
+			expectingLatestCall:  true,
+			expectingPendingCall: true,
+			expectingCreateCall:  true,
+			expectingSubmitCall:  true,
+		},
+		{
+			name:                 "Report is already being generated",
+			filter:               openFilter,
+			latestReturn:         openStale,
+			pendingReturn:        &models.ReportJob{ReportFilter: openFilter, Status: models.JobRunning},
+			expectedStatus:       models.JobRunning,
+			expectingLatestCall:  true,
+			expectingPendingCall: true,
+		},
+		{
+			name:                 "Previous report file is gone",
+			filter:               models.ReportFilter{Year: 2023, Month: 8},
+			latestReturn:         closedReport,
+			expectedStatus:       models.JobQueued,
+			expectingLatestCall:  true,
+			expectingExistsCall:  true,
+			expectingPendingCall: true,
+			expectingCreateCall:  true,
+			expectingSubmitCall:  true,
+		},
+		{
+			name:          "Invalid period",
+			filter:        models.ReportFilter{Year: 2023, Month: 13},
+			expectedError: errors.ErrInvalidPeriod,
+		},
+		{
+			name:          "Month not started yet",
+			filter:        models.ReportFilter{Year: nextMonth.Year(), Month: int(nextMonth.Month())},
+			expectedError: errors.ErrInvalidPeriod,
+		},
+		{
+			name:          "Empty range",
+			filter:        models.ReportFilter{From: closedFilter.To, To: closedFilter.From},
+			expectedError: errors.ErrInvalidPeriod,
+		},
+		{
+			name:          "Both month and range",
+			filter:        models.ReportFilter{Year: 2023, Month: 8, From: closedFilter.From, To: closedFilter.To},
+			expectedError: errors.ErrInvalidPeriod,
+		},
+		{
+			name:          "Invalid slug filter",
+			filter:        models.ReportFilter{Year: 2023, Month: 8, Slugs: []string{"avito"}},
+			expectedError: errors.ErrInvalidSegmentSlug,
+		},
+		{
+			name:          "Invalid user filter",
+			filter:        models.ReportFilter{Year: 2023, Month: 8, UserIDs: []string{"user"}},
+			expectedError: errors.ErrInvalidUserID,
+		},
+		{
+			name:          "Invalid method filter",
+			filter:        models.ReportFilter{Year: 2023, Month: 8, Methods: []string{models.MethodUpdated}},
+			expectedError: errors.ErrInvalidFilter,
+		},
+		{
+			name:          "Unsupported format",
+			filter:        models.ReportFilter{Year: 2023, Month: 8},
+			format:        "pdf",
+			expectedError: errors.ErrInvalidFormat,
+		},
+		{
+			name:                 "Queue is full",
+			filter:               models.ReportFilter{Year: 2023, Month: 8},
+			submitError:          errors.ErrQueueFull,
+			expectedError:        errors.ErrQueueFull,
+			expectingLatestCall:  true,
+			expectingPendingCall: true,
+			expectingCreateCall:  true,
+			expectingSubmitCall:  true,
+		},
+		{
+			name:                 "Some internal error",
+			filter:               models.ReportFilter{Year: 2023, Month: 8},
+			createError:          os.ErrInvalid,
+			expectedError:        os.ErrInvalid,
+			expectingLatestCall:  true,
+			expectingPendingCall: true,
+			expectingCreateCall:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			jobRepo := NewMockJobRepository(ctrl)
+			storage := NewMockReportStorage(ctrl)
+			scheduler := NewMockScheduler(ctrl)
+
+			if tc.expectingLatestCall {
+				jobRepo.EXPECT().LatestReportJob(context.Background(), gomock.Any(), gomock.Any()).Return(tc.latestReturn, nil)
+			}
+
+			if tc.expectingExistsCall {
+				storage.EXPECT().Exists(context.Background(), tc.latestReturn.File).Return(tc.existsReturn, nil)
+			}
+
+			if tc.expectingPendingCall {
+				jobRepo.EXPECT().PendingReportJob(context.Background(), gomock.Any(), gomock.Any(), gomock.Any()).Return(tc.pendingReturn, nil)
+			}
+
+			if tc.expectingCreateCall {
+				jobRepo.EXPECT().CreateReportJob(context.Background(), gomock.Any()).Return(tc.createError)
+			}
+
+			if tc.expectingSubmitCall {
+				scheduler.EXPECT().Submit(gomock.Any()).Return(tc.submitError)
+			}
+
+			if tc.submitError != nil {
+				jobRepo.EXPECT().FinishReportJob(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, nil, jobRepo, nil, nil, nil, nil, storage, scheduler, &config.Config{}, zp)
+
+			job, err := serv.CreateReport(context.Background(), &tc.filter, tc.format, tc.force)
+
+			a.Equal(tc.expectedError, err)
+
+			if tc.expectedError == nil {
+				a.Equal(tc.expectedStatus, job.Status)
+			}
+		})
+	}
+}
+
+// sliceCursor is a report cursor over prepared rows which fails after them with err.
+type sliceCursor struct {
+	rows   []models.ReportRow
+	err    error
+	pos    int
+	closed bool
+}
+
+func (c *sliceCursor) Next() bool {
+	if c.pos >= len(c.rows) {
+		return false
+	}
+
+	c.pos++
+
+	return true
+}
+
+func (c *sliceCursor) Row() models.ReportRow { return c.rows[c.pos-1] }
+func (c *sliceCursor) Err() error            { return c.err }
+func (c *sliceCursor) Close()                { c.closed = true }
+
+func TestService_CreateReportRun(t *testing.T) {
+	a := assert.New(t)
+
+	timestamp := time.Date(2023, time.August, 26, 19, 00, 00, 00, time.UTC)
+	row := models.ReportRow{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Slug: "TEST_SLUG", Method: models.MethodAdded, Timestamp: timestamp}
+
+	manyRows := make([]models.ReportRow, service.ReportProgressEvery*2+1)
+	for i := range manyRows {
+		manyRows[i] = row
+	}
+
+	testCases := []struct {
+		name             string
+		dataReturn       []models.ReportRow
+		dataError        error
+		cursorError      error
+		expectedStatus   string
+		expectedRows     int
+		expectingFile    bool
+		expectedError    string
+		expectedReport   string
+		expectedProgress []int
+	}{
+		{
+			name: "Report written",
+			dataReturn: []models.ReportRow{
+				row,
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Slug: "TEST_SLUG", Method: models.MethodDeleted, Timestamp: timestamp},
+			},
+			expectedStatus: models.JobDone,
+			expectedRows:   2,
+			expectingFile:  true,
+			expectedReport: "user_id,slug,method,timestamp\n" +
+				"80b0b88d-379e-11ee-8bf7-0242c0a80002,TEST_SLUG,added,2023-08-26T19:00:00Z\n" +
+				"80b0b88d-379e-11ee-8bf7-0242c0a80002,TEST_SLUG,deleted,2023-08-26T19:00:00Z\n",
+		},
+		{
+			name:             "Progress reported",
+			dataReturn:       manyRows,
+			expectedStatus:   models.JobDone,
+			expectedRows:     len(manyRows),
+			expectingFile:    true,
+			expectedProgress: []int{service.ReportProgressEvery, service.ReportProgressEvery * 2},
+		},
+		{
+			name:           "No data",
+			expectedStatus: models.JobFailed,
+			expectedError:  "no data for report",
+		},
+		{
+			name:           "Some internal error",
+			dataError:      os.ErrInvalid,
+			expectedStatus: models.JobFailed,
+			expectedError:  os.ErrInvalid.Error(),
+		},
+		{
+			name:           "Reading rows failed",
+			dataReturn:     []models.ReportRow{row},
+			cursorError:    os.ErrInvalid,
+			expectedStatus: models.JobFailed,
+			expectedError:  os.ErrInvalid.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := NewMockUserRepository(ctrl)
+			jobRepo := NewMockJobRepository(ctrl)
+			storage := NewMockReportStorage(ctrl)
+			scheduler := NewMockScheduler(ctrl)
+
+			var (
+				task     worker.Task
+				created  models.ReportJob
+				finished *models.ReportJob
+				report   bytes.Buffer
+				progress []int
+				cursor   = &sliceCursor{rows: tc.dataReturn, err: tc.cursorError}
+			)
+
+			jobRepo.EXPECT().LatestReportJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+			jobRepo.EXPECT().PendingReportJob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+			jobRepo.EXPECT().CreateReportJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job *models.ReportJob) error {
+				created = *job
+				return nil
+			})
+			scheduler.EXPECT().Submit(gomock.Any()).DoAndReturn(func(t worker.Task) error {
+				task = t
+				return nil
+			})
+
+			jobRepo.EXPECT().StartReportJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			jobRepo.EXPECT().ProgressReportJob(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, rows int) error {
+				progress = append(progress, rows)
+				return nil
+			}).AnyTimes()
+
+			// File is named by the job, so earlier files of the report are not overwritten.
+			storage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, name string, fn func(w io.Writer) error) error {
+					a.Equal(created.ID+".csv", name)
+					return fn(&report)
+				})
+
+			if tc.dataError != nil {
+				userRepo.EXPECT().ReportRows(gomock.Any(), gomock.Any()).Return(nil, tc.dataError)
+			} else {
+				userRepo.EXPECT().ReportRows(gomock.Any(), &models.ReportFilter{
+					Year:  2023,
+					Month: 8,
+					From:  time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+					To:    time.Date(2023, time.September, 1, 0, 0, 0, 0, time.UTC),
+					Slugs: []string{"TEST_SLUG"},
+				}).Return(cursor, nil)
+			}
+
+			jobRepo.EXPECT().FinishReportJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job *models.ReportJob) error {
+				finished = job
+				return nil
+			})
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, nil, jobRepo, nil, nil, nil, nil, storage, scheduler, &config.Config{}, zp)
+
+			_, err := serv.CreateReport(context.Background(), &models.ReportFilter{Year: 2023, Month: 8, Slugs: []string{"TEST_SLUG"}}, models.FormatCSV, false)
+			a.NoError(err)
+
+			task(context.Background())
+
+			a.Equal(tc.expectedStatus, finished.Status)
+			a.Equal(tc.expectedRows, finished.Rows)
+			if tc.expectingFile {
+				a.Equal(created.ID+".csv", finished.File)
+			} else {
+				a.Empty(finished.File)
+			}
+
+			a.Equal(time.Date(2023, time.September, 1, 0, 0, 0, 0, time.UTC), finished.To)
+			a.Equal(tc.expectedError, finished.Error)
+			a.NotNil(finished.FinishedAt)
+			a.Equal(tc.expectedStatus == models.JobDone, finished.GeneratedAt != nil)
+			a.Equal(tc.expectedProgress, progress)
+			a.Equal(tc.dataError == nil, cursor.closed)
+
+			if tc.expectedReport != "" {
+				a.Equal(tc.expectedReport, report.String())
+			}
+		})
+	}
+}
+
+func TestService_StreamReport(t *testing.T) {
+	a := assert.New(t)
+
+	row := models.ReportRow{
+		UserID:    "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+		Slug:      "TEST_SLUG",
+		Method:    models.MethodAdded,
+		Timestamp: time.Date(2023, time.August, 26, 19, 00, 00, 00, time.UTC),
+	}
+
+	testCases := []struct {
+		name           string
+		filter         models.ReportFilter
+		format         string
+		dataReturn     []models.ReportRow
+		dataError      error
+		expectedReport string
+		expectedError  error
+
+		expectingDataCall bool
+	}{
+		{
+			name:              "Report streamed",
+			filter:            models.ReportFilter{Year: 2023, Month: 8},
+			format:            models.FormatNDJSON,
+			dataReturn:        []models.ReportRow{row},
+			expectedReport:    `{"userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002","slug":"TEST_SLUG","method":"added","timestamp":"2023-08-26T19:00:00Z"}` + "\n",
+			expectingDataCall: true,
+		},
+		{
+			name:              "Empty report streamed",
+			filter:            models.ReportFilter{Year: 2023, Month: 8},
+			expectedReport:    "user_id,slug,method,timestamp\n",
+			expectingDataCall: true,
+		},
+		{
+			name:          "Invalid period",
+			filter:        models.ReportFilter{Year: 2023, Month: 13},
+			expectedError: errors.ErrInvalidPeriod,
+		},
+		{
+			name:          "Unsupported format",
+			filter:        models.ReportFilter{Year: 2023, Month: 8},
+			format:        "pdf",
+			expectedError: errors.ErrInvalidFormat,
+		},
+		{
+			name:              "Some internal error",
+			filter:            models.ReportFilter{Year: 2023, Month: 8},
+			dataError:         os.ErrInvalid,
+			expectedError:     os.ErrInvalid,
+			expectingDataCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := NewMockUserRepository(ctrl)
+
+			if tc.expectingDataCall {
+				var cursor models.ReportCursor
+				if tc.dataError == nil {
+					cursor = &sliceCursor{rows: tc.dataReturn}
+				}
+
+				userRepo.EXPECT().ReportRows(context.Background(), gomock.Any()).Return(cursor, tc.dataError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, nil, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			var out bytes.Buffer
+
+			err := serv.StreamReport(context.Background(), &tc.filter, tc.format, &out)
+
+			a.Equal(tc.expectedError, err)
+			a.Equal(tc.expectedReport, out.String())
+		})
+	}
+}
+
+func TestService_CreateReportID(t *testing.T) {
+	a := assert.New(t)
+
+	august := models.ReportFilter{
+		From:    time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2023, time.September, 1, 0, 0, 0, 0, time.UTC),
+		Slugs:   []string{"AVITO_TEST", "AVITO_VOICE"},
+		Methods: []string{models.MethodAdded},
+	}
+
+	testCases := []struct {
+		name     string
+		filter   models.ReportFilter
+		expected bool
+	}{
+		{
+			name: "Month instead of range",
+			filter: models.ReportFilter{
+				Year: 2023, Month: 8, Slugs: august.Slugs, Methods: august.Methods,
+			},
+			expected: true,
+		},
+		{
+			name: "Same range in other time zone",
+			filter: models.ReportFilter{
+				From:    august.From.In(time.FixedZone("MSK", 3*60*60)),
+				To:      august.To.In(time.FixedZone("MSK", 3*60*60)),
+				Slugs:   august.Slugs,
+				Methods: august.Methods,
+			},
+			expected: true,
+		},
+		{
+			name: "Reordered and repeated slugs",
+			filter: models.ReportFilter{
+				From: august.From, To: august.To, Slugs: []string{"AVITO_VOICE", "AVITO_TEST", "AVITO_VOICE"}, Methods: august.Methods,
+			},
+			expected: true,
+		},
+		{
+			name: "Other methods",
+			filter: models.ReportFilter{
+				From: august.From, To: august.To, Slugs: august.Slugs, Methods: []string{models.MethodDeleted},
+			},
+			expected: false,
+		},
+		{
+			name: "Other range",
+			filter: models.ReportFilter{
+				From: august.From, To: august.To.Add(-time.Hour), Slugs: august.Slugs, Methods: august.Methods,
+			},
+			expected: false,
+		},
+	}
+
+	reportID := func(t *testing.T, filter models.ReportFilter) string {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		jobRepo := NewMockJobRepository(ctrl)
+		scheduler := NewMockScheduler(ctrl)
+
+		jobRepo.EXPECT().LatestReportJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+		jobRepo.EXPECT().PendingReportJob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+		jobRepo.EXPECT().CreateReportJob(gomock.Any(), gomock.Any()).Return(nil)
+		scheduler.EXPECT().Submit(gomock.Any()).Return(nil)
+
+		zp, _ := zap.NewDevelopment()
+		serv := service.New(nil, nil, jobRepo, nil, nil, nil, nil, nil, scheduler, &config.Config{}, zp)
+
+		job, err := serv.CreateReport(context.Background(), &filter, "", false)
+		a.NoError(err)
+
+		return job.ReportID
+	}
+
+	expected := reportID(t, august)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a.Equal(tc.expected, reportID(t, tc.filter) == expected)
+		})
+	}
+}
+
+func TestService_CreateReportRunOpenPeriod(t *testing.T) {
+	a := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := NewMockUserRepository(ctrl)
+	jobRepo := NewMockJobRepository(ctrl)
+	storage := NewMockReportStorage(ctrl)
+	scheduler := NewMockScheduler(ctrl)
+
+	var (
+		task     worker.Task
+		finished *models.ReportJob
+		now      = time.Now()
+		from     = now.Add(-time.Hour)
+	)
+
+	jobRepo.EXPECT().LatestReportJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	jobRepo.EXPECT().PendingReportJob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	jobRepo.EXPECT().CreateReportJob(gomock.Any(), gomock.Any()).Return(nil)
+	scheduler.EXPECT().Submit(gomock.Any()).DoAndReturn(func(t worker.Task) error {
+		task = t
+		return nil
+	})
+
+	jobRepo.EXPECT().StartReportJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	userRepo.EXPECT().ReportRows(gomock.Any(), gomock.Any()).
+		Return(&sliceCursor{rows: []models.ReportRow{{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Slug: "TEST_SLUG"}}}, nil)
+	storage.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, fn func(w io.Writer) error) error {
+			return fn(io.Discard)
+		})
+	jobRepo.EXPECT().FinishReportJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job *models.ReportJob) error {
+		finished = job
+		return nil
+	})
+
+	zp, _ := zap.NewDevelopment()
+	serv := service.New(userRepo, nil, jobRepo, nil, nil, nil, nil, storage, scheduler, &config.Config{}, zp)
+
+	_, err := serv.CreateReport(context.Background(), &models.ReportFilter{From: from, To: now.Add(time.Hour)}, models.FormatCSV, false)
+	a.NoError(err)
+
+	task(context.Background())
+
+	// Job tells the range its file actually covers.
+	a.Equal(models.JobDone, finished.Status)
+	a.Equal(*finished.GeneratedAt, finished.To)
+	a.Equal(from.UTC(), finished.From)
+}
+
+func TestService_ReportFile(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name string
+		file string
+
+		latestReturn *models.ReportJob
+		stored       string
+
+		urlReturn    string
+		existsReturn bool
+		openError    error
+
+		expectedURL   string
+		expectedError error
+
+		expectingURLCall    bool
+		expectingExistsCall bool
+		expectingOpenCall   bool
+	}{
+		{
+			name:              "Report streamed",
+			file:              "8_2023_report.csv",
+			expectingURLCall:  true,
+			expectingOpenCall: true,
+		},
+		{
+			name:                "Report redirected",
+			file:                "8_2023_report.csv",
+			urlReturn:           "http://storage/reports/8_2023_report.csv",
+			existsReturn:        true,
+			expectedURL:         "http://storage/reports/8_2023_report.csv",
+			expectingURLCall:    true,
+			expectingExistsCall: true,
+		},
+		{
+			name:                "Redirect to missing report",
+			file:                "8_2023_report.csv",
+			urlReturn:           "http://storage/reports/8_2023_report.csv",
+			expectedError:       errors.ErrReportNotFound,
+			expectingURLCall:    true,
+			expectingExistsCall: true,
+		},
+		{
+			name:              "Report not found",
+			file:              "8_2023_report.csv",
+			openError:         errors.ErrReportNotFound,
+			expectedError:     errors.ErrReportNotFound,
+			expectingURLCall:  true,
+			expectingOpenCall: true,
+		},
+		{
+			name:              "Report by its id",
+			file:              "fcbf375313027e3ca47841baf5736886.csv",
+			latestReturn:      &models.ReportJob{File: "80b0b88d-379e-11ee-8bf7-0242c0a80004.csv"},
+			stored:            "80b0b88d-379e-11ee-8bf7-0242c0a80004.csv",
+			expectingURLCall:  true,
+			expectingOpenCall: true,
+		},
+		{
+			name:          "Report by its id is not generated",
+			file:          "fcbf375313027e3ca47841baf5736886.csv",
+			expectedError: errors.ErrReportNotFound,
+		},
+		{
+			name:          "Path traversal",
+			file:          "../config.yml",
+			expectedError: errors.ErrReportNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			jobRepo := NewMockJobRepository(ctrl)
+			storage := NewMockReportStorage(ctrl)
+
+			stored := tc.file
+			if tc.stored != "" {
+				stored = tc.stored
+			}
+
+			if strings.HasPrefix(tc.file, "fcbf375313027e3ca47841baf5736886.") {
+				jobRepo.EXPECT().LatestReportJob(context.Background(), "fcbf375313027e3ca47841baf5736886", models.FormatCSV).
+					Return(tc.latestReturn, nil)
+			}
+
+			if tc.expectingURLCall {
+				storage.EXPECT().URL(context.Background(), stored).Return(tc.urlReturn, nil)
+			}
+
+			if tc.expectingExistsCall {
+				storage.EXPECT().Exists(context.Background(), stored).Return(tc.existsReturn, nil)
+			}
+
+			if tc.expectingOpenCall {
+				var content io.ReadCloser
+				if tc.openError == nil {
+					content = io.NopCloser(strings.NewReader("report"))
+				}
+
+				storage.EXPECT().Open(context.Background(), stored).Return(content, tc.openError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, nil, jobRepo, nil, nil, nil, nil, storage, nil, &config.Config{}, zp)
+
+			file, err := serv.ReportFile(context.Background(), tc.file)
+
+			a.Equal(tc.expectedError, err)
+
+			if tc.expectedError == nil {
+				a.Equal(tc.file, file.Name)
+				a.Equal(tc.expectedURL, file.URL)
+			}
+		})
+	}
+}
+
+func TestService_ReportJob(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name string
+		id   string
+
+		repositoryReturn *models.ReportJob
+		repositoryError  error
+
+		expectedError error
+
+		expectingRepositoryCall bool
+	}{
+		{
+			name:                    "Job found",
+			id:                      "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			repositoryReturn:        &models.ReportJob{Status: models.JobRunning},
+			expectingRepositoryCall: true,
+		},
+		{
+			name:          "Invalid job id",
+			id:            "123",
+			expectedError: errors.ErrReportJobNotFound,
+		},
+		{
+			name:                    "Job not found",
+			id:                      "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			expectedError:           errors.ErrReportJobNotFound,
+			expectingRepositoryCall: true,
+		},
+		{
+			name:                    "Some internal error",
+			id:                      "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			repositoryError:         os.ErrInvalid,
+			expectedError:           os.ErrInvalid,
+			expectingRepositoryCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			jobRepo := NewMockJobRepository(ctrl)
+
+			if tc.expectingRepositoryCall {
+				jobRepo.EXPECT().GetReportJob(context.Background(), tc.id).Return(tc.repositoryReturn, tc.repositoryError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, nil, jobRepo, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			job, err := serv.ReportJob(context.Background(), tc.id)
+
+			a.Equal(tc.expectedError, err)
+
+			if tc.expectedError == nil {
+				a.Equal(tc.repositoryReturn, job)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"regexp"
 
 	"github.com/dupreehkuda/avito-segments/internal/errors"
@@ -13,6 +14,10 @@ func (s *Service) SegmentAdd(ctx context.Context, segment *models.Segment) error
 		return errors.ErrInvalidSegmentSlug
 	}
 
+	if !IsValidPercentage(segment.Percentage) {
+		return errors.ErrInvalidPercentage
+	}
+
 	seg, err := s.segmentRepo.Get(ctx, segment.Slug)
 	if err != nil {
 		return err
@@ -22,7 +27,7 @@ func (s *Service) SegmentAdd(ctx context.Context, segment *models.Segment) error
 		return errors.ErrDuplicateSegment
 	}
 
-	err = s.segmentRepo.Add(ctx, segment)
+	err = s.segmentRepo.Add(ctx, segment, auditEntry(ctx, models.AuditSegmentAdd, "", []string{segment.Slug}))
 	if err != nil {
 		return err
 	}
@@ -48,7 +53,7 @@ func (s *Service) SegmentDelete(ctx context.Context, slug string) error {
 		return errors.ErrAlreadyDeleted
 	}
 
-	err = s.segmentRepo.Delete(ctx, slug)
+	err = s.segmentRepo.Delete(ctx, slug, auditEntry(ctx, models.AuditSegmentDelete, "", []string{slug}))
 	if err != nil {
 		return err
 	}
@@ -56,8 +61,228 @@ func (s *Service) SegmentDelete(ctx context.Context, slug string) error {
 	return nil
 }
 
+// SegmentUpdate changes segment's metadata. Raising percentage enrolls newly covered users,
+// lowering it only affects users that appear later.
+func (s *Service) SegmentUpdate(ctx context.Context, slug string, req *models.SegmentUpdateRequest) error {
+	if !IsValidSlug(slug) {
+		return errors.ErrInvalidSegmentSlug
+	}
+
+	if req.Description == nil && req.Percentage == nil {
+		return errors.ErrNothingToUpdate
+	}
+
+	if req.Percentage != nil && !IsValidPercentage(*req.Percentage) {
+		return errors.ErrInvalidPercentage
+	}
+
+	seg, err := s.segmentRepo.Get(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	if seg == nil {
+		return errors.ErrSegmentNotFound
+	}
+
+	if !seg.DeletedAt.IsZero() {
+		return errors.ErrAlreadyDeleted
+	}
+
+	return s.segmentRepo.Update(ctx, slug, req, auditEntry(ctx, models.AuditSegmentUpdate, "", []string{slug}))
+}
+
+// SegmentRestore brings deleted segment back. Memberships hidden by the delete
+// become active again only if restoreMembers is set, otherwise they are removed.
+func (s *Service) SegmentRestore(ctx context.Context, slug string, restoreMembers bool) error {
+	if !IsValidSlug(slug) {
+		return errors.ErrInvalidSegmentSlug
+	}
+
+	seg, err := s.segmentRepo.Get(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	if seg == nil {
+		return errors.ErrSegmentNotFound
+	}
+
+	if seg.DeletedAt.IsZero() {
+		return errors.ErrNotDeleted
+	}
+
+	return s.segmentRepo.Restore(ctx, slug, restoreMembers, auditEntry(ctx, models.AuditSegmentRestore, "", []string{slug}))
+}
+
+func (s *Service) SegmentList(ctx context.Context, filter *models.SegmentFilter) (*models.SegmentListResponse, error) {
+	if err := IsValidSegmentFilter(filter); err != nil {
+		return nil, err
+	}
+
+	segments, total, err := s.segmentRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SegmentListResponse{
+		Segments: segments,
+		Total:    total,
+		Limit:    filter.Limit,
+		Offset:   filter.Offset,
+	}, nil
+}
+
+func (s *Service) SegmentGet(ctx context.Context, slug string) (*models.SegmentInfo, error) {
+	if !IsValidSlug(slug) {
+		return nil, errors.ErrInvalidSegmentSlug
+	}
+
+	info, err := s.segmentRepo.Info(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if info == nil {
+		return nil, errors.ErrSegmentNotFound
+	}
+
+	return info, nil
+}
+
+// SegmentMembers returns a page of segment's active members. Cursor is taken from previous page,
+// empty cursor means the first page.
+func (s *Service) SegmentMembers(ctx context.Context, slug, cursor string, limit int) (*models.SegmentMembersResponse, error) {
+	if limit < 1 || limit > MaxPageLimit {
+		return nil, errors.ErrInvalidPagination
+	}
+
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.activeSegment(ctx, slug); err != nil {
+		return nil, err
+	}
+
+	members, err := s.segmentRepo.Members(ctx, slug, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.SegmentMembersResponse{Users: members}
+
+	if len(members) > limit {
+		resp.Users = members[:limit]
+		resp.NextCursor = encodeCursor(members[limit-1].UserID)
+	}
+
+	return resp, nil
+}
+
+// SegmentExport passes every active member of the segment to fn.
+func (s *Service) SegmentExport(ctx context.Context, slug string, fn func(member models.SegmentMember) error) error {
+	if err := s.activeSegment(ctx, slug); err != nil {
+		return err
+	}
+
+	return s.segmentRepo.StreamMembers(ctx, slug, fn)
+}
+
+// activeSegment checks that segment exists and is not deleted.
+func (s *Service) activeSegment(ctx context.Context, slug string) error {
+	if !IsValidSlug(slug) {
+		return errors.ErrInvalidSegmentSlug
+	}
+
+	seg, err := s.segmentRepo.Get(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	if seg == nil {
+		return errors.ErrSegmentNotFound
+	}
+
+	if !seg.DeletedAt.IsZero() {
+		return errors.ErrAlreadyDeleted
+	}
+
+	return nil
+}
+
+func encodeCursor(userID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(userID))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+
+	userID, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(userID) == 0 {
+		return "", errors.ErrInvalidPagination
+	}
+
+	return string(userID), nil
+}
+
+// segmentsExist checks that all the slugs are in catalog and reports the missing ones otherwise.
+func (s *Service) segmentsExist(ctx context.Context, slugs []string) error {
+	existing, err := s.segmentRepo.Existing(ctx, slugs)
+	if err != nil {
+		return err
+	}
+
+	found := make(map[string]struct{}, len(existing))
+	for _, slug := range existing {
+		found[slug] = struct{}{}
+	}
+
+	var missing []string
+
+	for _, slug := range uniqueSorted(slugs) {
+		if _, ok := found[slug]; !ok {
+			missing = append(missing, slug)
+		}
+	}
+
+	if len(missing) > 0 {
+		return errors.WithSlugs(errors.ErrSegmentsNotFound, missing...)
+	}
+
+	return nil
+}
+
 func IsValidSlug(slug string) bool {
 	pattern := "^[A-Z0-9_]+$"
 	regex := regexp.MustCompile(pattern)
 	return regex.MatchString(slug)
 }
+
+// IsValidPercentage checks that percentage of automatically enrolled users is in [0, 100].
+// Zero percentage means that segment is assigned only manually.
+func IsValidPercentage(percentage int) bool {
+	return percentage >= 0 && percentage <= 100
+}
+
+// IsValidSegmentFilter checks segment catalog filter and pagination.
+func IsValidSegmentFilter(filter *models.SegmentFilter) error {
+	switch filter.Status {
+	case models.SegmentStatusActive, models.SegmentStatusDeleted, models.SegmentStatusAll:
+	default:
+		return errors.ErrInvalidFilter
+	}
+
+	if filter.Prefix != "" && !IsValidSlug(filter.Prefix) {
+		return errors.ErrInvalidSegmentSlug
+	}
+
+	if filter.Limit < 1 || filter.Limit > MaxPageLimit || filter.Offset < 0 {
+		return errors.ErrInvalidPagination
+	}
+
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 
+	"github.com/dupreehkuda/avito-segments/internal/config"
 	"github.com/dupreehkuda/avito-segments/internal/errors"
 	"github.com/dupreehkuda/avito-segments/internal/models"
 	"github.com/dupreehkuda/avito-segments/internal/service"
@@ -65,6 +66,45 @@ func TestService_SegmentAdd(t *testing.T) {
 			expectingGet:     false,
 			expectingAdd:     false,
 		},
+		{
+			name: "Segment created w/ percentage",
+			inputBody: &models.Segment{
+				Slug:       "NEW_SLUG",
+				Percentage: 30,
+			},
+			getSegmentReturn: nil,
+			getSegmentError:  nil,
+			repositoryReturn: nil,
+			expectedReturn:   nil,
+			expectingGet:     true,
+			expectingAdd:     true,
+		},
+		{
+			name: "Invalid percentage",
+			inputBody: &models.Segment{
+				Slug:       "NEW_SLUG",
+				Percentage: 101,
+			},
+			getSegmentReturn: nil,
+			getSegmentError:  nil,
+			repositoryReturn: nil,
+			expectedReturn:   errors.ErrInvalidPercentage,
+			expectingGet:     false,
+			expectingAdd:     false,
+		},
+		{
+			name: "Negative percentage",
+			inputBody: &models.Segment{
+				Slug:       "NEW_SLUG",
+				Percentage: -1,
+			},
+			getSegmentReturn: nil,
+			getSegmentError:  nil,
+			repositoryReturn: nil,
+			expectedReturn:   errors.ErrInvalidPercentage,
+			expectingGet:     false,
+			expectingAdd:     false,
+		},
 		{
 			name: "Duplicate entry",
 			inputBody: &models.Segment{
@@ -123,11 +163,11 @@ func TestService_SegmentAdd(t *testing.T) {
 			}
 
 			if tc.expectingAdd {
-				segmentRepo.EXPECT().Add(context.Background(), tc.inputBody).Return(tc.repositoryReturn)
+				segmentRepo.EXPECT().Add(context.Background(), tc.inputBody, gomock.Any()).Return(tc.repositoryReturn)
 			}
 
 			zp, _ := zap.NewDevelopment()
-			serv := service.New(userRepo, segmentRepo, zp)
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
 
 			err := serv.SegmentAdd(context.Background(), tc.inputBody)
 
@@ -246,11 +286,11 @@ func TestService_SegmentDelete(t *testing.T) {
 			}
 
 			if tc.expectingDelete {
-				segmentRepo.EXPECT().Delete(context.Background(), tc.input).Return(tc.repositoryReturn)
+				segmentRepo.EXPECT().Delete(context.Background(), tc.input, gomock.Any()).Return(tc.repositoryReturn)
 			}
 
 			zp, _ := zap.NewDevelopment()
-			serv := service.New(userRepo, segmentRepo, zp)
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
 
 			err := serv.SegmentDelete(context.Background(), tc.input)
 
@@ -313,7 +353,7 @@ func TestIsValidSegment(t *testing.T) {
 			name: "Valid date",
 			segment: models.UserSegment{
 				Slug:   "AVITO_PERFORMANCE_VAS",
-				Expire: time.Date(2024, time.August, 26, 19, 00, 00, 00, time.Local),
+				Expire: time.Date(2099, time.August, 26, 19, 00, 00, 00, time.Local),
 			},
 			want: nil,
 		},
@@ -323,7 +363,7 @@ func TestIsValidSegment(t *testing.T) {
 				Slug:   "AVITO_PERFORMANCE_VAS",
 				Expire: time.Date(2022, time.August, 26, 19, 00, 00, 00, time.Local),
 			},
-			want: errors.ErrAlreadyExpired,
+			want: errors.WithSlugs(errors.ErrAlreadyExpired, "AVITO_PERFORMANCE_VAS"),
 		},
 	}
 
@@ -333,3 +373,533 @@ func TestIsValidSegment(t *testing.T) {
 		})
 	}
 }
+
+func TestService_SegmentList(t *testing.T) {
+	a := assert.New(t)
+
+	segments := []models.SegmentInfo{
+		{
+			Slug:      "NEW_SLUG",
+			CreatedAt: time.Date(2023, time.August, 26, 19, 00, 00, 00, time.UTC),
+			Members:   10,
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		input          *models.SegmentFilter
+		listReturn     []models.SegmentInfo
+		listTotal      int
+		listError      error
+		expectedReturn *models.SegmentListResponse
+		expectedError  error
+		expectingList  bool
+	}{
+		{
+			name:       "Segments listed",
+			input:      &models.SegmentFilter{Status: models.SegmentStatusActive, Prefix: "NEW", Limit: 10},
+			listReturn: segments,
+			listTotal:  1,
+			expectedReturn: &models.SegmentListResponse{
+				Segments: segments,
+				Total:    1,
+				Limit:    10,
+			},
+			expectingList: true,
+		},
+		{
+			name:          "Invalid status",
+			input:         &models.SegmentFilter{Status: "expired", Limit: 10},
+			expectedError: errors.ErrInvalidFilter,
+		},
+		{
+			name:          "Invalid prefix",
+			input:         &models.SegmentFilter{Status: models.SegmentStatusAll, Prefix: "new%", Limit: 10},
+			expectedError: errors.ErrInvalidSegmentSlug,
+		},
+		{
+			name:          "Limit too big",
+			input:         &models.SegmentFilter{Status: models.SegmentStatusAll, Limit: service.MaxPageLimit + 1},
+			expectedError: errors.ErrInvalidPagination,
+		},
+		{
+			name:          "Negative offset",
+			input:         &models.SegmentFilter{Status: models.SegmentStatusAll, Limit: 10, Offset: -1},
+			expectedError: errors.ErrInvalidPagination,
+		},
+		{
+			name:          "DB error",
+			input:         &models.SegmentFilter{Status: models.SegmentStatusDeleted, Limit: 10},
+			listError:     pgx.ErrTxClosed,
+			expectedError: pgx.ErrTxClosed,
+			expectingList: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := NewMockUserRepository(ctrl)
+			segmentRepo := NewMockSegmentRepository(ctrl)
+
+			if tc.expectingList {
+				segmentRepo.EXPECT().List(context.Background(), tc.input).Return(tc.listReturn, tc.listTotal, tc.listError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			resp, err := serv.SegmentList(context.Background(), tc.input)
+
+			a.Equal(tc.expectedReturn, resp)
+			a.Equal(tc.expectedError, err)
+		})
+	}
+}
+
+func TestService_SegmentGet(t *testing.T) {
+	a := assert.New(t)
+
+	info := &models.SegmentInfo{
+		Slug:      "NEW_SLUG",
+		CreatedAt: time.Date(2023, time.August, 26, 19, 00, 00, 00, time.UTC),
+		Members:   10,
+	}
+
+	testCases := []struct {
+		name           string
+		input          string
+		infoReturn     *models.SegmentInfo
+		infoError      error
+		expectedReturn *models.SegmentInfo
+		expectedError  error
+		expectingInfo  bool
+	}{
+		{
+			name:           "Segment found",
+			input:          "NEW_SLUG",
+			infoReturn:     info,
+			expectedReturn: info,
+			expectingInfo:  true,
+		},
+		{
+			name:          "Segment not found",
+			input:         "OLD_SLUG",
+			expectedError: errors.ErrSegmentNotFound,
+			expectingInfo: true,
+		},
+		{
+			name:          "Invalid slug",
+			input:         "NeW-SLug",
+			expectedError: errors.ErrInvalidSegmentSlug,
+		},
+		{
+			name:          "DB error",
+			input:         "NEW_SLUG",
+			infoError:     pgx.ErrTxClosed,
+			expectedError: pgx.ErrTxClosed,
+			expectingInfo: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := NewMockUserRepository(ctrl)
+			segmentRepo := NewMockSegmentRepository(ctrl)
+
+			if tc.expectingInfo {
+				segmentRepo.EXPECT().Info(context.Background(), tc.input).Return(tc.infoReturn, tc.infoError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			resp, err := serv.SegmentGet(context.Background(), tc.input)
+
+			a.Equal(tc.expectedReturn, resp)
+			a.Equal(tc.expectedError, err)
+		})
+	}
+}
+
+func TestService_SegmentUpdate(t *testing.T) {
+	a := assert.New(t)
+
+	description := "updated description"
+	percentage := 40
+	invalidPercentage := 140
+
+	testCases := []struct {
+		name             string
+		slug             string
+		input            *models.SegmentUpdateRequest
+		getSegmentReturn *models.Segment
+		getSegmentError  error
+		repositoryReturn error
+		expectedReturn   error
+		expectingGet     bool
+		expectingUpdate  bool
+	}{
+		{
+			name:             "Segment updated",
+			slug:             "NEW_SLUG",
+			input:            &models.SegmentUpdateRequest{Description: &description, Percentage: &percentage},
+			getSegmentReturn: &models.Segment{Slug: "NEW_SLUG"},
+			expectingGet:     true,
+			expectingUpdate:  true,
+		},
+		{
+			name:           "Nothing to update",
+			slug:           "NEW_SLUG",
+			input:          &models.SegmentUpdateRequest{},
+			expectedReturn: errors.ErrNothingToUpdate,
+		},
+		{
+			name:           "Invalid percentage",
+			slug:           "NEW_SLUG",
+			input:          &models.SegmentUpdateRequest{Percentage: &invalidPercentage},
+			expectedReturn: errors.ErrInvalidPercentage,
+		},
+		{
+			name:           "Invalid slug",
+			slug:           "NeW-SLug",
+			input:          &models.SegmentUpdateRequest{Description: &description},
+			expectedReturn: errors.ErrInvalidSegmentSlug,
+		},
+		{
+			name:           "Segment not found",
+			slug:           "NEW_SLUG",
+			input:          &models.SegmentUpdateRequest{Description: &description},
+			expectedReturn: errors.ErrSegmentNotFound,
+			expectingGet:   true,
+		},
+		{
+			name:  "Segment deleted",
+			slug:  "NEW_SLUG",
+			input: &models.SegmentUpdateRequest{Description: &description},
+			getSegmentReturn: &models.Segment{
+				Slug:      "NEW_SLUG",
+				DeletedAt: time.Date(2022, time.August, 26, 19, 00, 00, 00, time.Local),
+			},
+			expectedReturn: errors.ErrAlreadyDeleted,
+			expectingGet:   true,
+		},
+		{
+			name:             "DB error",
+			slug:             "NEW_SLUG",
+			input:            &models.SegmentUpdateRequest{Description: &description},
+			getSegmentReturn: &models.Segment{Slug: "NEW_SLUG"},
+			repositoryReturn: pgx.ErrTxClosed,
+			expectedReturn:   pgx.ErrTxClosed,
+			expectingGet:     true,
+			expectingUpdate:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := NewMockUserRepository(ctrl)
+			segmentRepo := NewMockSegmentRepository(ctrl)
+
+			if tc.expectingGet {
+				segmentRepo.EXPECT().Get(context.Background(), tc.slug).Return(tc.getSegmentReturn, tc.getSegmentError)
+			}
+
+			if tc.expectingUpdate {
+				segmentRepo.EXPECT().Update(context.Background(), tc.slug, tc.input, gomock.Any()).Return(tc.repositoryReturn)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			err := serv.SegmentUpdate(context.Background(), tc.slug, tc.input)
+
+			a.Equal(tc.expectedReturn, err)
+		})
+	}
+}
+
+func TestService_SegmentRestore(t *testing.T) {
+	a := assert.New(t)
+
+	deleted := &models.Segment{
+		Slug:      "NEW_SLUG",
+		DeletedAt: time.Date(2022, time.August, 26, 19, 00, 00, 00, time.Local),
+	}
+
+	testCases := []struct {
+		name             string
+		slug             string
+		restoreMembers   bool
+		getSegmentReturn *models.Segment
+		repositoryReturn error
+		expectedReturn   error
+		expectingGet     bool
+		expectingRestore bool
+	}{
+		{
+			name:             "Segment restored",
+			slug:             "NEW_SLUG",
+			getSegmentReturn: deleted,
+			expectingGet:     true,
+			expectingRestore: true,
+		},
+		{
+			name:             "Segment restored w/ members",
+			slug:             "NEW_SLUG",
+			restoreMembers:   true,
+			getSegmentReturn: deleted,
+			expectingGet:     true,
+			expectingRestore: true,
+		},
+		{
+			name:             "Segment not deleted",
+			slug:             "NEW_SLUG",
+			getSegmentReturn: &models.Segment{Slug: "NEW_SLUG"},
+			expectedReturn:   errors.ErrNotDeleted,
+			expectingGet:     true,
+		},
+		{
+			name:           "Segment not found",
+			slug:           "NEW_SLUG",
+			expectedReturn: errors.ErrSegmentNotFound,
+			expectingGet:   true,
+		},
+		{
+			name:           "Invalid slug",
+			slug:           "",
+			expectedReturn: errors.ErrInvalidSegmentSlug,
+		},
+		{
+			name:             "DB error",
+			slug:             "NEW_SLUG",
+			getSegmentReturn: deleted,
+			repositoryReturn: pgx.ErrTxClosed,
+			expectedReturn:   pgx.ErrTxClosed,
+			expectingGet:     true,
+			expectingRestore: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := NewMockUserRepository(ctrl)
+			segmentRepo := NewMockSegmentRepository(ctrl)
+
+			if tc.expectingGet {
+				segmentRepo.EXPECT().Get(context.Background(), tc.slug).Return(tc.getSegmentReturn, nil)
+			}
+
+			if tc.expectingRestore {
+				segmentRepo.EXPECT().Restore(context.Background(), tc.slug, tc.restoreMembers, gomock.Any()).Return(tc.repositoryReturn)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			err := serv.SegmentRestore(context.Background(), tc.slug, tc.restoreMembers)
+
+			a.Equal(tc.expectedReturn, err)
+		})
+	}
+}
+
+func TestService_SegmentMembers(t *testing.T) {
+	a := assert.New(t)
+
+	members := []models.SegmentMember{
+		{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002"},
+		{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80003"},
+		{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80004"},
+	}
+
+	testCases := []struct {
+		name   string
+		slug   string
+		cursor string
+		limit  int
+
+		getReturn *models.Segment
+
+		membersAfter  string
+		membersReturn []models.SegmentMember
+		membersError  error
+
+		expectedUsers  []models.SegmentMember
+		expectedCursor string
+		expectedError  error
+
+		expectingGetCall     bool
+		expectingMembersCall bool
+	}{
+		{
+			name:                 "Last page",
+			slug:                 "TEST_SLUG",
+			limit:                3,
+			getReturn:            &models.Segment{Slug: "TEST_SLUG"},
+			membersReturn:        members,
+			expectedUsers:        members,
+			expectingGetCall:     true,
+			expectingMembersCall: true,
+		},
+		{
+			name:                 "Page w/ next cursor",
+			slug:                 "TEST_SLUG",
+			cursor:               "ODBiMGI4OGQtMzc5ZS0xMWVlLThiZjctMDI0MmMwYTgwMDAx",
+			limit:                2,
+			getReturn:            &models.Segment{Slug: "TEST_SLUG"},
+			membersAfter:         "80b0b88d-379e-11ee-8bf7-0242c0a80001",
+			membersReturn:        members,
+			expectedUsers:        members[:2],
+			expectedCursor:       "ODBiMGI4OGQtMzc5ZS0xMWVlLThiZjctMDI0MmMwYTgwMDAz",
+			expectingGetCall:     true,
+			expectingMembersCall: true,
+		},
+		{
+			name:          "Invalid cursor",
+			slug:          "TEST_SLUG",
+			cursor:        "!!!",
+			limit:         2,
+			expectedError: errors.ErrInvalidPagination,
+		},
+		{
+			name:          "Invalid limit",
+			slug:          "TEST_SLUG",
+			limit:         service.MaxPageLimit + 1,
+			expectedError: errors.ErrInvalidPagination,
+		},
+		{
+			name:          "Invalid slug",
+			slug:          "test-slug",
+			limit:         2,
+			expectedError: errors.ErrInvalidSegmentSlug,
+		},
+		{
+			name:             "Segment not found",
+			slug:             "TEST_SLUG",
+			limit:            2,
+			expectedError:    errors.ErrSegmentNotFound,
+			expectingGetCall: true,
+		},
+		{
+			name:             "Segment deleted",
+			slug:             "TEST_SLUG",
+			limit:            2,
+			getReturn:        &models.Segment{Slug: "TEST_SLUG", DeletedAt: time.Now()},
+			expectedError:    errors.ErrAlreadyDeleted,
+			expectingGetCall: true,
+		},
+		{
+			name:                 "DB error",
+			slug:                 "TEST_SLUG",
+			limit:                2,
+			getReturn:            &models.Segment{Slug: "TEST_SLUG"},
+			membersError:         pgx.ErrTxClosed,
+			expectedError:        pgx.ErrTxClosed,
+			expectingGetCall:     true,
+			expectingMembersCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			segmentRepo := NewMockSegmentRepository(ctrl)
+
+			if tc.expectingGetCall {
+				segmentRepo.EXPECT().Get(context.Background(), tc.slug).Return(tc.getReturn, nil)
+			}
+
+			if tc.expectingMembersCall {
+				segmentRepo.EXPECT().Members(context.Background(), tc.slug, tc.membersAfter, tc.limit+1).Return(tc.membersReturn, tc.membersError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			resp, err := serv.SegmentMembers(context.Background(), tc.slug, tc.cursor, tc.limit)
+
+			a.Equal(tc.expectedError, err)
+
+			if tc.expectedError == nil {
+				a.Equal(tc.expectedUsers, resp.Users)
+				a.Equal(tc.expectedCursor, resp.NextCursor)
+			}
+		})
+	}
+}
+
+func TestService_SegmentExport(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name          string
+		slug          string
+		getReturn     *models.Segment
+		streamError   error
+		expectedError error
+
+		expectingGetCall    bool
+		expectingStreamCall bool
+	}{
+		{
+			name:                "Members exported",
+			slug:                "TEST_SLUG",
+			getReturn:           &models.Segment{Slug: "TEST_SLUG"},
+			expectingGetCall:    true,
+			expectingStreamCall: true,
+		},
+		{
+			name:             "Segment not found",
+			slug:             "TEST_SLUG",
+			expectedError:    errors.ErrSegmentNotFound,
+			expectingGetCall: true,
+		},
+		{
+			name:                "DB error",
+			slug:                "TEST_SLUG",
+			getReturn:           &models.Segment{Slug: "TEST_SLUG"},
+			streamError:         pgx.ErrTxClosed,
+			expectedError:       pgx.ErrTxClosed,
+			expectingGetCall:    true,
+			expectingStreamCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			segmentRepo := NewMockSegmentRepository(ctrl)
+
+			if tc.expectingGetCall {
+				segmentRepo.EXPECT().Get(context.Background(), tc.slug).Return(tc.getReturn, nil)
+			}
+
+			if tc.expectingStreamCall {
+				segmentRepo.EXPECT().StreamMembers(context.Background(), tc.slug, gomock.Any()).Return(tc.streamError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			err := serv.SegmentExport(context.Background(), tc.slug, func(member models.SegmentMember) error { return nil })
+
+			a.Equal(tc.expectedError, err)
+		})
+	}
+}
@@ -2,41 +2,183 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/dupreehkuda/avito-segments/internal/config"
 	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/worker"
 )
 
 //go:generate mockgen -source=service.go -destination=mock_test.go -package=service_test
 
 type UserRepository interface {
-	SetSegments(ctx context.Context, segments *models.UserSetRequest) error
-	DeleteSegments(ctx context.Context, segments *models.UserDeleteRequest) error
+	SetSegments(ctx context.Context, segments *models.UserSetRequest, audit *models.AuditEntry) error
+	DeleteSegments(ctx context.Context, segments *models.UserDeleteRequest, audit *models.AuditEntry) error
+	UpdateSegments(ctx context.Context, req *models.UserUpdateRequest, audit *models.AuditEntry) error
+	SetSegmentsBulk(ctx context.Context, reqs []models.UserSetRequest, audit []models.AuditEntry) error
 	GetSegments(ctx context.Context, userID string) (*models.UserResponse, error)
+	GetSegmentsAt(ctx context.Context, userID string, at time.Time) (*models.UserResponse, error)
 
-	GetReportData(ctx context.Context, year, month int) ([]models.ReportRow, error)
+	ReportRows(ctx context.Context, filter *models.ReportFilter) (models.ReportCursor, error)
 }
 
 type SegmentRepository interface {
-	Add(ctx context.Context, segment *models.Segment) error
-	Delete(ctx context.Context, slug string) error
+	Add(ctx context.Context, segment *models.Segment, audit *models.AuditEntry) error
+	Delete(ctx context.Context, slug string, audit *models.AuditEntry) error
+	Update(ctx context.Context, slug string, req *models.SegmentUpdateRequest, audit *models.AuditEntry) error
+	Restore(ctx context.Context, slug string, restoreMembers bool, audit *models.AuditEntry) error
 	Get(ctx context.Context, slug string) (*models.Segment, error)
-	Count(ctx context.Context, slugs []string) (int, error)
+	Existing(ctx context.Context, slugs []string) ([]string, error)
+	List(ctx context.Context, filter *models.SegmentFilter) ([]models.SegmentInfo, int, error)
+	Info(ctx context.Context, slug string) (*models.SegmentInfo, error)
+	Members(ctx context.Context, slug, after string, limit int) ([]models.SegmentMember, error)
+	StreamMembers(ctx context.Context, slug string, fn func(member models.SegmentMember) error) error
+	DailyStats(ctx context.Context, slug string, from, to time.Time) ([]models.SegmentDayStats, error)
+	Ranking(ctx context.Context, limit, offset int) ([]models.SegmentInfo, int, error)
 }
 
+type JobRepository interface {
+	CreateImport(ctx context.Context, job *models.ImportJob) error
+	StartImport(ctx context.Context, id string, now time.Time) error
+	ProgressImport(ctx context.Context, id string, processed int, rowErrors []models.ImportRowError) error
+	FinishImport(ctx context.Context, id, status, message string, now time.Time) error
+	GetImport(ctx context.Context, id string, limit, offset int) (*models.ImportJob, error)
+	HeartbeatImports(ctx context.Context, ids []string, now time.Time) error
+	ClaimStaleImports(ctx context.Context, staleBefore, now time.Time) ([]models.ImportJob, error)
+
+	CreateReportJob(ctx context.Context, job *models.ReportJob) error
+	StartReportJob(ctx context.Context, id string, now time.Time) error
+	ProgressReportJob(ctx context.Context, id string, rows int) error
+	FinishReportJob(ctx context.Context, job *models.ReportJob) error
+	GetReportJob(ctx context.Context, id string) (*models.ReportJob, error)
+	LatestReportJob(ctx context.Context, reportID, format string) (*models.ReportJob, error)
+	PendingReportJob(ctx context.Context, reportID, format string, staleBefore time.Time) (*models.ReportJob, error)
+	HeartbeatReportJobs(ctx context.Context, ids []string, now time.Time) error
+	ClaimStaleReportJobs(ctx context.Context, staleBefore, now time.Time) ([]models.ReportJob, error)
+}
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, hook *models.Webhook, audit *models.AuditEntry) error
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id string) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string, audit *models.AuditEntry) error
+	Deliveries(ctx context.Context, webhookID string, filter *models.DeliveryFilter) ([]models.WebhookDelivery, int, error)
+	Delivery(ctx context.Context, webhookID string, id int64) (*models.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, id int64, now time.Time) error
+}
+
+type EventRepository interface {
+	Events(ctx context.Context, filter *models.EventFilter) ([]models.StreamEvent, error)
+	EventsHead(ctx context.Context) (models.EventPosition, error)
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *models.APIKey, audit *models.AuditEntry) error
+	ListAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string, now time.Time, audit *models.AuditEntry) (bool, error)
+}
+
+type AuditRepository interface {
+	AuditLog(ctx context.Context, filter *models.AuditFilter) ([]models.AuditEntry, int, error)
+}
+
+// ReportStorage keeps generated report files and uploaded import files.
+type ReportStorage interface {
+	Put(ctx context.Context, name string, fn func(w io.Writer) error) error
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	Exists(ctx context.Context, name string) (bool, error)
+	URL(ctx context.Context, name string) (string, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// Scheduler runs tasks in background.
+type Scheduler interface {
+	Submit(task worker.Task) error
+}
+
+const (
+	// MaxPageLimit is the biggest page size for list requests.
+	MaxPageLimit = 1000
+	// MaxBulkSize is the biggest amount of users in one bulk request.
+	MaxBulkSize = 10000
+	// ImportChunkSize is the amount of imported rows written at once.
+	ImportChunkSize = 1000
+	// JobLease is the time a background job stays with its instance without heartbeat,
+	// after that the job is taken as abandoned and is taken over.
+	JobLease = 2 * time.Minute
+	// ReportStaleAfter is the age after which report for a period that is not over yet is regenerated.
+	ReportStaleAfter = 15 * time.Minute
+	// ReportSettleMargin is added to expiry interval to get the time after which period's history is settled.
+	ReportSettleMargin = time.Minute
+	// ReportProgressEvery is the amount of written report rows between progress updates.
+	ReportProgressEvery = 10000
+	// MinSecretLength is the shortest webhook secret accepted from client.
+	MinSecretLength = 16
+	// MaxKeyNameLength is the longest api key name.
+	MaxKeyNameLength = 100
+	// EventPollInterval is the pause between event stream polls when there was nothing to send.
+	EventPollInterval = 500 * time.Millisecond
+	// EventBatchSize is the most events read from the stream at once.
+	EventBatchSize = 500
+)
+
 // Service provides service's business-logic.
 type Service struct {
-	userRepo    UserRepository
-	segmentRepo SegmentRepository
-	logger      *zap.Logger
+	userRepo     UserRepository
+	segmentRepo  SegmentRepository
+	jobRepo      JobRepository
+	webhookRepo  WebhookRepository
+	eventRepo    EventRepository
+	keyRepo      APIKeyRepository
+	auditRepo    AuditRepository
+	storage      ReportStorage
+	scheduler    Scheduler
+	imports      *jobSet
+	reports      *jobSet
+	reportSettle time.Duration
+	// allowPrivateHooks lets webhooks point to internal addresses.
+	allowPrivateHooks bool
+	logger            *zap.Logger
 }
 
 // New creates new instance of service.
-func New(userRepo UserRepository, segmentRepo SegmentRepository, logger *zap.Logger) *Service {
+func New(
+	userRepo UserRepository,
+	segmentRepo SegmentRepository,
+	jobRepo JobRepository,
+	webhookRepo WebhookRepository,
+	eventRepo EventRepository,
+	keyRepo APIKeyRepository,
+	auditRepo AuditRepository,
+	storage ReportStorage,
+	scheduler Scheduler,
+	config *config.Config,
+	logger *zap.Logger,
+) *Service {
+	// Events dated inside a period are written after it is over until expiry worker catches up,
+	// which takes up to its interval.
+	expiryInterval := config.Workers.ExpiryInterval
+	if expiryInterval <= 0 {
+		expiryInterval = worker.DefaultExpiryInterval
+	}
+
 	return &Service{
-		userRepo:    userRepo,
-		segmentRepo: segmentRepo,
-		logger:      logger,
+		userRepo:          userRepo,
+		segmentRepo:       segmentRepo,
+		jobRepo:           jobRepo,
+		webhookRepo:       webhookRepo,
+		eventRepo:         eventRepo,
+		keyRepo:           keyRepo,
+		auditRepo:         auditRepo,
+		storage:           storage,
+		scheduler:         scheduler,
+		imports:           newJobSet(),
+		reports:           newJobSet(),
+		reportSettle:      expiryInterval + ReportSettleMargin,
+		allowPrivateHooks: config.Webhooks.AllowPrivate,
+		logger:            logger,
 	}
 }
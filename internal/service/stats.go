@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+const (
+	// DefaultStatsDays is the amount of days covered by segment stats when range is not set.
+	DefaultStatsDays = 30
+	// MaxStatsDays is the longest range of segment stats.
+	MaxStatsDays = 366
+)
+
+const day = 24 * time.Hour
+
+// SegmentStats returns current amount of segment members and its daily changes over [from, to).
+// Range is aligned to UTC days, zero bounds mean the last DefaultStatsDays days including today.
+func (s *Service) SegmentStats(ctx context.Context, slug string, from, to time.Time) (*models.SegmentStats, error) {
+	if !IsValidSlug(slug) {
+		return nil, errors.ErrInvalidSegmentSlug
+	}
+
+	from, to, err := statsRange(from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.segmentRepo.Info(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if info == nil {
+		return nil, errors.ErrSegmentNotFound
+	}
+
+	days, err := s.segmentRepo.DailyStats(ctx, slug, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.SegmentStats{
+		Slug:   slug,
+		Active: info.Members,
+		From:   from,
+		To:     to,
+		Days:   days,
+	}
+
+	for _, daily := range days {
+		stats.Added += daily.Added
+		stats.Deleted += daily.Deleted
+		stats.Expired += daily.Expired
+		stats.NetGrowth += daily.Net
+	}
+
+	return stats, nil
+}
+
+// SegmentRanking returns a page of active segments ordered by amount of members, the biggest first.
+func (s *Service) SegmentRanking(ctx context.Context, limit, offset int) (*models.SegmentListResponse, error) {
+	if limit < 1 || limit > MaxPageLimit || offset < 0 {
+		return nil, errors.ErrInvalidPagination
+	}
+
+	segments, total, err := s.segmentRepo.Ranking(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SegmentListResponse{
+		Segments: segments,
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+	}, nil
+}
+
+// statsRange aligns stats range to UTC days and checks its length.
+func statsRange(from, to, now time.Time) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = now.UTC().Truncate(day).Add(day)
+	}
+
+	if from.IsZero() {
+		from = to.UTC().Truncate(day).Add(-DefaultStatsDays * day)
+	}
+
+	from, to = from.UTC().Truncate(day), to.UTC().Truncate(day)
+
+	if !from.Before(to) || to.Sub(from) > MaxStatsDays*day {
+		return time.Time{}, time.Time{}, errors.ErrInvalidPeriod
+	}
+
+	return from, to, nil
+}
@@ -0,0 +1,233 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/service"
+)
+
+func TestService_SegmentStats(t *testing.T) {
+	a := assert.New(t)
+
+	var (
+		augustFirst  = time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC)
+		augustSecond = time.Date(2023, time.August, 2, 0, 0, 0, 0, time.UTC)
+		augustThird  = time.Date(2023, time.August, 3, 0, 0, 0, 0, time.UTC)
+		today        = time.Now().UTC().Truncate(24 * time.Hour)
+	)
+
+	testCases := []struct {
+		name string
+		slug string
+		from time.Time
+		to   time.Time
+
+		infoReturn *models.SegmentInfo
+		infoError  error
+		daysReturn []models.SegmentDayStats
+		daysError  error
+
+		expectedFrom  time.Time
+		expectedTo    time.Time
+		expectedRes   *models.SegmentStats
+		expectedError error
+
+		expectingInfoCall bool
+		expectingDaysCall bool
+	}{
+		{
+			name:       "Stats counted",
+			slug:       "AVITO_TEST",
+			from:       augustFirst,
+			to:         augustThird.Add(time.Hour),
+			infoReturn: &models.SegmentInfo{Slug: "AVITO_TEST", Members: 10},
+			daysReturn: []models.SegmentDayStats{
+				{Day: augustFirst, Added: 5, Deleted: 1, Expired: 1, Net: 3},
+				{Day: augustSecond, Added: 1, Deleted: 2, Net: -1},
+			},
+			expectedFrom: augustFirst,
+			expectedTo:   augustThird,
+			expectedRes: &models.SegmentStats{
+				Slug:      "AVITO_TEST",
+				Active:    10,
+				From:      augustFirst,
+				To:        augustThird,
+				Added:     6,
+				Deleted:   3,
+				Expired:   1,
+				NetGrowth: 2,
+				Days: []models.SegmentDayStats{
+					{Day: augustFirst, Added: 5, Deleted: 1, Expired: 1, Net: 3},
+					{Day: augustSecond, Added: 1, Deleted: 2, Net: -1},
+				},
+			},
+			expectingInfoCall: true,
+			expectingDaysCall: true,
+		},
+		{
+			name:         "Last days by default",
+			slug:         "AVITO_TEST",
+			infoReturn:   &models.SegmentInfo{Slug: "AVITO_TEST"},
+			daysReturn:   []models.SegmentDayStats{},
+			expectedFrom: today.Add(-(service.DefaultStatsDays - 1) * 24 * time.Hour),
+			expectedTo:   today.Add(24 * time.Hour),
+			expectedRes: &models.SegmentStats{
+				Slug: "AVITO_TEST",
+				From: today.Add(-(service.DefaultStatsDays - 1) * 24 * time.Hour),
+				To:   today.Add(24 * time.Hour),
+				Days: []models.SegmentDayStats{},
+			},
+			expectingInfoCall: true,
+			expectingDaysCall: true,
+		},
+		{
+			name:          "Invalid slug",
+			slug:          "avito",
+			expectedError: errors.ErrInvalidSegmentSlug,
+		},
+		{
+			name:          "Empty range",
+			slug:          "AVITO_TEST",
+			from:          augustSecond,
+			to:            augustSecond.Add(time.Hour),
+			expectedError: errors.ErrInvalidPeriod,
+		},
+		{
+			name:          "Range is too long",
+			slug:          "AVITO_TEST",
+			from:          augustFirst.AddDate(-2, 0, 0),
+			to:            augustFirst,
+			expectedError: errors.ErrInvalidPeriod,
+		},
+		{
+			name:              "Segment not found",
+			slug:              "AVITO_TEST",
+			from:              augustFirst,
+			to:                augustThird,
+			expectedError:     errors.ErrSegmentNotFound,
+			expectingInfoCall: true,
+		},
+		{
+			name:              "Some internal error",
+			slug:              "AVITO_TEST",
+			from:              augustFirst,
+			to:                augustThird,
+			infoReturn:        &models.SegmentInfo{Slug: "AVITO_TEST"},
+			daysError:         os.ErrInvalid,
+			expectedFrom:      augustFirst,
+			expectedTo:        augustThird,
+			expectedError:     os.ErrInvalid,
+			expectingInfoCall: true,
+			expectingDaysCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			segmentRepo := NewMockSegmentRepository(ctrl)
+
+			if tc.expectingInfoCall {
+				segmentRepo.EXPECT().Info(context.Background(), tc.slug).Return(tc.infoReturn, tc.infoError)
+			}
+
+			if tc.expectingDaysCall {
+				segmentRepo.EXPECT().DailyStats(context.Background(), tc.slug, tc.expectedFrom, tc.expectedTo).Return(tc.daysReturn, tc.daysError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			res, err := serv.SegmentStats(context.Background(), tc.slug, tc.from, tc.to)
+
+			a.Equal(tc.expectedError, err)
+			a.Equal(tc.expectedRes, res)
+		})
+	}
+}
+
+func TestService_SegmentRanking(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name   string
+		limit  int
+		offset int
+
+		rankingReturn []models.SegmentInfo
+		rankingError  error
+
+		expectedRes   *models.SegmentListResponse
+		expectedError error
+
+		expectingRankingCall bool
+	}{
+		{
+			name:  "Segments ranked",
+			limit: 2,
+			rankingReturn: []models.SegmentInfo{
+				{Slug: "AVITO_VOICE", Members: 100},
+				{Slug: "AVITO_TEST", Members: 10},
+			},
+			expectedRes: &models.SegmentListResponse{
+				Segments: []models.SegmentInfo{
+					{Slug: "AVITO_VOICE", Members: 100},
+					{Slug: "AVITO_TEST", Members: 10},
+				},
+				Total: 3,
+				Limit: 2,
+			},
+			expectingRankingCall: true,
+		},
+		{
+			name:          "Invalid pagination",
+			limit:         service.MaxPageLimit + 1,
+			expectedError: errors.ErrInvalidPagination,
+		},
+		{
+			name:                 "Some internal error",
+			limit:                10,
+			rankingError:         os.ErrInvalid,
+			expectedError:        os.ErrInvalid,
+			expectingRankingCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			segmentRepo := NewMockSegmentRepository(ctrl)
+
+			if tc.expectingRankingCall {
+				total := 0
+				if tc.expectedRes != nil {
+					total = tc.expectedRes.Total
+				}
+
+				segmentRepo.EXPECT().Ranking(context.Background(), tc.limit, tc.offset).Return(tc.rankingReturn, total, tc.rankingError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			res, err := serv.SegmentRanking(context.Background(), tc.limit, tc.offset)
+
+			a.Equal(tc.expectedError, err)
+			a.Equal(tc.expectedRes, res)
+		})
+	}
+}
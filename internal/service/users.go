@@ -2,15 +2,14 @@ package service
 
 import (
 	"context"
-	"encoding/csv"
-	"fmt"
-	"os"
 	"time"
 
 	"github.com/dupreehkuda/avito-segments/internal/errors"
 	"github.com/dupreehkuda/avito-segments/internal/models"
 )
 
+// UserSetSegments adds user to the segments. Membership events are written to outbox and
+// queued as deliveries of subscribed webhooks in the same transaction as the change itself.
 func (s *Service) UserSetSegments(ctx context.Context, req *models.UserSetRequest) error {
 	var slugs = make([]string, 0, len(req.Segments))
 
@@ -22,16 +21,12 @@ func (s *Service) UserSetSegments(ctx context.Context, req *models.UserSetReques
 		slugs = append(slugs, segment.Slug)
 	}
 
-	count, err := s.segmentRepo.Count(ctx, slugs)
+	err := s.segmentsExist(ctx, slugs)
 	if err != nil {
 		return err
 	}
 
-	if len(req.Segments) != count {
-		return errors.ErrSegmentsNotFound
-	}
-
-	err = s.userRepo.SetSegments(ctx, req)
+	err = s.userRepo.SetSegments(ctx, req, auditEntry(ctx, models.AuditUserSetSegments, req.UserID, slugs))
 	if err != nil {
 		return err
 	}
@@ -39,23 +34,20 @@ func (s *Service) UserSetSegments(ctx context.Context, req *models.UserSetReques
 	return nil
 }
 
+// UserDeleteSegments removes user from the segments, notifying outbox and webhooks like UserSetSegments.
 func (s *Service) UserDeleteSegments(ctx context.Context, req *models.UserDeleteRequest) error {
 	for _, segment := range req.Slugs {
 		if !IsValidSlug(segment) {
-			return errors.ErrInvalidSegmentSlug
+			return errors.WithSlugs(errors.ErrInvalidSegmentSlug, segment)
 		}
 	}
 
-	count, err := s.segmentRepo.Count(ctx, req.Slugs)
+	err := s.segmentsExist(ctx, req.Slugs)
 	if err != nil {
 		return err
 	}
 
-	if len(req.Slugs) != count {
-		return errors.ErrSegmentsNotFound
-	}
-
-	err = s.userRepo.DeleteSegments(ctx, req)
+	err = s.userRepo.DeleteSegments(ctx, req, auditEntry(ctx, models.AuditUserDeleteSegments, req.UserID, req.Slugs))
 	if err != nil {
 		return err
 	}
@@ -63,6 +55,131 @@ func (s *Service) UserDeleteSegments(ctx context.Context, req *models.UserDelete
 	return nil
 }
 
+// UserUpdateSegments adds and removes user's segments at once.
+// Request is validated as a whole and applied atomically.
+func (s *Service) UserUpdateSegments(ctx context.Context, req *models.UserUpdateRequest) error {
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		return errors.ErrNoSegmentsProvided
+	}
+
+	var (
+		added = make(map[string]struct{}, len(req.Add))
+		slugs = make([]string, 0, len(req.Add)+len(req.Remove))
+	)
+
+	for _, segment := range req.Add {
+		if err := IsValidSegment(segment); err != nil {
+			return err
+		}
+
+		if _, ok := added[segment.Slug]; !ok {
+			added[segment.Slug] = struct{}{}
+			slugs = append(slugs, segment.Slug)
+		}
+	}
+
+	removed := make(map[string]struct{}, len(req.Remove))
+
+	for _, slug := range req.Remove {
+		if !IsValidSlug(slug) {
+			return errors.WithSlugs(errors.ErrInvalidSegmentSlug, slug)
+		}
+
+		if _, ok := added[slug]; ok {
+			return errors.WithSlugs(errors.ErrConflictingSlugs, slug)
+		}
+
+		if _, ok := removed[slug]; !ok {
+			removed[slug] = struct{}{}
+			slugs = append(slugs, slug)
+		}
+	}
+
+	if err := s.segmentsExist(ctx, slugs); err != nil {
+		return err
+	}
+
+	return s.userRepo.UpdateSegments(ctx, req, auditEntry(ctx, models.AuditUserUpdateSegments, req.UserID, slugs))
+}
+
+// UserBulkSetSegments assigns segments to many users at once. Returned errors are aligned
+// with reqs: invalid entries are skipped and reported, the rest are stored together.
+func (s *Service) UserBulkSetSegments(ctx context.Context, reqs []models.UserSetRequest) ([]error, error) {
+	if len(reqs) > MaxBulkSize {
+		return nil, errors.ErrBatchTooLarge
+	}
+
+	var (
+		results = make([]error, len(reqs))
+		seen    = make(map[string]struct{})
+		slugs   = make([]string, 0)
+	)
+
+	for i, req := range reqs {
+		if len(req.Segments) == 0 {
+			results[i] = errors.ErrNoSegmentsProvided
+			continue
+		}
+
+		for _, segment := range req.Segments {
+			if err := IsValidSegment(segment); err != nil {
+				results[i] = err
+				break
+			}
+
+			if _, ok := seen[segment.Slug]; !ok {
+				seen[segment.Slug] = struct{}{}
+				slugs = append(slugs, segment.Slug)
+			}
+		}
+	}
+
+	if len(slugs) == 0 {
+		return results, nil
+	}
+
+	existing, err := s.segmentRepo.Existing(ctx, slugs)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]struct{}, len(existing))
+	for _, slug := range existing {
+		found[slug] = struct{}{}
+	}
+
+	valid := make([]models.UserSetRequest, 0, len(reqs))
+
+	for i, req := range reqs {
+		if results[i] != nil {
+			continue
+		}
+
+		for _, segment := range req.Segments {
+			if _, ok := found[segment.Slug]; !ok {
+				results[i] = errors.WithSlugs(errors.ErrSegmentsNotFound, segment.Slug)
+				break
+			}
+		}
+
+		if results[i] == nil {
+			valid = append(valid, req)
+		}
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	audit := bulkAuditEntries(auditEntry(ctx, models.AuditUserBulkSetSegments, "", nil), valid)
+
+	if err = s.userRepo.SetSegmentsBulk(ctx, valid, audit); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
 func (s *Service) UserGetSegments(ctx context.Context, userID string) (*models.UserResponse, error) {
 	resp, err := s.userRepo.GetSegments(ctx, userID)
 	if err != nil {
@@ -70,7 +187,7 @@ func (s *Service) UserGetSegments(ctx context.Context, userID string) (*models.U
 	}
 
 	if resp == nil {
-		return nil, errors.ErrUserNotFound
+		return nil, errors.WithUserIDs(errors.ErrUserNotFound, userID)
 	}
 
 	if resp.Slugs == nil || len(resp.Slugs) == 0 {
@@ -80,37 +197,26 @@ func (s *Service) UserGetSegments(ctx context.Context, userID string) (*models.U
 	return resp, nil
 }
 
-func (s *Service) CreateReport(ctx context.Context, year, month int) (string, error) {
-	if !IsValidReportTime(year, month) {
-		return "", errors.ErrInvalidPeriod
+// UserGetSegmentsAt returns segments user was in at the given moment in the past.
+func (s *Service) UserGetSegmentsAt(ctx context.Context, userID string, at time.Time) (*models.UserResponse, error) {
+	if at.After(time.Now()) {
+		return nil, errors.ErrInvalidPeriod
 	}
 
-	data, err := s.userRepo.GetReportData(ctx, year, month)
+	resp, err := s.userRepo.GetSegmentsAt(ctx, userID, at)
 	if err != nil {
-		return "", err
-	}
-
-	fileName := fmt.Sprintf("%v_%v_report.csv", month, year)
-
-	file, err := os.Create("reports/" + fileName)
-	if err != nil {
-		return "", fmt.Errorf("error creating CSV: %w", err)
+		return nil, err
 	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	for _, row := range data {
-		err = writer.Write([]string{row.UserID, row.Slug, row.Method, row.Timestamp.String()})
-		if err != nil {
-			return "", fmt.Errorf("error writing to CSV: %w", err)
-		}
+	if len(resp.Slugs) == 0 {
+		return nil, errors.ErrSegmentsNotFound
 	}
 
-	return fileName, nil
+	return resp, nil
 }
 
+// IsValidReportTime checks that month exists and has already started. Month starts in UTC,
+// as it is resolved into range in UTC, so the same rule applies to months and ranges.
 func IsValidReportTime(year, month int) bool {
 	now := time.Now()
 
@@ -122,12 +228,12 @@ func IsValidReportTime(year, month int) bool {
 		return false
 	}
 
-	return true
+	return !time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC).After(now)
 }
 
 func IsValidSegment(segment models.UserSegment) error {
 	if !IsValidSlug(segment.Slug) {
-		return errors.ErrInvalidSegmentSlug
+		return errors.WithSlugs(errors.ErrInvalidSegmentSlug, segment.Slug)
 	}
 
 	if segment.Expire.IsZero() {
@@ -135,7 +241,7 @@ func IsValidSegment(segment models.UserSegment) error {
 	}
 
 	if segment.Expire.Before(time.Now()) {
-		return errors.ErrAlreadyExpired
+		return errors.WithSlugs(errors.ErrAlreadyExpired, segment.Slug)
 	}
 
 	return nil
@@ -10,6 +10,7 @@ import (
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 
+	"github.com/dupreehkuda/avito-segments/internal/config"
 	"github.com/dupreehkuda/avito-segments/internal/errors"
 	"github.com/dupreehkuda/avito-segments/internal/models"
 	"github.com/dupreehkuda/avito-segments/internal/service"
@@ -106,7 +107,7 @@ func TestService_UserGetSegments(t *testing.T) {
 			}
 
 			zp, _ := zap.NewDevelopment()
-			serv := service.New(userRepo, segmentRepo, zp)
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
 
 			resp, err := serv.UserGetSegments(context.Background(), tc.inputBody)
 
@@ -116,6 +117,84 @@ func TestService_UserGetSegments(t *testing.T) {
 	}
 }
 
+func TestService_UserGetSegmentsAt(t *testing.T) {
+	a := assert.New(t)
+
+	moment := time.Date(2023, time.August, 26, 19, 00, 00, 00, time.UTC)
+
+	testCases := []struct {
+		name  string
+		input time.Time
+
+		repositoryReturn *models.UserResponse
+		repositoryError  error
+
+		expectedReturn *models.UserResponse
+		expectedError  error
+
+		expectingRepositoryCall bool
+	}{
+		{
+			name:  "Segments returned",
+			input: moment,
+			repositoryReturn: &models.UserResponse{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+				Slugs:  []string{"TEST_SLUG"},
+			},
+			expectedReturn: &models.UserResponse{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+				Slugs:  []string{"TEST_SLUG"},
+			},
+			expectingRepositoryCall: true,
+		},
+		{
+			name:  "No segments at that moment",
+			input: moment,
+			repositoryReturn: &models.UserResponse{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+				Slugs:  []string{},
+			},
+			expectedError:           errors.ErrSegmentsNotFound,
+			expectingRepositoryCall: true,
+		},
+		{
+			name:          "Moment in future",
+			input:         time.Now().Add(time.Hour),
+			expectedError: errors.ErrInvalidPeriod,
+		},
+		{
+			name:                    "Some internal error",
+			input:                   moment,
+			repositoryError:         os.ErrInvalid,
+			expectedError:           os.ErrInvalid,
+			expectingRepositoryCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := NewMockUserRepository(ctrl)
+			segmentRepo := NewMockSegmentRepository(ctrl)
+
+			if tc.expectingRepositoryCall {
+				userRepo.EXPECT().GetSegmentsAt(context.Background(), "80b0b88d-379e-11ee-8bf7-0242c0a80002", tc.input).
+					Return(tc.repositoryReturn, tc.repositoryError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			resp, err := serv.UserGetSegmentsAt(context.Background(), "80b0b88d-379e-11ee-8bf7-0242c0a80002", tc.input)
+
+			a.Equal(tc.expectedReturn, resp)
+			a.Equal(tc.expectedError, err)
+		})
+	}
+}
+
 func TestService_UserSetSegments(t *testing.T) {
 	a := assert.New(t)
 
@@ -123,16 +202,16 @@ func TestService_UserSetSegments(t *testing.T) {
 		name      string
 		inputBody *models.UserSetRequest
 
-		getCountInput  []string
-		getCountReturn int
-		getCountError  error
+		getExistingInput  []string
+		getExistingReturn []string
+		getExistingError  error
 
 		repositoryError error
 
 		expectedError error
 
-		expectingGetCountCall   bool
-		expectingRepositoryCall bool
+		expectingGetExistingCall bool
+		expectingRepositoryCall  bool
 	}{
 		{
 			name: "Segment created",
@@ -144,13 +223,13 @@ func TestService_UserSetSegments(t *testing.T) {
 					},
 				},
 			},
-			getCountInput:           []string{"TEST_SLUG"},
-			getCountReturn:          1,
-			getCountError:           nil,
-			repositoryError:         nil,
-			expectedError:           nil,
-			expectingGetCountCall:   true,
-			expectingRepositoryCall: true,
+			getExistingInput:         []string{"TEST_SLUG"},
+			getExistingReturn:        []string{"TEST_SLUG"},
+			getExistingError:         nil,
+			repositoryError:          nil,
+			expectedError:            nil,
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  true,
 		},
 		{
 			name: "Segment created w/ expire",
@@ -159,17 +238,17 @@ func TestService_UserSetSegments(t *testing.T) {
 				Segments: []models.UserSegment{
 					{
 						Slug:   "TEST_SLUG",
-						Expire: time.Date(2024, time.August, 26, 19, 00, 00, 00, time.Local),
+						Expire: time.Date(2099, time.August, 26, 19, 00, 00, 00, time.Local),
 					},
 				},
 			},
-			getCountInput:           []string{"TEST_SLUG"},
-			getCountReturn:          1,
-			getCountError:           nil,
-			repositoryError:         nil,
-			expectedError:           nil,
-			expectingGetCountCall:   true,
-			expectingRepositoryCall: true,
+			getExistingInput:         []string{"TEST_SLUG"},
+			getExistingReturn:        []string{"TEST_SLUG"},
+			getExistingError:         nil,
+			repositoryError:          nil,
+			expectedError:            nil,
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  true,
 		},
 		{
 			name: "Invalid slug",
@@ -184,12 +263,11 @@ func TestService_UserSetSegments(t *testing.T) {
 					},
 				},
 			},
-			getCountReturn:          0,
-			getCountError:           nil,
-			repositoryError:         nil,
-			expectedError:           errors.ErrInvalidSegmentSlug,
-			expectingGetCountCall:   false,
-			expectingRepositoryCall: false,
+			getExistingError:         nil,
+			repositoryError:          nil,
+			expectedError:            errors.WithSlugs(errors.ErrInvalidSegmentSlug, "TEST_SLUG-2"),
+			expectingGetExistingCall: false,
+			expectingRepositoryCall:  false,
 		},
 		{
 			name: "Already expired",
@@ -205,12 +283,11 @@ func TestService_UserSetSegments(t *testing.T) {
 					},
 				},
 			},
-			getCountReturn:          0,
-			getCountError:           nil,
-			repositoryError:         nil,
-			expectedError:           errors.ErrAlreadyExpired,
-			expectingGetCountCall:   false,
-			expectingRepositoryCall: false,
+			getExistingError:         nil,
+			repositoryError:          nil,
+			expectedError:            errors.WithSlugs(errors.ErrAlreadyExpired, "TEST_SLUG_2"),
+			expectingGetExistingCall: false,
+			expectingRepositoryCall:  false,
 		},
 		{
 			name: "Count mismatch",
@@ -225,13 +302,13 @@ func TestService_UserSetSegments(t *testing.T) {
 					},
 				},
 			},
-			getCountInput:           []string{"TEST_SLUG", "TEST_SLUG_2"},
-			getCountReturn:          1,
-			getCountError:           nil,
-			repositoryError:         nil,
-			expectedError:           errors.ErrSegmentsNotFound,
-			expectingGetCountCall:   true,
-			expectingRepositoryCall: false,
+			getExistingInput:         []string{"TEST_SLUG", "TEST_SLUG_2"},
+			getExistingReturn:        []string{"TEST_SLUG"},
+			getExistingError:         nil,
+			repositoryError:          nil,
+			expectedError:            errors.WithSlugs(errors.ErrSegmentsNotFound, "TEST_SLUG_2"),
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  false,
 		},
 		{
 			name: "Some internal error",
@@ -243,13 +320,12 @@ func TestService_UserSetSegments(t *testing.T) {
 					},
 				},
 			},
-			getCountInput:           []string{"TEST_SLUG"},
-			getCountReturn:          0,
-			getCountError:           os.ErrInvalid,
-			repositoryError:         nil,
-			expectedError:           os.ErrInvalid,
-			expectingGetCountCall:   true,
-			expectingRepositoryCall: false,
+			getExistingInput:         []string{"TEST_SLUG"},
+			getExistingError:         os.ErrInvalid,
+			repositoryError:          nil,
+			expectedError:            os.ErrInvalid,
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  false,
 		},
 		{
 			name: "Some internal error",
@@ -261,13 +337,13 @@ func TestService_UserSetSegments(t *testing.T) {
 					},
 				},
 			},
-			getCountInput:           []string{"TEST_SLUG"},
-			getCountReturn:          1,
-			getCountError:           nil,
-			repositoryError:         os.ErrInvalid,
-			expectedError:           os.ErrInvalid,
-			expectingGetCountCall:   true,
-			expectingRepositoryCall: true,
+			getExistingInput:         []string{"TEST_SLUG"},
+			getExistingReturn:        []string{"TEST_SLUG"},
+			getExistingError:         nil,
+			repositoryError:          os.ErrInvalid,
+			expectedError:            os.ErrInvalid,
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  true,
 		},
 	}
 
@@ -279,16 +355,16 @@ func TestService_UserSetSegments(t *testing.T) {
 			userRepo := NewMockUserRepository(ctrl)
 			segmentRepo := NewMockSegmentRepository(ctrl)
 
-			if tc.expectingGetCountCall {
-				segmentRepo.EXPECT().Count(context.Background(), tc.getCountInput).Return(tc.getCountReturn, tc.getCountError)
+			if tc.expectingGetExistingCall {
+				segmentRepo.EXPECT().Existing(context.Background(), tc.getExistingInput).Return(tc.getExistingReturn, tc.getExistingError)
 			}
 
 			if tc.expectingRepositoryCall {
-				userRepo.EXPECT().SetSegments(context.Background(), tc.inputBody).Return(tc.repositoryError)
+				userRepo.EXPECT().SetSegments(context.Background(), tc.inputBody, gomock.Any()).Return(tc.repositoryError)
 			}
 
 			zp, _ := zap.NewDevelopment()
-			serv := service.New(userRepo, segmentRepo, zp)
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
 
 			err := serv.UserSetSegments(context.Background(), tc.inputBody)
 
@@ -304,15 +380,15 @@ func TestService_UserDeleteSegments(t *testing.T) {
 		name      string
 		inputBody *models.UserDeleteRequest
 
-		getCountReturn int
-		getCountError  error
+		getExistingReturn []string
+		getExistingError  error
 
 		repositoryError error
 
 		expectedError error
 
-		expectingGetCountCall   bool
-		expectingRepositoryCall bool
+		expectingGetExistingCall bool
+		expectingRepositoryCall  bool
 	}{
 		{
 			name: "Segment created",
@@ -320,12 +396,12 @@ func TestService_UserDeleteSegments(t *testing.T) {
 				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
 				Slugs:  []string{"TEST_SLUG"},
 			},
-			getCountReturn:          1,
-			getCountError:           nil,
-			repositoryError:         nil,
-			expectedError:           nil,
-			expectingGetCountCall:   true,
-			expectingRepositoryCall: true,
+			getExistingReturn:        []string{"TEST_SLUG"},
+			getExistingError:         nil,
+			repositoryError:          nil,
+			expectedError:            nil,
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  true,
 		},
 		{
 			name: "Invalid slug",
@@ -333,12 +409,11 @@ func TestService_UserDeleteSegments(t *testing.T) {
 				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
 				Slugs:  []string{"TEST_SLUG", "TEST_SLUG-2"},
 			},
-			getCountReturn:          0,
-			getCountError:           nil,
-			repositoryError:         nil,
-			expectedError:           errors.ErrInvalidSegmentSlug,
-			expectingGetCountCall:   false,
-			expectingRepositoryCall: false,
+			getExistingError:         nil,
+			repositoryError:          nil,
+			expectedError:            errors.WithSlugs(errors.ErrInvalidSegmentSlug, "TEST_SLUG-2"),
+			expectingGetExistingCall: false,
+			expectingRepositoryCall:  false,
 		},
 		{
 			name: "Count mismatch",
@@ -346,12 +421,12 @@ func TestService_UserDeleteSegments(t *testing.T) {
 				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
 				Slugs:  []string{"TEST_SLUG", "TEST_SLUG_2"},
 			},
-			getCountReturn:          1,
-			getCountError:           nil,
-			repositoryError:         nil,
-			expectedError:           errors.ErrSegmentsNotFound,
-			expectingGetCountCall:   true,
-			expectingRepositoryCall: false,
+			getExistingReturn:        []string{"TEST_SLUG"},
+			getExistingError:         nil,
+			repositoryError:          nil,
+			expectedError:            errors.WithSlugs(errors.ErrSegmentsNotFound, "TEST_SLUG_2"),
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  false,
 		},
 		{
 			name: "Some internal error",
@@ -359,12 +434,11 @@ func TestService_UserDeleteSegments(t *testing.T) {
 				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
 				Slugs:  []string{"TEST_SLUG"},
 			},
-			getCountReturn:          0,
-			getCountError:           os.ErrInvalid,
-			repositoryError:         nil,
-			expectedError:           os.ErrInvalid,
-			expectingGetCountCall:   true,
-			expectingRepositoryCall: false,
+			getExistingError:         os.ErrInvalid,
+			repositoryError:          nil,
+			expectedError:            os.ErrInvalid,
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  false,
 		},
 		{
 			name: "Some internal error",
@@ -372,11 +446,255 @@ func TestService_UserDeleteSegments(t *testing.T) {
 				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
 				Slugs:  []string{"TEST_SLUG"},
 			},
-			getCountReturn:          1,
-			getCountError:           nil,
+			getExistingReturn:        []string{"TEST_SLUG"},
+			getExistingError:         nil,
+			repositoryError:          os.ErrInvalid,
+			expectedError:            os.ErrInvalid,
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := NewMockUserRepository(ctrl)
+			segmentRepo := NewMockSegmentRepository(ctrl)
+
+			if tc.expectingGetExistingCall {
+				segmentRepo.EXPECT().Existing(context.Background(), tc.inputBody.Slugs).Return(tc.getExistingReturn, tc.getExistingError)
+			}
+
+			if tc.expectingRepositoryCall {
+				userRepo.EXPECT().DeleteSegments(context.Background(), tc.inputBody, gomock.Any()).Return(tc.repositoryError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			err := serv.UserDeleteSegments(context.Background(), tc.inputBody)
+
+			a.Equal(tc.expectedError, err)
+		})
+	}
+}
+
+func TestService_UserUpdateSegments(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name      string
+		inputBody *models.UserUpdateRequest
+
+		getExistingInput  []string
+		getExistingReturn []string
+		getExistingError  error
+
+		repositoryError error
+
+		expectedError error
+
+		expectingGetExistingCall bool
+		expectingRepositoryCall  bool
+	}{
+		{
+			name: "Segments moved",
+			inputBody: &models.UserUpdateRequest{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+				Add: []models.UserSegment{
+					{Slug: "TEST_SLUG_B", Expire: time.Date(2099, time.August, 26, 19, 00, 00, 00, time.Local)},
+				},
+				Remove: []string{"TEST_SLUG_A"},
+			},
+			getExistingInput:         []string{"TEST_SLUG_B", "TEST_SLUG_A"},
+			getExistingReturn:        []string{"TEST_SLUG_B", "TEST_SLUG_A"},
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  true,
+		},
+		{
+			name: "Only removal w/ duplicates",
+			inputBody: &models.UserUpdateRequest{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+				Remove: []string{"TEST_SLUG_A", "TEST_SLUG_A"},
+			},
+			getExistingInput:         []string{"TEST_SLUG_A"},
+			getExistingReturn:        []string{"TEST_SLUG_A"},
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  true,
+		},
+		{
+			name: "Nothing provided",
+			inputBody: &models.UserUpdateRequest{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+			},
+			expectedError: errors.ErrNoSegmentsProvided,
+		},
+		{
+			name: "Same slug added and removed",
+			inputBody: &models.UserUpdateRequest{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+				Add:    []models.UserSegment{{Slug: "TEST_SLUG_A"}},
+				Remove: []string{"TEST_SLUG_A"},
+			},
+			expectedError: errors.WithSlugs(errors.ErrConflictingSlugs, "TEST_SLUG_A"),
+		},
+		{
+			name: "Invalid slug to remove",
+			inputBody: &models.UserUpdateRequest{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+				Add:    []models.UserSegment{{Slug: "TEST_SLUG_A"}},
+				Remove: []string{"test-slug"},
+			},
+			expectedError: errors.WithSlugs(errors.ErrInvalidSegmentSlug, "test-slug"),
+		},
+		{
+			name: "Already expired",
+			inputBody: &models.UserUpdateRequest{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+				Add: []models.UserSegment{
+					{Slug: "TEST_SLUG_A", Expire: time.Date(2022, time.August, 26, 19, 00, 00, 00, time.Local)},
+				},
+			},
+			expectedError: errors.WithSlugs(errors.ErrAlreadyExpired, "TEST_SLUG_A"),
+		},
+		{
+			name: "Count mismatch",
+			inputBody: &models.UserUpdateRequest{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+				Add:    []models.UserSegment{{Slug: "TEST_SLUG_B"}},
+				Remove: []string{"TEST_SLUG_A"},
+			},
+			getExistingInput:         []string{"TEST_SLUG_B", "TEST_SLUG_A"},
+			getExistingReturn:        []string{"TEST_SLUG_B"},
+			expectedError:            errors.WithSlugs(errors.ErrSegmentsNotFound, "TEST_SLUG_A"),
+			expectingGetExistingCall: true,
+		},
+		{
+			name: "Some internal error",
+			inputBody: &models.UserUpdateRequest{
+				UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+				Add:    []models.UserSegment{{Slug: "TEST_SLUG_B"}},
+			},
+			getExistingInput:         []string{"TEST_SLUG_B"},
+			getExistingReturn:        []string{"TEST_SLUG_B"},
+			repositoryError:          os.ErrInvalid,
+			expectedError:            os.ErrInvalid,
+			expectingGetExistingCall: true,
+			expectingRepositoryCall:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := NewMockUserRepository(ctrl)
+			segmentRepo := NewMockSegmentRepository(ctrl)
+
+			if tc.expectingGetExistingCall {
+				segmentRepo.EXPECT().Existing(context.Background(), tc.getExistingInput).Return(tc.getExistingReturn, tc.getExistingError)
+			}
+
+			if tc.expectingRepositoryCall {
+				userRepo.EXPECT().UpdateSegments(context.Background(), tc.inputBody, gomock.Any()).Return(tc.repositoryError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			err := serv.UserUpdateSegments(context.Background(), tc.inputBody)
+
+			a.Equal(tc.expectedError, err)
+		})
+	}
+}
+
+func TestService_UserBulkSetSegments(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name      string
+		inputBody []models.UserSetRequest
+
+		existingInput  []string
+		existingReturn []string
+		existingError  error
+
+		repositoryInput []models.UserSetRequest
+		repositoryError error
+
+		expectedResults []error
+		expectedError   error
+
+		expectingExistingCall   bool
+		expectingRepositoryCall bool
+	}{
+		{
+			name: "All users stored",
+			inputBody: []models.UserSetRequest{
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Segments: []models.UserSegment{{Slug: "TEST_SLUG_A"}}},
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80003", Segments: []models.UserSegment{{Slug: "TEST_SLUG_A"}, {Slug: "TEST_SLUG_B"}}},
+			},
+			existingInput:  []string{"TEST_SLUG_A", "TEST_SLUG_B"},
+			existingReturn: []string{"TEST_SLUG_A", "TEST_SLUG_B"},
+			repositoryInput: []models.UserSetRequest{
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Segments: []models.UserSegment{{Slug: "TEST_SLUG_A"}}},
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80003", Segments: []models.UserSegment{{Slug: "TEST_SLUG_A"}, {Slug: "TEST_SLUG_B"}}},
+			},
+			expectedResults:         []error{nil, nil},
+			expectingExistingCall:   true,
+			expectingRepositoryCall: true,
+		},
+		{
+			name: "Invalid entries skipped",
+			inputBody: []models.UserSetRequest{
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Segments: []models.UserSegment{{Slug: "TEST_SLUG_A"}}},
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80003"},
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80004", Segments: []models.UserSegment{{Slug: "test-slug"}}},
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80005", Segments: []models.UserSegment{{Slug: "TEST_SLUG_C"}}},
+			},
+			existingInput:  []string{"TEST_SLUG_A", "TEST_SLUG_C"},
+			existingReturn: []string{"TEST_SLUG_A"},
+			repositoryInput: []models.UserSetRequest{
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Segments: []models.UserSegment{{Slug: "TEST_SLUG_A"}}},
+			},
+			expectedResults: []error{
+				nil,
+				errors.ErrNoSegmentsProvided,
+				errors.WithSlugs(errors.ErrInvalidSegmentSlug, "test-slug"),
+				errors.WithSlugs(errors.ErrSegmentsNotFound, "TEST_SLUG_C"),
+			},
+			expectingExistingCall:   true,
+			expectingRepositoryCall: true,
+		},
+		{
+			name: "Nothing valid",
+			inputBody: []models.UserSetRequest{
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002"},
+			},
+			expectedResults: []error{errors.ErrNoSegmentsProvided},
+		},
+		{
+			name:          "Batch too large",
+			inputBody:     make([]models.UserSetRequest, service.MaxBulkSize+1),
+			expectedError: errors.ErrBatchTooLarge,
+		},
+		{
+			name: "Some internal error",
+			inputBody: []models.UserSetRequest{
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Segments: []models.UserSegment{{Slug: "TEST_SLUG_A"}}},
+			},
+			existingInput:  []string{"TEST_SLUG_A"},
+			existingReturn: []string{"TEST_SLUG_A"},
+			repositoryInput: []models.UserSetRequest{
+				{UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Segments: []models.UserSegment{{Slug: "TEST_SLUG_A"}}},
+			},
 			repositoryError:         os.ErrInvalid,
 			expectedError:           os.ErrInvalid,
-			expectingGetCountCall:   true,
+			expectingExistingCall:   true,
 			expectingRepositoryCall: true,
 		},
 	}
@@ -389,20 +707,21 @@ func TestService_UserDeleteSegments(t *testing.T) {
 			userRepo := NewMockUserRepository(ctrl)
 			segmentRepo := NewMockSegmentRepository(ctrl)
 
-			if tc.expectingGetCountCall {
-				segmentRepo.EXPECT().Count(context.Background(), tc.inputBody.Slugs).Return(tc.getCountReturn, tc.getCountError)
+			if tc.expectingExistingCall {
+				segmentRepo.EXPECT().Existing(context.Background(), tc.existingInput).Return(tc.existingReturn, tc.existingError)
 			}
 
 			if tc.expectingRepositoryCall {
-				userRepo.EXPECT().DeleteSegments(context.Background(), tc.inputBody).Return(tc.repositoryError)
+				userRepo.EXPECT().SetSegmentsBulk(context.Background(), tc.repositoryInput, gomock.Any()).Return(tc.repositoryError)
 			}
 
 			zp, _ := zap.NewDevelopment()
-			serv := service.New(userRepo, segmentRepo, zp)
+			serv := service.New(userRepo, segmentRepo, nil, nil, nil, nil, nil, nil, nil, &config.Config{}, zp)
 
-			err := serv.UserDeleteSegments(context.Background(), tc.inputBody)
+			results, err := serv.UserBulkSetSegments(context.Background(), tc.inputBody)
 
 			a.Equal(tc.expectedError, err)
+			a.Equal(tc.expectedResults, results)
 		})
 	}
 }
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/webhook"
+)
+
+// WebhookCreate subscribes url to membership changes in the segments.
+// Secret for signing requests is generated unless client provided one, it is returned only here.
+func (s *Service) WebhookCreate(ctx context.Context, req *models.WebhookRequest) (*models.Webhook, error) {
+	if !IsValidWebhookURL(req.URL, s.allowPrivateHooks) {
+		return nil, errors.ErrInvalidWebhook
+	}
+
+	if req.Secret != "" && len(req.Secret) < MinSecretLength {
+		return nil, errors.ErrInvalidWebhook
+	}
+
+	if len(req.Slugs) == 0 {
+		return nil, errors.ErrNoSegmentsProvided
+	}
+
+	for _, slug := range req.Slugs {
+		if !IsValidSlug(slug) {
+			return nil, errors.WithSlugs(errors.ErrInvalidSegmentSlug, slug)
+		}
+	}
+
+	slugs := uniqueSorted(req.Slugs)
+
+	err := s.segmentsExist(ctx, slugs)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = newSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	hook := &models.Webhook{
+		ID:        uuid.NewString(),
+		URL:       req.URL,
+		Slugs:     slugs,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	}
+
+	if err = s.webhookRepo.CreateWebhook(ctx, hook, auditEntry(ctx, models.AuditWebhookCreate, "", slugs)); err != nil {
+		return nil, err
+	}
+
+	return hook, nil
+}
+
+func (s *Service) WebhookList(ctx context.Context) (*models.WebhookListResponse, error) {
+	hooks, err := s.webhookRepo.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WebhookListResponse{Webhooks: hooks}, nil
+}
+
+func (s *Service) WebhookGet(ctx context.Context, id string) (*models.Webhook, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.ErrWebhookNotFound
+	}
+
+	hook, err := s.webhookRepo.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if hook == nil {
+		return nil, errors.ErrWebhookNotFound
+	}
+
+	return hook, nil
+}
+
+// WebhookDelete unsubscribes webhook. Its pending deliveries are dropped along with the log.
+func (s *Service) WebhookDelete(ctx context.Context, id string) error {
+	hook, err := s.WebhookGet(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.webhookRepo.DeleteWebhook(ctx, id, auditEntry(ctx, models.AuditWebhookDelete, "", hook.Slugs))
+}
+
+// WebhookDeliveries returns a page of webhook's delivery log. Filtering by dead status gives the dead-letter list.
+func (s *Service) WebhookDeliveries(ctx context.Context, id string, filter *models.DeliveryFilter) (*models.WebhookDeliveriesResponse, error) {
+	if err := IsValidDeliveryFilter(filter); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.WebhookGet(ctx, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := s.webhookRepo.Deliveries(ctx, id, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Total:      total,
+		Limit:      filter.Limit,
+		Offset:     filter.Offset,
+	}, nil
+}
+
+// WebhookRedeliver queues dead or already delivered delivery again with a fresh set of attempts.
+func (s *Service) WebhookRedeliver(ctx context.Context, id string, deliveryID int64) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return errors.ErrWebhookNotFound
+	}
+
+	delivery, err := s.webhookRepo.Delivery(ctx, id, deliveryID)
+	if err != nil {
+		return err
+	}
+
+	if delivery == nil {
+		return errors.ErrDeliveryNotFound
+	}
+
+	if delivery.Status == models.DeliveryPending {
+		return errors.ErrDeliveryPending
+	}
+
+	return s.webhookRepo.RetryDelivery(ctx, deliveryID, time.Now())
+}
+
+// IsValidWebhookURL checks that webhook url is an absolute http(s) url. Unless private addresses
+// are allowed, its host must not be localhost or loopback, link-local or private address.
+// Names are checked once more when dialing, as they may resolve to such an address.
+func IsValidWebhookURL(raw string, allowPrivate bool) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return false
+	}
+
+	return allowPrivate || webhook.IsPublicHost(u.Hostname())
+}
+
+// IsValidDeliveryFilter checks delivery log filter and pagination.
+func IsValidDeliveryFilter(filter *models.DeliveryFilter) error {
+	switch filter.Status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		return errors.ErrInvalidFilter
+	}
+
+	if filter.Limit < 1 || filter.Limit > MaxPageLimit || filter.Offset < 0 {
+		return errors.ErrInvalidPagination
+	}
+
+	return nil
+}
+
+// newSecret generates random webhook secret.
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,361 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/service"
+)
+
+const testWebhookID = "5f0c6a3e-4a0b-4c64-9a4e-2b7f3c1d9e10"
+
+func TestService_WebhookCreate(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name         string
+		req          *models.WebhookRequest
+		allowPrivate bool
+
+		existingInput  []string
+		existingReturn []string
+		existingError  error
+		createError    error
+
+		expectedError error
+
+		expectingExistingCall bool
+		expectingCreateCall   bool
+	}{
+		{
+			name: "Webhook created",
+			req: &models.WebhookRequest{
+				URL:   "https://example.com/hooks/segments",
+				Slugs: []string{"AVITO_VOICE", "AVITO_TEST", "AVITO_VOICE"},
+			},
+			existingInput:         []string{"AVITO_TEST", "AVITO_VOICE"},
+			existingReturn:        []string{"AVITO_TEST", "AVITO_VOICE"},
+			expectingExistingCall: true,
+			expectingCreateCall:   true,
+		},
+		{
+			name: "Webhook with own secret",
+			req: &models.WebhookRequest{
+				URL:    "http://example.com:9000",
+				Slugs:  []string{"AVITO_TEST"},
+				Secret: "0123456789abcdef",
+			},
+			existingInput:         []string{"AVITO_TEST"},
+			existingReturn:        []string{"AVITO_TEST"},
+			expectingExistingCall: true,
+			expectingCreateCall:   true,
+		},
+		{
+			name:          "Localhost url",
+			req:           &models.WebhookRequest{URL: "http://localhost:9000", Slugs: []string{"AVITO_TEST"}},
+			expectedError: errors.ErrInvalidWebhook,
+		},
+		{
+			name:          "Loopback url",
+			req:           &models.WebhookRequest{URL: "http://[::1]:9000", Slugs: []string{"AVITO_TEST"}},
+			expectedError: errors.ErrInvalidWebhook,
+		},
+		{
+			name:          "Cloud metadata url",
+			req:           &models.WebhookRequest{URL: "http://169.254.169.254/latest/meta-data", Slugs: []string{"AVITO_TEST"}},
+			expectedError: errors.ErrInvalidWebhook,
+		},
+		{
+			name:          "Private network url",
+			req:           &models.WebhookRequest{URL: "https://10.0.0.5/hooks", Slugs: []string{"AVITO_TEST"}},
+			expectedError: errors.ErrInvalidWebhook,
+		},
+		{
+			name:                  "Private url allowed",
+			req:                   &models.WebhookRequest{URL: "http://localhost:9000", Slugs: []string{"AVITO_TEST"}},
+			allowPrivate:          true,
+			existingInput:         []string{"AVITO_TEST"},
+			existingReturn:        []string{"AVITO_TEST"},
+			expectingExistingCall: true,
+			expectingCreateCall:   true,
+		},
+		{
+			name:          "Relative url",
+			req:           &models.WebhookRequest{URL: "/hooks", Slugs: []string{"AVITO_TEST"}},
+			expectedError: errors.ErrInvalidWebhook,
+		},
+		{
+			name:          "Unsupported scheme",
+			req:           &models.WebhookRequest{URL: "ftp://example.com", Slugs: []string{"AVITO_TEST"}},
+			expectedError: errors.ErrInvalidWebhook,
+		},
+		{
+			name: "Short secret",
+			req: &models.WebhookRequest{
+				URL:    "https://example.com",
+				Slugs:  []string{"AVITO_TEST"},
+				Secret: "secret",
+			},
+			expectedError: errors.ErrInvalidWebhook,
+		},
+		{
+			name:          "No slugs",
+			req:           &models.WebhookRequest{URL: "https://example.com"},
+			expectedError: errors.ErrNoSegmentsProvided,
+		},
+		{
+			name:          "Invalid slug",
+			req:           &models.WebhookRequest{URL: "https://example.com", Slugs: []string{"avito"}},
+			expectedError: errors.WithSlugs(errors.ErrInvalidSegmentSlug, "avito"),
+		},
+		{
+			name:                  "Segment not found",
+			req:                   &models.WebhookRequest{URL: "https://example.com", Slugs: []string{"AVITO_TEST", "AVITO_VOICE"}},
+			existingInput:         []string{"AVITO_TEST", "AVITO_VOICE"},
+			existingReturn:        []string{"AVITO_TEST"},
+			expectedError:         errors.WithSlugs(errors.ErrSegmentsNotFound, "AVITO_VOICE"),
+			expectingExistingCall: true,
+		},
+		{
+			name:                  "Some internal error",
+			req:                   &models.WebhookRequest{URL: "https://example.com", Slugs: []string{"AVITO_TEST"}},
+			existingInput:         []string{"AVITO_TEST"},
+			existingReturn:        []string{"AVITO_TEST"},
+			createError:           os.ErrInvalid,
+			expectedError:         os.ErrInvalid,
+			expectingExistingCall: true,
+			expectingCreateCall:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			segmentRepo := NewMockSegmentRepository(ctrl)
+			webhookRepo := NewMockWebhookRepository(ctrl)
+
+			if tc.expectingExistingCall {
+				segmentRepo.EXPECT().Existing(context.Background(), tc.existingInput).Return(tc.existingReturn, tc.existingError)
+			}
+
+			if tc.expectingCreateCall {
+				webhookRepo.EXPECT().CreateWebhook(context.Background(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, hook *models.Webhook, _ *models.AuditEntry) error {
+						a.Equal(tc.req.URL, hook.URL)
+						a.Equal(tc.existingInput, hook.Slugs)
+						a.NotEmpty(hook.ID)
+
+						if tc.req.Secret != "" {
+							a.Equal(tc.req.Secret, hook.Secret)
+						} else {
+							a.Len(hook.Secret, 64)
+						}
+
+						return tc.createError
+					})
+			}
+
+			cfg := &config.Config{}
+			cfg.Webhooks.AllowPrivate = tc.allowPrivate
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, segmentRepo, nil, webhookRepo, nil, nil, nil, nil, nil, cfg, zp)
+
+			res, err := serv.WebhookCreate(context.Background(), tc.req)
+
+			a.Equal(tc.expectedError, err)
+
+			if tc.expectedError == nil {
+				a.NotEmpty(res.Secret)
+			} else {
+				a.Nil(res)
+			}
+		})
+	}
+}
+
+func TestService_WebhookDeliveries(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name   string
+		id     string
+		filter *models.DeliveryFilter
+
+		hookReturn       *models.Webhook
+		deliveriesReturn []models.WebhookDelivery
+		deliveriesError  error
+
+		expectedRes   *models.WebhookDeliveriesResponse
+		expectedError error
+
+		expectingHookCall       bool
+		expectingDeliveriesCall bool
+	}{
+		{
+			name:             "Dead letters listed",
+			id:               testWebhookID,
+			filter:           &models.DeliveryFilter{Status: models.DeliveryDead, Limit: 10},
+			hookReturn:       &models.Webhook{ID: testWebhookID},
+			deliveriesReturn: []models.WebhookDelivery{{ID: 1, WebhookID: testWebhookID, Status: models.DeliveryDead}},
+			expectedRes: &models.WebhookDeliveriesResponse{
+				Deliveries: []models.WebhookDelivery{{ID: 1, WebhookID: testWebhookID, Status: models.DeliveryDead}},
+				Total:      1,
+				Limit:      10,
+			},
+			expectingHookCall:       true,
+			expectingDeliveriesCall: true,
+		},
+		{
+			name:          "Invalid status",
+			id:            testWebhookID,
+			filter:        &models.DeliveryFilter{Status: "lost", Limit: 10},
+			expectedError: errors.ErrInvalidFilter,
+		},
+		{
+			name:          "Invalid pagination",
+			id:            testWebhookID,
+			filter:        &models.DeliveryFilter{Limit: service.MaxPageLimit + 1},
+			expectedError: errors.ErrInvalidPagination,
+		},
+		{
+			name:          "Invalid webhook id",
+			id:            "hook",
+			filter:        &models.DeliveryFilter{Limit: 10},
+			expectedError: errors.ErrWebhookNotFound,
+		},
+		{
+			name:              "Webhook not found",
+			id:                testWebhookID,
+			filter:            &models.DeliveryFilter{Limit: 10},
+			expectedError:     errors.ErrWebhookNotFound,
+			expectingHookCall: true,
+		},
+		{
+			name:                    "Some internal error",
+			id:                      testWebhookID,
+			filter:                  &models.DeliveryFilter{Limit: 10},
+			hookReturn:              &models.Webhook{ID: testWebhookID},
+			deliveriesError:         os.ErrInvalid,
+			expectedError:           os.ErrInvalid,
+			expectingHookCall:       true,
+			expectingDeliveriesCall: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			webhookRepo := NewMockWebhookRepository(ctrl)
+
+			if tc.expectingHookCall {
+				webhookRepo.EXPECT().GetWebhook(context.Background(), tc.id).Return(tc.hookReturn, nil)
+			}
+
+			if tc.expectingDeliveriesCall {
+				webhookRepo.EXPECT().Deliveries(context.Background(), tc.id, tc.filter).
+					Return(tc.deliveriesReturn, len(tc.deliveriesReturn), tc.deliveriesError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, nil, nil, webhookRepo, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			res, err := serv.WebhookDeliveries(context.Background(), tc.id, tc.filter)
+
+			a.Equal(tc.expectedError, err)
+			a.Equal(tc.expectedRes, res)
+		})
+	}
+}
+
+func TestService_WebhookRedeliver(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name       string
+		id         string
+		deliveryID int64
+
+		deliveryReturn *models.WebhookDelivery
+		retryError     error
+
+		expectedError error
+
+		expectingDeliveryCall bool
+		expectingRetryCall    bool
+	}{
+		{
+			name:                  "Dead delivery queued again",
+			id:                    testWebhookID,
+			deliveryID:            1,
+			deliveryReturn:        &models.WebhookDelivery{ID: 1, Status: models.DeliveryDead},
+			expectingDeliveryCall: true,
+			expectingRetryCall:    true,
+		},
+		{
+			name:                  "Pending delivery",
+			id:                    testWebhookID,
+			deliveryID:            1,
+			deliveryReturn:        &models.WebhookDelivery{ID: 1, Status: models.DeliveryPending},
+			expectedError:         errors.ErrDeliveryPending,
+			expectingDeliveryCall: true,
+		},
+		{
+			name:                  "Delivery not found",
+			id:                    testWebhookID,
+			deliveryID:            1,
+			expectedError:         errors.ErrDeliveryNotFound,
+			expectingDeliveryCall: true,
+		},
+		{
+			name:          "Invalid webhook id",
+			id:            "hook",
+			deliveryID:    1,
+			expectedError: errors.ErrWebhookNotFound,
+		},
+		{
+			name:                  "Some internal error",
+			id:                    testWebhookID,
+			deliveryID:            1,
+			deliveryReturn:        &models.WebhookDelivery{ID: 1, Status: models.DeliveryDelivered},
+			retryError:            os.ErrInvalid,
+			expectedError:         os.ErrInvalid,
+			expectingDeliveryCall: true,
+			expectingRetryCall:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			webhookRepo := NewMockWebhookRepository(ctrl)
+
+			if tc.expectingDeliveryCall {
+				webhookRepo.EXPECT().Delivery(context.Background(), tc.id, tc.deliveryID).Return(tc.deliveryReturn, nil)
+			}
+
+			if tc.expectingRetryCall {
+				webhookRepo.EXPECT().RetryDelivery(context.Background(), tc.deliveryID, gomock.Any()).Return(tc.retryError)
+			}
+
+			zp, _ := zap.NewDevelopment()
+			serv := service.New(nil, nil, nil, webhookRepo, nil, nil, nil, nil, nil, &config.Config{}, zp)
+
+			a.Equal(tc.expectedError, serv.WebhookRedeliver(context.Background(), tc.id, tc.deliveryID))
+		})
+	}
+}
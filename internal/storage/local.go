@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	errs "github.com/dupreehkuda/avito-segments/internal/errors"
+)
+
+const defaultRoot = "./reports"
+
+// Local keeps files in a directory on local filesystem.
+type Local struct {
+	root string
+}
+
+// NewLocal creates local storage in root, creating the directory if needed.
+func NewLocal(root string) (*Local, error) {
+	if root == "" {
+		root = defaultRoot
+	}
+
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &Local{root: root}, nil
+}
+
+// Put writes into temporary file which is renamed to name afterwards,
+// so readers never see partially written files.
+func (l *Local) Put(_ context.Context, name string, fn func(w io.Writer) error) error {
+	target := l.path(name)
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(l.root, "."+filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err = fn(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), target)
+}
+
+func (l *Local) Open(_ context.Context, name string) (io.ReadCloser, error) {
+	file, err := os.Open(l.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errs.ErrReportNotFound
+	}
+
+	return file, err
+}
+
+func (l *Local) Exists(_ context.Context, name string) (bool, error) {
+	_, err := os.Stat(l.path(name))
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, os.ErrNotExist):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// URL is always empty as local files are served by the service.
+func (l *Local) URL(_ context.Context, _ string) (string, error) {
+	return "", nil
+}
+
+func (l *Local) Delete(_ context.Context, name string) error {
+	err := os.Remove(l.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// path maps name to a file under root, prefixes of the name become directories.
+func (l *Local) path(name string) string {
+	return filepath.Join(l.root, filepath.FromSlash(path.Clean("/"+name)))
+}
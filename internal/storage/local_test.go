@@ -0,0 +1,108 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/storage"
+)
+
+func TestLocal(t *testing.T) {
+	a := assert.New(t)
+
+	ctx := context.Background()
+	root := t.TempDir()
+
+	store, err := storage.NewLocal(root)
+	a.NoError(err)
+
+	exists, err := store.Exists(ctx, "8_2023_report.csv")
+	a.NoError(err)
+	a.False(exists)
+
+	_, err = store.Open(ctx, "8_2023_report.csv")
+	a.Equal(errors.ErrReportNotFound, err)
+
+	err = store.Put(ctx, "8_2023_report.csv", func(w io.Writer) error {
+		_, err := io.WriteString(w, "report")
+		return err
+	})
+	a.NoError(err)
+
+	exists, err = store.Exists(ctx, "8_2023_report.csv")
+	a.NoError(err)
+	a.True(exists)
+
+	content, err := store.Open(ctx, "8_2023_report.csv")
+	a.NoError(err)
+
+	data, _ := io.ReadAll(content)
+	_ = content.Close()
+	a.Equal("report", string(data))
+
+	link, err := store.URL(ctx, "8_2023_report.csv")
+	a.NoError(err)
+	a.Empty(link)
+
+	a.NoError(store.Delete(ctx, "8_2023_report.csv"))
+	a.NoError(store.Delete(ctx, "8_2023_report.csv"), "deleting missing file should succeed")
+
+	exists, err = store.Exists(ctx, "8_2023_report.csv")
+	a.NoError(err)
+	a.False(exists)
+}
+
+func TestLocal_Prefix(t *testing.T) {
+	a := assert.New(t)
+
+	ctx := context.Background()
+	root := t.TempDir()
+
+	store, err := storage.NewLocal(root)
+	a.NoError(err)
+
+	err = store.Put(ctx, "imports/job.csv", func(w io.Writer) error {
+		_, err := io.WriteString(w, "user_id")
+		return err
+	})
+	a.NoError(err)
+
+	_, err = os.Stat(filepath.Join(root, "imports", "job.csv"))
+	a.NoError(err)
+
+	// Names can not escape the root.
+	err = store.Put(ctx, "../escaped.csv", func(w io.Writer) error { return nil })
+	a.NoError(err)
+
+	_, err = os.Stat(filepath.Join(root, "escaped.csv"))
+	a.NoError(err)
+}
+
+func TestLocal_FailedPut(t *testing.T) {
+	a := assert.New(t)
+
+	ctx := context.Background()
+	root := t.TempDir()
+
+	store, err := storage.NewLocal(root)
+	a.NoError(err)
+
+	err = store.Put(ctx, "8_2023_report.csv", func(w io.Writer) error {
+		_, _ = io.WriteString(w, "partial")
+		return os.ErrInvalid
+	})
+	a.Equal(os.ErrInvalid, err)
+
+	exists, err := store.Exists(ctx, "8_2023_report.csv")
+	a.NoError(err)
+	a.False(exists)
+
+	files, _ := os.ReadDir(root)
+	a.Empty(files, "temporary file left behind")
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	errs "github.com/dupreehkuda/avito-segments/internal/errors"
+)
+
+const defaultURLExpiry = 15 * time.Minute
+
+// S3 keeps files in a bucket of S3-compatible object storage.
+type S3 struct {
+	client    *minio.Client
+	bucket    string
+	region    string
+	redirect  bool
+	urlExpiry time.Duration
+}
+
+// NewS3 creates S3 storage client. Bucket is not checked until EnsureBucket.
+func NewS3(config *config.Config) (*S3, error) {
+	cfg := config.Storage.S3
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       cfg.UseSSL,
+		Region:       cfg.Region,
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	store := &S3{
+		client:    client,
+		bucket:    cfg.Bucket,
+		region:    cfg.Region,
+		redirect:  cfg.Redirect,
+		urlExpiry: cfg.URLExpiry,
+	}
+
+	if store.urlExpiry <= 0 {
+		store.urlExpiry = defaultURLExpiry
+	}
+
+	return store, nil
+}
+
+// EnsureBucket creates the bucket if it does not exist yet.
+func (s *S3) EnsureBucket(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	return s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{Region: s.region})
+}
+
+// Put spools file to a temporary file first, so upload size is known
+// and memory usage does not depend on file size.
+func (s *S3) Put(ctx context.Context, name string, fn func(w io.Writer) error) error {
+	file, err := os.CreateTemp("", "report-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err = fn(file); err != nil {
+		return err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(ctx, s.bucket, name, file, size, minio.PutObjectOptions{
+		ContentType:          contentType(name),
+		DisableContentSha256: true,
+	})
+
+	return err
+}
+
+func (s *S3) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// GetObject is lazy, stat makes missing object an error right away.
+	if _, err = object.Stat(); err != nil {
+		_ = object.Close()
+
+		if isNotFound(err) {
+			return nil, errs.ErrReportNotFound
+		}
+
+		return nil, err
+	}
+
+	return object, nil
+}
+
+func (s *S3) Exists(ctx context.Context, name string) (bool, error) {
+	_, err := s.client.StatObject(ctx, s.bucket, name, minio.StatObjectOptions{})
+
+	switch {
+	case err == nil:
+		return true, nil
+	case isNotFound(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// URL returns presigned download link if redirects are enabled.
+func (s *S3) URL(ctx context.Context, name string) (string, error) {
+	if !s.redirect {
+		return "", nil
+	}
+
+	link, err := s.client.PresignedGetObject(ctx, s.bucket, name, s.urlExpiry, url.Values{})
+	if err != nil {
+		return "", err
+	}
+
+	return link.String(), nil
+}
+
+func (s *S3) Delete(ctx context.Context, name string) error {
+	return s.client.RemoveObject(ctx, s.bucket, name, minio.RemoveObjectOptions{})
+}
+
+func isNotFound(err error) bool {
+	resp := minio.ToErrorResponse(err)
+	return resp.StatusCode == http.StatusNotFound || resp.Code == "NoSuchKey"
+}
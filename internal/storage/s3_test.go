@@ -0,0 +1,180 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/storage"
+)
+
+// fakeS3 is a minimal in-memory stand-in for S3-compatible storage with path-style addressing.
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]bool
+	objects map[string][]byte
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		buckets: make(map[string]bool),
+		objects: make(map[string][]byte),
+	}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+
+	if key == "" {
+		switch r.Method {
+		case http.MethodHead:
+			if !f.buckets[bucket] {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case http.MethodPut:
+			f.buckets[bucket] = true
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[bucket+"/"+key] = data
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodHead, http.MethodGet:
+		data, ok := f.objects[bucket+"/"+key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+
+			if r.Method == http.MethodGet {
+				_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			}
+
+			return
+		}
+
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, bucket+"/"+key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func newS3(t *testing.T, redirect bool) (*storage.S3, *fakeS3) {
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	endpoint, _ := url.Parse(server.URL)
+
+	cfg := &config.Config{}
+	cfg.Storage.S3.Endpoint = endpoint.Host
+	cfg.Storage.S3.Region = "us-east-1"
+	cfg.Storage.S3.AccessKey = "minio"
+	cfg.Storage.S3.SecretKey = "minio123"
+	cfg.Storage.S3.Bucket = "reports"
+	cfg.Storage.S3.Redirect = redirect
+
+	store, err := storage.NewS3(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store, fake
+}
+
+func TestS3(t *testing.T) {
+	a := assert.New(t)
+
+	ctx := context.Background()
+	store, fake := newS3(t, false)
+
+	a.NoError(store.EnsureBucket(ctx))
+	a.True(fake.buckets["reports"])
+
+	exists, err := store.Exists(ctx, "8_2023_report.csv")
+	a.NoError(err)
+	a.False(exists)
+
+	_, err = store.Open(ctx, "8_2023_report.csv")
+	a.Equal(errors.ErrReportNotFound, err)
+
+	err = store.Put(ctx, "8_2023_report.csv", func(w io.Writer) error {
+		_, err := io.WriteString(w, "report")
+		return err
+	})
+	a.NoError(err)
+	a.Equal("report", string(fake.objects["reports/8_2023_report.csv"]))
+
+	exists, err = store.Exists(ctx, "8_2023_report.csv")
+	a.NoError(err)
+	a.True(exists)
+
+	content, err := store.Open(ctx, "8_2023_report.csv")
+	a.NoError(err)
+
+	data, _ := io.ReadAll(content)
+	_ = content.Close()
+	a.Equal("report", string(data))
+
+	link, err := store.URL(ctx, "8_2023_report.csv")
+	a.NoError(err)
+	a.Empty(link)
+
+	a.NoError(store.Delete(ctx, "8_2023_report.csv"))
+	a.NotContains(fake.objects, "reports/8_2023_report.csv")
+}
+
+func TestS3_FailedPut(t *testing.T) {
+	a := assert.New(t)
+
+	store, fake := newS3(t, false)
+
+	err := store.Put(context.Background(), "8_2023_report.csv", func(w io.Writer) error {
+		return os.ErrInvalid
+	})
+	a.Equal(os.ErrInvalid, err)
+	a.Empty(fake.objects)
+}
+
+func TestS3_URL(t *testing.T) {
+	a := assert.New(t)
+
+	store, _ := newS3(t, true)
+
+	link, err := store.URL(context.Background(), "8_2023_report.csv")
+	a.NoError(err)
+
+	parsed, err := url.Parse(link)
+	a.NoError(err)
+	a.Equal("/reports/8_2023_report.csv", parsed.Path)
+	a.NotEmpty(parsed.Query().Get("X-Amz-Signature"))
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/report"
+)
+
+// Storage types selectable in config.
+const (
+	TypeLocal = "local"
+	TypeS3    = "s3"
+)
+
+// Storage keeps generated report files and uploaded import files.
+// Names may have slash separated prefixes, reports are stored without one.
+type Storage interface {
+	// Put stores file written by fn. File becomes visible only if fn succeeds.
+	Put(ctx context.Context, name string, fn func(w io.Writer) error) error
+	// Open returns file contents or ErrReportNotFound.
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// Exists reports whether file is stored.
+	Exists(ctx context.Context, name string) (bool, error)
+	// URL returns direct download link or empty string if files should be served by the service itself.
+	URL(ctx context.Context, name string) (string, error)
+	// Delete removes file. Missing file is not an error.
+	Delete(ctx context.Context, name string) error
+}
+
+// New creates storage chosen in config.
+func New(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) (Storage, error) {
+	switch config.Storage.Type {
+	case TypeS3:
+		store, err := NewS3(config)
+		if err != nil {
+			logger.Error("Unable to create S3 storage", zap.Error(err))
+			return nil, err
+		}
+
+		lc.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				if err := store.EnsureBucket(ctx); err != nil {
+					logger.Error("Unable to prepare S3 bucket", zap.Error(err))
+					return err
+				}
+
+				return nil
+			},
+		})
+
+		return store, nil
+	default:
+		store, err := NewLocal(config.Storage.Root)
+		if err != nil {
+			logger.Error("Unable to create local storage", zap.Error(err))
+			return nil, err
+		}
+
+		return store, nil
+	}
+}
+
+// contentType guesses content type of the file by its extension.
+func contentType(name string) string {
+	ext := filepath.Ext(name)
+
+	if ct := report.ContentType(strings.TrimPrefix(ext, ".")); ct != "" {
+		return ct
+	}
+
+	if ct := mime.TypeByExtension(ext); ct != "" {
+		return ct
+	}
+
+	return "application/octet-stream"
+}
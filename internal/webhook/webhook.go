@@ -0,0 +1,160 @@
+// Package webhook sends signed membership change notifications to subscribers.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mailru/easyjson"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+// Headers of webhook request.
+const (
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const (
+	signaturePrefix = "sha256="
+	maxErrorBody    = 512
+	defaultTimeout  = 10 * time.Second
+)
+
+// ErrForbiddenAddress is returned for webhook connections to addresses which are not public.
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+// Sign returns signature of the body sent at the moment: HMAC-SHA256 of "<unix timestamp>.<body>" keyed by secret.
+// Timestamp is signed too, so receivers can reject replayed requests.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks signature of the body sent at the moment.
+func Verify(secret string, timestamp time.Time, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Client posts deliveries as JSON events.
+type Client struct {
+	client *http.Client
+}
+
+// New creates webhook client with request timeout from config.
+// Unless private addresses are allowed, client connects only to public ones.
+func New(config *config.Config) *Client {
+	timeout := config.Webhooks.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if !config.Webhooks.AllowPrivate {
+		dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second, Control: publicOnly}
+
+		transport.DialContext = dialer.DialContext
+		// Proxy would be dialed instead of the webhook, leaving webhook's address unchecked.
+		transport.Proxy = nil
+	}
+
+	return &Client{client: &http.Client{Timeout: timeout, Transport: transport}}
+}
+
+// publicOnly refuses connections to addresses which are not public. It is called with the address
+// the name resolved to, for every redirect too, so names resolving to internal addresses are refused
+// even if they resolved to public ones when webhook was created.
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !IsPublicAddress(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+
+	return nil
+}
+
+// IsPublicAddress tells whether ip may receive webhooks. Loopback, link-local (including cloud
+// metadata at 169.254.169.254), private, unspecified and multicast addresses may not.
+func IsPublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	return ip.IsValid() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() && !ip.IsPrivate() && !ip.IsUnspecified()
+}
+
+// IsPublicHost tells whether host of webhook url may point to public address.
+// Names other than localhost are resolved only when dialing, where the address is checked.
+func IsPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return IsPublicAddress(ip)
+	}
+
+	return true
+}
+
+// Send posts delivery's event to webhook's url and returns response status.
+// Delivery succeeded only if error is nil, which requires 2xx status.
+func (c *Client) Send(ctx context.Context, delivery models.WebhookDelivery) (int, error) {
+	body, err := easyjson.Marshal(delivery.Event)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, now, body))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(text))
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
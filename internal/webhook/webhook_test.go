@@ -0,0 +1,143 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/webhook"
+)
+
+func TestSign(t *testing.T) {
+	a := assert.New(t)
+
+	timestamp := time.Unix(1693000000, 0)
+	body := []byte(`{"slug":"AVITO_TEST"}`)
+
+	signature := webhook.Sign("0123456789abcdef", timestamp, body)
+
+	a.Equal("sha256=24b72fe31a81f10429c7c6b7939f31dc698fa1c2c675136933c16a1b9b184145", signature)
+	a.True(webhook.Verify("0123456789abcdef", timestamp, body, signature))
+	a.False(webhook.Verify("fedcba9876543210", timestamp, body, signature))
+	a.False(webhook.Verify("0123456789abcdef", timestamp.Add(time.Second), body, signature))
+	a.False(webhook.Verify("0123456789abcdef", timestamp, []byte(`{"slug":"AVITO_VOICE"}`), signature))
+}
+
+func TestClient_Send(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name           string
+		status         int
+		expectingError bool
+	}{
+		{
+			name:   "Delivered",
+			status: http.StatusNoContent,
+		},
+		{
+			name:           "Rejected",
+			status:         http.StatusInternalServerError,
+			expectingError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delivery := models.WebhookDelivery{
+				ID:     42,
+				Secret: "0123456789abcdef",
+				Event: models.OutboxEvent{
+					ID:     7,
+					Kind:   models.EventMembership,
+					UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002",
+					Slug:   "AVITO_TEST",
+					Method: models.MethodAdded,
+				},
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				a.NoError(err)
+
+				unix, err := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+				a.NoError(err)
+
+				a.Equal(http.MethodPost, r.Method)
+				a.Equal("application/json", r.Header.Get("Content-Type"))
+				a.Equal("42", r.Header.Get(webhook.HeaderDelivery))
+				a.True(webhook.Verify(delivery.Secret, time.Unix(unix, 0), body, r.Header.Get(webhook.HeaderSignature)))
+				a.JSONEq(`{"id":7,"kind":"membership","userID":"80b0b88d-379e-11ee-8bf7-0242c0a80002",`+
+					`"slug":"AVITO_TEST","method":"added","timestamp":"0001-01-01T00:00:00Z"}`, string(body))
+
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			delivery.URL = server.URL
+
+			// Test server listens on loopback.
+			cfg := &config.Config{}
+			cfg.Webhooks.AllowPrivate = true
+
+			status, err := webhook.New(cfg).Send(context.Background(), delivery)
+
+			a.Equal(tc.status, status)
+			a.Equal(tc.expectingError, err != nil)
+		})
+	}
+}
+
+func TestClient_SendPrivate(t *testing.T) {
+	a := assert.New(t)
+
+	called := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// Address is checked when dialing, after the name is resolved.
+	_, err := webhook.New(&config.Config{}).Send(context.Background(), models.WebhookDelivery{URL: server.URL})
+
+	a.ErrorIs(err, webhook.ErrForbiddenAddress)
+	a.False(called)
+}
+
+func TestIsPublicHost(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		host     string
+		expected bool
+	}{
+		{host: "example.com", expected: true},
+		{host: "93.184.216.34", expected: true},
+		{host: "2606:2800:220:1:248:1893:25c8:1946", expected: true},
+		{host: "localhost", expected: false},
+		{host: "api.localhost.", expected: false},
+		{host: "127.0.0.1", expected: false},
+		{host: "::1", expected: false},
+		{host: "::ffff:127.0.0.1", expected: false},
+		{host: "169.254.169.254", expected: false},
+		{host: "fe80::1", expected: false},
+		{host: "10.1.2.3", expected: false},
+		{host: "172.16.0.1", expected: false},
+		{host: "192.168.1.1", expected: false},
+		{host: "fd00::1", expected: false},
+		{host: "0.0.0.0", expected: false},
+	}
+
+	for _, tc := range testCases {
+		a.Equal(tc.expected, webhook.IsPublicHost(tc.host), tc.host)
+	}
+}
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+)
+
+//go:generate mockgen -source=expiry.go -destination=mock_test.go -package=worker_test
+
+// DefaultExpiryInterval is the pause between expiry runs unless configured.
+const DefaultExpiryInterval = time.Minute
+
+// ExpiryRepository marks memberships past their expiry as expired.
+type ExpiryRepository interface {
+	ExpireSegments(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Expiry periodically turns memberships past expired_at into expiry events.
+type Expiry struct {
+	repo     ExpiryRepository
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// RegisterExpiry creates expiry worker and binds it to the application lifecycle.
+func RegisterExpiry(lc fx.Lifecycle, repo ExpiryRepository, config *config.Config, logger *zap.Logger) *Expiry {
+	worker := &Expiry{
+		repo:     repo,
+		interval: config.Workers.ExpiryInterval,
+		logger:   logger,
+	}
+
+	if worker.interval <= 0 {
+		worker.interval = DefaultExpiryInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				worker.run(ctx)
+			}()
+
+			logger.Info("Expiry worker started", zap.Duration("interval", worker.interval))
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			logger.Info("Expiry worker stopped")
+
+			return nil
+		},
+	})
+
+	return worker
+}
+
+func (e *Expiry) run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.Expire(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Expire processes all memberships that are expired by now.
+func (e *Expiry) Expire(ctx context.Context) {
+	count, err := e.repo.ExpireSegments(ctx, time.Now())
+	if err != nil {
+		if ctx.Err() == nil {
+			e.logger.Error("Error while expiring segments", zap.Error(err))
+		}
+
+		return
+	}
+
+	if count > 0 {
+		e.logger.Info("Segments expired", zap.Int64("count", count))
+	}
+}
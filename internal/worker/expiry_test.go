@@ -0,0 +1,92 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/worker"
+)
+
+func TestExpiry_Expire(t *testing.T) {
+	testCases := []struct {
+		name        string
+		count       int64
+		returnError error
+	}{
+		{
+			name:        "Segments expired",
+			count:       3,
+			returnError: nil,
+		},
+		{
+			name:        "Nothing to expire",
+			count:       0,
+			returnError: nil,
+		},
+		{
+			name:        "DB error",
+			count:       0,
+			returnError: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := NewMockExpiryRepository(ctrl)
+			repo.EXPECT().ExpireSegments(context.Background(), gomock.Any()).Return(tc.count, tc.returnError)
+
+			zp, _ := zap.NewDevelopment()
+			expiry := worker.RegisterExpiry(fxtest.NewLifecycle(t), repo, &config.Config{}, zp)
+
+			expiry.Expire(context.Background())
+		})
+	}
+}
+
+func TestExpiry_Lifecycle(t *testing.T) {
+	a := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	called := make(chan struct{}, 1)
+
+	repo := NewMockExpiryRepository(ctrl)
+	repo.EXPECT().ExpireSegments(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, now time.Time) (int64, error) {
+			select {
+			case called <- struct{}{}:
+			default:
+			}
+
+			return 0, nil
+		}).
+		MinTimes(1)
+
+	cfg := &config.Config{}
+	cfg.Workers.ExpiryInterval = time.Hour
+
+	zp, _ := zap.NewDevelopment()
+	lc := fxtest.NewLifecycle(t)
+	worker.RegisterExpiry(lc, repo, cfg, zp)
+
+	lc.RequireStart()
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		a.Fail("expiry was not run on start")
+	}
+
+	lc.RequireStop()
+}
@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+//go:generate mockgen -source=jobs.go -destination=jobs_mock_test.go -package=worker_test
+
+// jobsInterval is the pause between heartbeats of background jobs, well below their lease.
+const jobsInterval = 30 * time.Second
+
+// JobKeeper keeps background jobs of this instance alive and takes over abandoned ones.
+type JobKeeper interface {
+	KeepJobs(ctx context.Context)
+}
+
+// Jobs periodically runs heartbeat of background jobs, so jobs lost on stop or crash are resumed.
+type Jobs struct {
+	keeper   JobKeeper
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// RegisterJobs creates jobs worker and binds it to the application lifecycle.
+// Jobs are checked right on start, so jobs abandoned by previous run are taken over as soon as their lease expires.
+func RegisterJobs(lc fx.Lifecycle, keeper JobKeeper, logger *zap.Logger) *Jobs {
+	worker := &Jobs{
+		keeper:   keeper,
+		interval: jobsInterval,
+		logger:   logger,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				worker.run(ctx)
+			}()
+
+			logger.Info("Jobs worker started", zap.Duration("interval", worker.interval))
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			logger.Info("Jobs worker stopped")
+
+			return nil
+		},
+	})
+
+	return worker
+}
+
+func (j *Jobs) run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.keeper.KeepJobs(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: jobs.go
+
+// Package worker_test is a generated GoMock package.
+package worker_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobKeeper is a mock of JobKeeper interface.
+type MockJobKeeper struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobKeeperMockRecorder
+}
+
+// MockJobKeeperMockRecorder is the mock recorder for MockJobKeeper.
+type MockJobKeeperMockRecorder struct {
+	mock *MockJobKeeper
+}
+
+// NewMockJobKeeper creates a new mock instance.
+func NewMockJobKeeper(ctrl *gomock.Controller) *MockJobKeeper {
+	mock := &MockJobKeeper{ctrl: ctrl}
+	mock.recorder = &MockJobKeeperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobKeeper) EXPECT() *MockJobKeeperMockRecorder {
+	return m.recorder
+}
+
+// KeepJobs mocks base method.
+func (m *MockJobKeeper) KeepJobs(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "KeepJobs", ctx)
+}
+
+// KeepJobs indicates an expected call of KeepJobs.
+func (mr *MockJobKeeperMockRecorder) KeepJobs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeepJobs", reflect.TypeOf((*MockJobKeeper)(nil).KeepJobs), ctx)
+}
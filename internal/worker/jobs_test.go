@@ -0,0 +1,47 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/worker"
+)
+
+func TestJobs_Lifecycle(t *testing.T) {
+	a := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	called := make(chan struct{}, 1)
+
+	keeper := NewMockJobKeeper(ctrl)
+	keeper.EXPECT().KeepJobs(gomock.Any()).
+		Do(func(ctx context.Context) {
+			select {
+			case called <- struct{}{}:
+			default:
+			}
+		}).
+		MinTimes(1)
+
+	zp, _ := zap.NewDevelopment()
+	lc := fxtest.NewLifecycle(t)
+	worker.RegisterJobs(lc, keeper, zp)
+
+	lc.RequireStart()
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		a.Fail("jobs were not kept on start")
+	}
+
+	lc.RequireStop()
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: expiry.go
+
+// Package worker_test is a generated GoMock package.
+package worker_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExpiryRepository is a mock of ExpiryRepository interface.
+type MockExpiryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpiryRepositoryMockRecorder
+}
+
+// MockExpiryRepositoryMockRecorder is the mock recorder for MockExpiryRepository.
+type MockExpiryRepositoryMockRecorder struct {
+	mock *MockExpiryRepository
+}
+
+// NewMockExpiryRepository creates a new mock instance.
+func NewMockExpiryRepository(ctrl *gomock.Controller) *MockExpiryRepository {
+	mock := &MockExpiryRepository{ctrl: ctrl}
+	mock.recorder = &MockExpiryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpiryRepository) EXPECT() *MockExpiryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExpireSegments mocks base method.
+func (m *MockExpiryRepository) ExpireSegments(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireSegments", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireSegments indicates an expected call of ExpireSegments.
+func (mr *MockExpiryRepositoryMockRecorder) ExpireSegments(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireSegments", reflect.TypeOf((*MockExpiryRepository)(nil).ExpireSegments), ctx, now)
+}
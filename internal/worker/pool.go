@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+)
+
+const (
+	defaultPoolSize  = 4
+	defaultQueueSize = 100
+)
+
+// Task is a unit of background work. Context is cancelled when the application stops.
+type Task func(ctx context.Context)
+
+// Pool runs submitted tasks on a fixed amount of goroutines.
+type Pool struct {
+	tasks  chan Task
+	size   int
+	logger *zap.Logger
+}
+
+// RegisterPool creates worker pool and binds it to the application lifecycle.
+// Tasks still queued on stop are dropped, running ones get their context cancelled.
+// Jobs of such tasks are taken over by Jobs worker once their lease expires.
+func RegisterPool(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) *Pool {
+	pool := &Pool{
+		size:   config.Workers.PoolSize,
+		logger: logger,
+	}
+
+	if pool.size <= 0 {
+		pool.size = defaultPoolSize
+	}
+
+	queueSize := config.Workers.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	pool.tasks = make(chan Task, queueSize)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := &sync.WaitGroup{}
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			wg.Add(pool.size)
+
+			for i := 0; i < pool.size; i++ {
+				go func() {
+					defer wg.Done()
+					pool.run(ctx)
+				}()
+			}
+
+			logger.Info("Worker pool started", zap.Int("size", pool.size))
+
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+
+			logger.Info("Worker pool stopped")
+
+			return nil
+		},
+	})
+
+	return pool
+}
+
+func (p *Pool) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task := <-p.tasks:
+			p.execute(ctx, task)
+		}
+	}
+}
+
+func (p *Pool) execute(ctx context.Context, task Task) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Error("Task panicked", zap.Any("panic", r))
+		}
+	}()
+
+	task(ctx)
+}
+
+// Submit queues task for execution without blocking.
+func (p *Pool) Submit(task Task) error {
+	select {
+	case p.tasks <- task:
+		return nil
+	default:
+		return errors.ErrQueueFull
+	}
+}
@@ -0,0 +1,79 @@
+package worker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/errors"
+	"github.com/dupreehkuda/avito-segments/internal/worker"
+)
+
+func TestPool_Submit(t *testing.T) {
+	a := assert.New(t)
+
+	zp, _ := zap.NewDevelopment()
+	lc := fxtest.NewLifecycle(t)
+	pool := worker.RegisterPool(lc, &config.Config{}, zp)
+
+	lc.RequireStart()
+	defer lc.RequireStop()
+
+	done := make(chan struct{})
+
+	a.NoError(pool.Submit(func(ctx context.Context) {
+		close(done)
+	}))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		a.Fail("task was not run")
+	}
+}
+
+func TestPool_QueueFull(t *testing.T) {
+	a := assert.New(t)
+
+	cfg := &config.Config{}
+	cfg.Workers.QueueSize = 1
+
+	zp, _ := zap.NewDevelopment()
+	pool := worker.RegisterPool(fxtest.NewLifecycle(t), cfg, zp)
+
+	a.NoError(pool.Submit(func(ctx context.Context) {}))
+	a.Equal(errors.ErrQueueFull, pool.Submit(func(ctx context.Context) {}))
+}
+
+func TestPool_Stop(t *testing.T) {
+	a := assert.New(t)
+
+	zp, _ := zap.NewDevelopment()
+	lc := fxtest.NewLifecycle(t)
+	pool := worker.RegisterPool(lc, &config.Config{}, zp)
+
+	lc.RequireStart()
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+
+	a.NoError(pool.Submit(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+	}))
+
+	<-started
+	lc.RequireStop()
+
+	select {
+	case <-cancelled:
+	default:
+		a.Fail("task context was not cancelled on stop")
+	}
+}
@@ -0,0 +1,158 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+//go:generate mockgen -source=relay.go -destination=relay_mock_test.go -package=worker_test
+
+const (
+	defaultRelayInterval  = time.Second
+	defaultRelayBatchSize = 500
+	defaultRetention      = 7 * 24 * time.Hour
+	purgeInterval         = time.Hour
+)
+
+// RelayRepository reads outbox events in the order they were written.
+type RelayRepository interface {
+	PublishOutbox(ctx context.Context, limit int, fn func(events []models.OutboxEvent) error) (int, error)
+	PurgeOutbox(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Publisher delivers events to consumers. Events passed again after a failure may be delivered twice.
+type Publisher interface {
+	Publish(ctx context.Context, events []models.OutboxEvent) error
+}
+
+// Relay publishes events written to outbox by membership and catalog changes.
+// Event is marked published only after publisher accepted it, so delivery is at-least-once.
+type Relay struct {
+	repo      RelayRepository
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+	retention time.Duration
+	logger    *zap.Logger
+}
+
+// RegisterRelay creates outbox relay and binds it to the application lifecycle.
+func RegisterRelay(lc fx.Lifecycle, repo RelayRepository, publisher Publisher, config *config.Config, logger *zap.Logger) *Relay {
+	relay := &Relay{
+		repo:      repo,
+		publisher: publisher,
+		interval:  config.Outbox.Interval,
+		batchSize: config.Outbox.BatchSize,
+		retention: config.Outbox.Retention,
+		logger:    logger,
+	}
+
+	if relay.interval <= 0 {
+		relay.interval = defaultRelayInterval
+	}
+
+	if relay.batchSize <= 0 {
+		relay.batchSize = defaultRelayBatchSize
+	}
+
+	if relay.retention <= 0 {
+		relay.retention = defaultRetention
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				relay.run(ctx)
+			}()
+
+			logger.Info("Outbox relay started", zap.Duration("interval", relay.interval))
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			logger.Info("Outbox relay stopped")
+
+			return nil
+		},
+	})
+
+	return relay
+}
+
+func (r *Relay) run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	purge := time.NewTicker(purgeInterval)
+	defer purge.Stop()
+
+	for {
+		r.Relay(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-purge.C:
+			r.Purge(ctx)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Relay publishes batches of pending events until outbox is drained or publishing fails.
+func (r *Relay) Relay(ctx context.Context) {
+	for {
+		count, err := r.repo.PublishOutbox(ctx, r.batchSize, func(events []models.OutboxEvent) error {
+			return r.publisher.Publish(ctx, events)
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.Error("Error while relaying outbox", zap.Error(err))
+			}
+
+			return
+		}
+
+		if count > 0 {
+			r.logger.Debug("Outbox events published", zap.Int("count", count))
+		}
+
+		if count < r.batchSize {
+			return
+		}
+	}
+}
+
+// Purge deletes events published longer than retention ago.
+func (r *Relay) Purge(ctx context.Context) {
+	count, err := r.repo.PurgeOutbox(ctx, time.Now().Add(-r.retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("Error while purging outbox", zap.Error(err))
+		}
+
+		return
+	}
+
+	if count > 0 {
+		r.logger.Info("Outbox purged", zap.Int64("count", count))
+	}
+}
@@ -0,0 +1,104 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: relay.go
+
+// Package worker_test is a generated GoMock package.
+package worker_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/dupreehkuda/avito-segments/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRelayRepository is a mock of RelayRepository interface.
+type MockRelayRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRelayRepositoryMockRecorder
+}
+
+// MockRelayRepositoryMockRecorder is the mock recorder for MockRelayRepository.
+type MockRelayRepositoryMockRecorder struct {
+	mock *MockRelayRepository
+}
+
+// NewMockRelayRepository creates a new mock instance.
+func NewMockRelayRepository(ctrl *gomock.Controller) *MockRelayRepository {
+	mock := &MockRelayRepository{ctrl: ctrl}
+	mock.recorder = &MockRelayRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRelayRepository) EXPECT() *MockRelayRepositoryMockRecorder {
+	return m.recorder
+}
+
+// PublishOutbox mocks base method.
+func (m *MockRelayRepository) PublishOutbox(ctx context.Context, limit int, fn func([]models.OutboxEvent) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishOutbox", ctx, limit, fn)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishOutbox indicates an expected call of PublishOutbox.
+func (mr *MockRelayRepositoryMockRecorder) PublishOutbox(ctx, limit, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishOutbox", reflect.TypeOf((*MockRelayRepository)(nil).PublishOutbox), ctx, limit, fn)
+}
+
+// PurgeOutbox mocks base method.
+func (m *MockRelayRepository) PurgeOutbox(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeOutbox", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeOutbox indicates an expected call of PurgeOutbox.
+func (mr *MockRelayRepositoryMockRecorder) PurgeOutbox(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeOutbox", reflect.TypeOf((*MockRelayRepository)(nil).PurgeOutbox), ctx, before)
+}
+
+// MockPublisher is a mock of Publisher interface.
+type MockPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherMockRecorder
+}
+
+// MockPublisherMockRecorder is the mock recorder for MockPublisher.
+type MockPublisherMockRecorder struct {
+	mock *MockPublisher
+}
+
+// NewMockPublisher creates a new mock instance.
+func NewMockPublisher(ctrl *gomock.Controller) *MockPublisher {
+	mock := &MockPublisher{ctrl: ctrl}
+	mock.recorder = &MockPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisher) EXPECT() *MockPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockPublisher) Publish(ctx context.Context, events []models.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, events)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockPublisherMockRecorder) Publish(ctx, events interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockPublisher)(nil).Publish), ctx, events)
+}
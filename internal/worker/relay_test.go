@@ -0,0 +1,179 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/publisher"
+	"github.com/dupreehkuda/avito-segments/internal/worker"
+)
+
+var testEvents = []models.OutboxEvent{
+	{ID: 1, Kind: models.EventMembership, UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Slug: "TEST_SLUG", Method: models.MethodAdded},
+	{ID: 2, Kind: models.EventMembership, UserID: "80b0b88d-379e-11ee-8bf7-0242c0a80002", Slug: "TEST_SLUG", Method: models.MethodDeleted},
+	{ID: 3, Kind: models.EventSegment, Slug: "TEST_SLUG", Method: models.SegmentDeleted},
+}
+
+// outboxBatches makes PublishOutbox pass batches to publisher one by one, as repository does.
+func outboxBatches(batches ...[]models.OutboxEvent) func(ctx context.Context, limit int, fn func([]models.OutboxEvent) error) (int, error) {
+	return func(ctx context.Context, limit int, fn func([]models.OutboxEvent) error) (int, error) {
+		if len(batches) == 0 {
+			return 0, nil
+		}
+
+		batch := batches[0]
+		if err := fn(batch); err != nil {
+			return 0, err
+		}
+
+		batches = batches[1:]
+
+		return len(batch), nil
+	}
+}
+
+func TestRelay_Relay(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		name           string
+		batches        [][]models.OutboxEvent
+		publishError   error
+		returnError    error
+		expectedCalls  int
+		expectedEvents []models.OutboxEvent
+	}{
+		{
+			name:           "Single batch",
+			batches:        [][]models.OutboxEvent{testEvents[:1]},
+			expectedCalls:  1,
+			expectedEvents: testEvents[:1],
+		},
+		{
+			name:           "Drains full batches",
+			batches:        [][]models.OutboxEvent{testEvents[:2], testEvents[2:]},
+			expectedCalls:  2,
+			expectedEvents: testEvents,
+		},
+		{
+			name:           "Full last batch",
+			batches:        [][]models.OutboxEvent{testEvents[:2]},
+			expectedCalls:  2,
+			expectedEvents: testEvents[:2],
+		},
+		{
+			name:          "Nothing to publish",
+			expectedCalls: 1,
+		},
+		{
+			name:          "Publisher error",
+			batches:       [][]models.OutboxEvent{testEvents[:2], testEvents[2:]},
+			publishError:  errors.New("broker is unavailable"),
+			expectedCalls: 1,
+		},
+		{
+			name:          "DB error",
+			returnError:   context.DeadlineExceeded,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var pub worker.Publisher
+
+			memory := publisher.NewMemory()
+			pub = memory
+
+			if tc.publishError != nil {
+				failing := NewMockPublisher(ctrl)
+				failing.EXPECT().Publish(gomock.Any(), tc.batches[0]).Return(tc.publishError)
+				pub = failing
+			}
+
+			repo := NewMockRelayRepository(ctrl)
+			if tc.returnError != nil {
+				repo.EXPECT().PublishOutbox(gomock.Any(), 2, gomock.Any()).Return(0, tc.returnError)
+			} else {
+				repo.EXPECT().PublishOutbox(gomock.Any(), 2, gomock.Any()).
+					DoAndReturn(outboxBatches(tc.batches...)).
+					Times(tc.expectedCalls)
+			}
+
+			cfg := &config.Config{}
+			cfg.Outbox.BatchSize = 2
+
+			zp, _ := zap.NewDevelopment()
+			relay := worker.RegisterRelay(fxtest.NewLifecycle(t), repo, pub, cfg, zp)
+
+			relay.Relay(context.Background())
+
+			a.Equal(tc.expectedEvents, memory.Events())
+		})
+	}
+}
+
+func TestRelay_Purge(t *testing.T) {
+	a := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cfg := &config.Config{}
+	cfg.Outbox.Retention = time.Hour
+
+	repo := NewMockRelayRepository(ctrl)
+	repo.EXPECT().PurgeOutbox(context.Background(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, before time.Time) (int64, error) {
+			a.WithinDuration(time.Now().Add(-time.Hour), before, time.Minute)
+			return 10, nil
+		})
+
+	zp, _ := zap.NewDevelopment()
+	relay := worker.RegisterRelay(fxtest.NewLifecycle(t), repo, publisher.NewMemory(), cfg, zp)
+
+	relay.Purge(context.Background())
+}
+
+func TestRelay_Lifecycle(t *testing.T) {
+	a := assert.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	memory := publisher.NewMemory()
+
+	repo := NewMockRelayRepository(ctrl)
+	repo.EXPECT().PublishOutbox(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(outboxBatches(testEvents)).
+		MinTimes(1)
+
+	cfg := &config.Config{}
+	cfg.Outbox.Interval = time.Hour
+
+	zp, _ := zap.NewDevelopment()
+	lc := fxtest.NewLifecycle(t)
+	worker.RegisterRelay(lc, repo, memory, cfg, zp)
+
+	lc.RequireStart()
+
+	a.Eventually(func() bool {
+		return len(memory.Events()) == len(testEvents)
+	}, time.Second, 10*time.Millisecond)
+
+	lc.RequireStop()
+
+	a.Equal(testEvents, memory.Events())
+}
@@ -0,0 +1,225 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+)
+
+//go:generate mockgen -source=webhooks.go -destination=webhooks_mock_test.go -package=worker_test
+
+const (
+	defaultDispatchInterval = time.Second
+	defaultDispatchBatch    = 50
+	defaultMaxAttempts      = 10
+	defaultBackoff          = 10 * time.Second
+	defaultMaxBackoff       = time.Hour
+	defaultSendTimeout      = 10 * time.Second
+)
+
+// WebhookRepository keeps queue of webhook deliveries.
+type WebhookRepository interface {
+	ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	FinishDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+// WebhookSender sends delivery and returns response status. Error means the attempt failed.
+type WebhookSender interface {
+	Send(ctx context.Context, delivery models.WebhookDelivery) (int, error)
+}
+
+// Dispatcher sends pending webhook deliveries. Failed attempts are retried with exponential backoff,
+// deliveries which exhausted their attempts are marked dead.
+type Dispatcher struct {
+	repo        WebhookRepository
+	sender      WebhookSender
+	interval    time.Duration
+	lease       time.Duration
+	batchSize   int
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	logger      *zap.Logger
+}
+
+// RegisterDispatcher creates webhook dispatcher and binds it to the application lifecycle.
+func RegisterDispatcher(lc fx.Lifecycle, repo WebhookRepository, sender WebhookSender, config *config.Config, logger *zap.Logger) *Dispatcher {
+	cfg := config.Webhooks
+
+	dispatcher := &Dispatcher{
+		repo:        repo,
+		sender:      sender,
+		interval:    cfg.Interval,
+		lease:       2 * cfg.Timeout,
+		batchSize:   cfg.BatchSize,
+		maxAttempts: cfg.MaxAttempts,
+		backoff:     cfg.Backoff,
+		maxBackoff:  cfg.MaxBackoff,
+		logger:      logger,
+	}
+
+	if dispatcher.interval <= 0 {
+		dispatcher.interval = defaultDispatchInterval
+	}
+
+	if dispatcher.lease <= 0 {
+		dispatcher.lease = 2 * defaultSendTimeout
+	}
+
+	if dispatcher.batchSize <= 0 {
+		dispatcher.batchSize = defaultDispatchBatch
+	}
+
+	if dispatcher.maxAttempts <= 0 {
+		dispatcher.maxAttempts = defaultMaxAttempts
+	}
+
+	if dispatcher.backoff <= 0 {
+		dispatcher.backoff = defaultBackoff
+	}
+
+	if dispatcher.maxBackoff <= 0 {
+		dispatcher.maxBackoff = defaultMaxBackoff
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+				dispatcher.run(ctx)
+			}()
+
+			logger.Info("Webhook dispatcher started", zap.Duration("interval", dispatcher.interval))
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			logger.Info("Webhook dispatcher stopped")
+
+			return nil
+		},
+	})
+
+	return dispatcher
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.Dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch sends batches of due deliveries until none are left.
+func (d *Dispatcher) Dispatch(ctx context.Context) {
+	for {
+		now := time.Now()
+
+		deliveries, err := d.repo.ClaimDeliveries(ctx, now, now.Add(d.lease), d.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				d.logger.Error("Error while claiming webhook deliveries", zap.Error(err))
+			}
+
+			return
+		}
+
+		wg := &sync.WaitGroup{}
+		wg.Add(len(deliveries))
+
+		for i := range deliveries {
+			go func(delivery *models.WebhookDelivery) {
+				defer wg.Done()
+				d.attempt(ctx, delivery)
+			}(&deliveries[i])
+		}
+
+		wg.Wait()
+
+		if len(deliveries) < d.batchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// attempt sends delivery once and stores the outcome. Attempt interrupted by shutdown is not counted,
+// delivery is claimed again once its lease is over.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	status, err := d.sender.Send(ctx, *delivery)
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now()
+
+	delivery.Attempts++
+	delivery.LastStatus = status
+	delivery.NextAttemptAt = nil
+
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= d.maxAttempts:
+		delivery.Status = models.DeliveryDead
+		delivery.LastError = err.Error()
+
+		d.logger.Warn("Webhook delivery is dead",
+			zap.Int64("delivery", delivery.ID),
+			zap.String("webhook", delivery.WebhookID),
+			zap.Error(err),
+		)
+	default:
+		next := now.Add(Backoff(d.backoff, d.maxBackoff, delivery.Attempts))
+
+		delivery.Status = models.DeliveryPending
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = &next
+	}
+
+	if err = d.repo.FinishDelivery(ctx, delivery); err != nil && ctx.Err() == nil {
+		d.logger.Error("Error while storing webhook delivery", zap.Int64("delivery", delivery.ID), zap.Error(err))
+	}
+}
+
+// Backoff returns delay before the next attempt after the given amount of failed ones:
+// base delay doubled after every failure, but not more than max.
+func Backoff(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+
+	return delay
+}
@@ -0,0 +1,163 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"github.com/dupreehkuda/avito-segments/internal/config"
+	"github.com/dupreehkuda/avito-segments/internal/models"
+	"github.com/dupreehkuda/avito-segments/internal/worker"
+)
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	a := assert.New(t)
+
+	failure := errors.New("unexpected status 500")
+
+	testCases := []struct {
+		name             string
+		attempts         int
+		sendStatus       int
+		sendError        error
+		expectedStatus   string
+		expectedAttempts int
+		expectedBackoff  time.Duration
+		expectedError    string
+	}{
+		{
+			name:             "Delivered",
+			sendStatus:       200,
+			expectedStatus:   models.DeliveryDelivered,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "First failure is retried",
+			sendStatus:       500,
+			sendError:        failure,
+			expectedStatus:   models.DeliveryPending,
+			expectedAttempts: 1,
+			expectedBackoff:  time.Second,
+			expectedError:    failure.Error(),
+		},
+		{
+			name:             "Backoff grows",
+			attempts:         2,
+			sendStatus:       500,
+			sendError:        failure,
+			expectedStatus:   models.DeliveryPending,
+			expectedAttempts: 3,
+			expectedBackoff:  4 * time.Second,
+			expectedError:    failure.Error(),
+		},
+		{
+			name:             "Attempts exhausted",
+			attempts:         4,
+			sendError:        failure,
+			expectedStatus:   models.DeliveryDead,
+			expectedAttempts: 5,
+			expectedError:    failure.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			delivery := models.WebhookDelivery{
+				ID:        1,
+				WebhookID: "5f0c6a3e-4a0b-4c64-9a4e-2b7f3c1d9e10",
+				Status:    models.DeliveryPending,
+				Attempts:  tc.attempts,
+				URL:       "https://example.com/hooks",
+			}
+
+			repo := NewMockWebhookRepository(ctrl)
+			repo.EXPECT().ClaimDeliveries(context.Background(), gomock.Any(), gomock.Any(), 10).
+				Return([]models.WebhookDelivery{delivery}, nil)
+			repo.EXPECT().FinishDelivery(context.Background(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, finished *models.WebhookDelivery) error {
+					a.Equal(tc.expectedStatus, finished.Status)
+					a.Equal(tc.expectedAttempts, finished.Attempts)
+					a.Equal(tc.sendStatus, finished.LastStatus)
+					a.Equal(tc.expectedError, finished.LastError)
+
+					if tc.expectedBackoff > 0 {
+						a.WithinDuration(time.Now().Add(tc.expectedBackoff), *finished.NextAttemptAt, time.Second/2)
+					} else {
+						a.Nil(finished.NextAttemptAt)
+					}
+
+					if tc.expectedStatus == models.DeliveryDelivered {
+						a.NotNil(finished.DeliveredAt)
+					}
+
+					return nil
+				})
+
+			sender := NewMockWebhookSender(ctrl)
+			sender.EXPECT().Send(context.Background(), delivery).Return(tc.sendStatus, tc.sendError)
+
+			cfg := &config.Config{}
+			cfg.Webhooks.BatchSize = 10
+			cfg.Webhooks.MaxAttempts = 5
+			cfg.Webhooks.Backoff = time.Second
+
+			zp, _ := zap.NewDevelopment()
+			dispatcher := worker.RegisterDispatcher(fxtest.NewLifecycle(t), repo, sender, cfg, zp)
+
+			dispatcher.Dispatch(context.Background())
+		})
+	}
+}
+
+func TestDispatcher_DispatchDrains(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	batch := []models.WebhookDelivery{{ID: 1}, {ID: 2}}
+
+	repo := NewMockWebhookRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().ClaimDeliveries(context.Background(), gomock.Any(), gomock.Any(), 2).Return(batch, nil),
+		repo.EXPECT().ClaimDeliveries(context.Background(), gomock.Any(), gomock.Any(), 2).Return(nil, context.DeadlineExceeded),
+	)
+	repo.EXPECT().FinishDelivery(context.Background(), gomock.Any()).Return(nil).Times(2)
+
+	sender := NewMockWebhookSender(ctrl)
+	sender.EXPECT().Send(context.Background(), gomock.Any()).Return(204, nil).Times(2)
+
+	cfg := &config.Config{}
+	cfg.Webhooks.BatchSize = 2
+
+	zp, _ := zap.NewDevelopment()
+	dispatcher := worker.RegisterDispatcher(fxtest.NewLifecycle(t), repo, sender, cfg, zp)
+
+	dispatcher.Dispatch(context.Background())
+}
+
+func TestBackoff(t *testing.T) {
+	a := assert.New(t)
+
+	testCases := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: 10 * time.Second},
+		{attempts: 2, expected: 20 * time.Second},
+		{attempts: 4, expected: 80 * time.Second},
+		{attempts: 10, expected: time.Hour},
+		{attempts: 1000, expected: time.Hour},
+	}
+
+	for _, tc := range testCases {
+		a.Equal(tc.expected, worker.Backoff(10*time.Second, time.Hour, tc.attempts))
+	}
+}
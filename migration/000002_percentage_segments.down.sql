@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS enroll_user ON users;
+DROP FUNCTION IF EXISTS enroll_percentage_segments();
+DROP FUNCTION IF EXISTS segment_bucket(text, text);
+ALTER TABLE segments DROP COLUMN IF EXISTS percentage;
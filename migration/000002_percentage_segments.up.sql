@@ -0,0 +1,27 @@
+ALTER TABLE segments ADD COLUMN IF NOT EXISTS percentage integer NOT NULL DEFAULT 0;
+
+CREATE OR REPLACE FUNCTION segment_bucket(user_id text, slug text) RETURNS integer AS $$
+    SELECT (('x' || substr(md5(user_id || ':' || slug), 1, 8))::bit(32)::bigint % 100)::integer;
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION enroll_percentage_segments() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO user_segments (slug, user_id, created_at)
+    SELECT slug, NEW.id, now()
+    FROM segments
+    WHERE deleted_at IS NULL
+      AND percentage > 0
+      AND segment_bucket(NEW.id, slug) < percentage
+    ON CONFLICT (slug, user_id) DO NOTHING;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Deferred so explicit assignments made in the same transaction win over automatic ones.
+DROP TRIGGER IF EXISTS enroll_user ON users;
+CREATE CONSTRAINT TRIGGER enroll_user
+    AFTER INSERT ON users
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+EXECUTE FUNCTION enroll_percentage_segments();
@@ -0,0 +1 @@
+UPDATE user_segments SET expired_at = '0001-01-01 00:00:00+00' WHERE expired_at IS NULL;
@@ -0,0 +1,2 @@
+-- Memberships without expiry used to be stored with zero time instead of NULL.
+UPDATE user_segments SET expired_at = NULL WHERE expired_at < '0002-01-01';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_segment_events;
+DROP INDEX IF EXISTS user_segments_pending_expiry_idx;
+ALTER TABLE user_segments DROP COLUMN IF EXISTS expired;
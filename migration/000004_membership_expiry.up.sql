@@ -0,0 +1,15 @@
+ALTER TABLE user_segments ADD COLUMN IF NOT EXISTS expired boolean NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS user_segments_pending_expiry_idx
+    ON user_segments (expired_at)
+    WHERE NOT expired AND expired_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS user_segment_events (
+                                                   id bigserial PRIMARY KEY,
+                                                   user_id text NOT NULL,
+                                                   slug text NOT NULL,
+                                                   method text NOT NULL,
+                                                   created_at timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS user_segment_events_created_at_idx ON user_segment_events (created_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS user_segment_events_user_id_idx;
+ALTER TABLE user_segment_events DROP COLUMN IF EXISTS expire_at;
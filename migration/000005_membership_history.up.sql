@@ -0,0 +1,43 @@
+DO $$
+BEGIN
+    IF NOT EXISTS(
+        SELECT 1
+        FROM information_schema.columns
+        WHERE table_name = 'user_segment_events' AND column_name = 'expire_at'
+    ) THEN
+        ALTER TABLE user_segment_events ADD COLUMN expire_at timestamptz;
+
+        -- One-time backfill of history from current memberships.
+        INSERT INTO user_segment_events (user_id, slug, method, expire_at, created_at)
+        SELECT user_id, slug, 'added', expired_at, created_at
+        FROM user_segments;
+
+        INSERT INTO user_segment_events (user_id, slug, method, created_at)
+        SELECT user_id, slug, 'deleted', deleted_at
+        FROM user_segments
+        WHERE deleted_at IS NOT NULL;
+    END IF;
+END;
+$$;
+
+CREATE INDEX IF NOT EXISTS user_segment_events_user_id_idx ON user_segment_events (user_id, created_at);
+
+CREATE OR REPLACE FUNCTION enroll_percentage_segments() RETURNS TRIGGER AS $$
+BEGIN
+    WITH enrolled AS (
+        INSERT INTO user_segments (slug, user_id, created_at)
+        SELECT slug, NEW.id, now()
+        FROM segments
+        WHERE deleted_at IS NULL
+          AND percentage > 0
+          AND segment_bucket(NEW.id, slug) < percentage
+        ON CONFLICT (slug, user_id) DO NOTHING
+        RETURNING user_id, slug, created_at
+    )
+    INSERT INTO user_segment_events (user_id, slug, method, created_at)
+    SELECT user_id, slug, 'added', created_at
+    FROM enrolled;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
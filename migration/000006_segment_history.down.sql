@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS user_segment_events_user_slug_idx;
+DROP TABLE IF EXISTS segment_events;
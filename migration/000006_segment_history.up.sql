@@ -0,0 +1,29 @@
+DO $$
+BEGIN
+    IF NOT EXISTS(
+        SELECT 1
+        FROM information_schema.tables
+        WHERE table_name = 'segment_events'
+    ) THEN
+        CREATE TABLE segment_events (
+                                        id bigserial PRIMARY KEY,
+                                        slug text NOT NULL,
+                                        method text NOT NULL,
+                                        created_at timestamptz NOT NULL
+        );
+
+        -- One-time backfill of history from current catalog.
+        INSERT INTO segment_events (slug, method, created_at)
+        SELECT slug, 'created', created_at
+        FROM segments;
+
+        INSERT INTO segment_events (slug, method, created_at)
+        SELECT slug, 'deleted', deleted_at
+        FROM segments
+        WHERE deleted_at IS NOT NULL;
+    END IF;
+END;
+$$;
+
+CREATE INDEX IF NOT EXISTS segment_events_slug_idx ON segment_events (slug, created_at);
+CREATE INDEX IF NOT EXISTS user_segment_events_user_slug_idx ON user_segment_events (user_id, slug, created_at);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS import_jobs (
+                                           id uuid PRIMARY KEY,
+                                           slug text NOT NULL,
+                                           status text NOT NULL,
+                                           total_rows integer NOT NULL DEFAULT 0,
+                                           processed_rows integer NOT NULL DEFAULT 0,
+                                           failed_rows integer NOT NULL DEFAULT 0,
+                                           error text NOT NULL DEFAULT '',
+                                           created_at timestamptz NOT NULL,
+                                           started_at timestamptz,
+                                           finished_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS import_job_errors (
+                                                 job_id uuid NOT NULL REFERENCES import_jobs (id) ON DELETE CASCADE,
+                                                 row_number integer NOT NULL,
+                                                 user_id text NOT NULL,
+                                                 error text NOT NULL,
+                                                 PRIMARY KEY (job_id, row_number)
+);
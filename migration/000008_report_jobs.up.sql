@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS report_jobs (
+                                           id uuid PRIMARY KEY,
+                                           year integer NOT NULL,
+                                           month integer NOT NULL,
+                                           status text NOT NULL,
+                                           row_count integer NOT NULL DEFAULT 0,
+                                           file text NOT NULL DEFAULT '',
+                                           error text NOT NULL DEFAULT '',
+                                           created_at timestamptz NOT NULL,
+                                           started_at timestamptz,
+                                           finished_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS report_jobs_period_idx ON report_jobs (year, month, finished_at);
@@ -0,0 +1,3 @@
+ALTER TABLE report_jobs DROP COLUMN IF EXISTS generated_at;
+ALTER TABLE report_jobs DROP COLUMN IF EXISTS range_to;
+ALTER TABLE report_jobs DROP COLUMN IF EXISTS range_from;
@@ -0,0 +1,3 @@
+ALTER TABLE report_jobs ADD COLUMN IF NOT EXISTS range_from timestamptz;
+ALTER TABLE report_jobs ADD COLUMN IF NOT EXISTS range_to timestamptz;
+ALTER TABLE report_jobs ADD COLUMN IF NOT EXISTS generated_at timestamptz;
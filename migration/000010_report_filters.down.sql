@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS report_jobs_report_idx;
+
+ALTER TABLE report_jobs DROP COLUMN IF EXISTS methods;
+ALTER TABLE report_jobs DROP COLUMN IF EXISTS user_ids;
+ALTER TABLE report_jobs DROP COLUMN IF EXISTS slugs;
+ALTER TABLE report_jobs DROP COLUMN IF EXISTS report_id;
@@ -0,0 +1,6 @@
+ALTER TABLE report_jobs ADD COLUMN IF NOT EXISTS report_id text;
+ALTER TABLE report_jobs ADD COLUMN IF NOT EXISTS slugs text[];
+ALTER TABLE report_jobs ADD COLUMN IF NOT EXISTS user_ids text[];
+ALTER TABLE report_jobs ADD COLUMN IF NOT EXISTS methods text[];
+
+CREATE INDEX IF NOT EXISTS report_jobs_report_idx ON report_jobs (report_id, finished_at);
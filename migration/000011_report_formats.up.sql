@@ -0,0 +1 @@
+ALTER TABLE report_jobs ADD COLUMN IF NOT EXISTS format text NOT NULL DEFAULT 'csv';
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS user_segment_events_slug_idx ON user_segment_events (slug, created_at);
@@ -0,0 +1,21 @@
+CREATE OR REPLACE FUNCTION enroll_percentage_segments() RETURNS TRIGGER AS $$
+BEGIN
+    WITH enrolled AS (
+        INSERT INTO user_segments (slug, user_id, created_at)
+        SELECT slug, NEW.id, now()
+        FROM segments
+        WHERE deleted_at IS NULL
+          AND percentage > 0
+          AND segment_bucket(NEW.id, slug) < percentage
+        ON CONFLICT (slug, user_id) DO NOTHING
+        RETURNING user_id, slug, created_at
+    )
+    INSERT INTO user_segment_events (user_id, slug, method, created_at)
+    SELECT user_id, slug, 'added', created_at
+    FROM enrolled;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TABLE IF EXISTS outbox;
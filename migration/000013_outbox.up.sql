@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS outbox (
+                                      id bigserial PRIMARY KEY,
+                                      kind text NOT NULL,
+                                      user_id text,
+                                      slug text NOT NULL,
+                                      method text NOT NULL,
+                                      expire_at timestamptz,
+                                      created_at timestamptz NOT NULL,
+                                      published_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_published_at_idx ON outbox (published_at);
+
+CREATE OR REPLACE FUNCTION enroll_percentage_segments() RETURNS TRIGGER AS $$
+BEGIN
+    WITH enrolled AS (
+        INSERT INTO user_segments (slug, user_id, created_at)
+        SELECT slug, NEW.id, now()
+        FROM segments
+        WHERE deleted_at IS NULL
+          AND percentage > 0
+          AND segment_bucket(NEW.id, slug) < percentage
+        ON CONFLICT (slug, user_id) DO NOTHING
+        RETURNING user_id, slug, created_at
+    ), events AS (
+        INSERT INTO user_segment_events (user_id, slug, method, created_at)
+        SELECT user_id, slug, 'added', created_at
+        FROM enrolled
+        RETURNING user_id, slug, method, expire_at, created_at
+    )
+    INSERT INTO outbox (kind, user_id, slug, method, expire_at, created_at)
+    SELECT 'membership', user_id, slug, method, expire_at, created_at
+    FROM events
+    ORDER BY slug;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
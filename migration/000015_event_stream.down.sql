@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS outbox_stream_idx;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS txid;
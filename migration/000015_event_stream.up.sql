@@ -0,0 +1,3 @@
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS txid xid8 NOT NULL DEFAULT pg_current_xact_id();
+
+CREATE INDEX IF NOT EXISTS outbox_stream_idx ON outbox (txid, id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+                                        id uuid PRIMARY KEY,
+                                        name text NOT NULL,
+                                        prefix text NOT NULL,
+                                        key_hash text NOT NULL UNIQUE,
+                                        scopes text[] NOT NULL,
+                                        created_at timestamptz NOT NULL,
+                                        revoked_at timestamptz
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+                                         id bigserial PRIMARY KEY,
+                                         actor text NOT NULL,
+                                         actor_name text NOT NULL DEFAULT '',
+                                         request_id text NOT NULL DEFAULT '',
+                                         operation text NOT NULL,
+                                         slugs text[] NOT NULL DEFAULT '{}',
+                                         user_id text,
+                                         before jsonb,
+                                         after jsonb,
+                                         created_at timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_user_idx ON audit_log (user_id, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_slugs_idx ON audit_log USING gin (slugs);
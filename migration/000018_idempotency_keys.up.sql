@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+                                                principal text NOT NULL,
+                                                key text NOT NULL,
+                                                request_hash text NOT NULL,
+                                                status integer,
+                                                content_type text NOT NULL DEFAULT '',
+                                                body bytea,
+                                                created_at timestamptz NOT NULL,
+                                                expires_at timestamptz NOT NULL,
+                                                PRIMARY KEY (principal, key)
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS import_jobs_pending_idx;
+DROP TABLE IF EXISTS import_job_rows;
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS heartbeat_at;
@@ -0,0 +1,11 @@
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS heartbeat_at timestamptz;
+
+CREATE TABLE IF NOT EXISTS import_job_rows (
+                                               job_id uuid NOT NULL REFERENCES import_jobs (id) ON DELETE CASCADE,
+                                               row_number integer NOT NULL,
+                                               user_id text NOT NULL,
+                                               expire_at timestamptz,
+                                               PRIMARY KEY (job_id, row_number)
+);
+
+CREATE INDEX IF NOT EXISTS import_jobs_pending_idx ON import_jobs (heartbeat_at) WHERE status IN ('queued', 'running');
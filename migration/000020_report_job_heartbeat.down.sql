@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS report_jobs_pending_idx;
+ALTER TABLE report_jobs DROP COLUMN IF EXISTS heartbeat_at;
@@ -0,0 +1,3 @@
+ALTER TABLE report_jobs ADD COLUMN IF NOT EXISTS heartbeat_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS report_jobs_pending_idx ON report_jobs (heartbeat_at) WHERE status IN ('queued', 'running');
@@ -0,0 +1,33 @@
+CREATE OR REPLACE FUNCTION enroll_percentage_segments() RETURNS TRIGGER AS $$
+BEGIN
+    WITH enrolled AS (
+        INSERT INTO user_segments (slug, user_id, created_at)
+        SELECT slug, NEW.id, now()
+        FROM segments
+        WHERE deleted_at IS NULL
+          AND percentage > 0
+          AND segment_bucket(NEW.id, slug) < percentage
+        ON CONFLICT (slug, user_id) DO NOTHING
+        RETURNING user_id, slug, created_at
+    ), events AS (
+        INSERT INTO user_segment_events (user_id, slug, method, created_at)
+        SELECT user_id, slug, 'added', created_at
+        FROM enrolled
+        RETURNING user_id, slug, method, expire_at, created_at
+    ), outboxed AS (
+        INSERT INTO outbox (kind, user_id, slug, method, expire_at, created_at)
+        SELECT 'membership', user_id, slug, method, expire_at, created_at
+        FROM events
+        ORDER BY slug
+        RETURNING id, user_id, slug, method, expire_at, created_at
+    )
+    INSERT INTO webhook_deliveries (webhook_id, event_id, user_id, slug, method, expire_at, event_at,
+                                    status, next_attempt_at, created_at)
+    SELECT webhooks.id, outboxed.id, outboxed.user_id, outboxed.slug, outboxed.method,
+           outboxed.expire_at, outboxed.created_at, 'pending', now(), now()
+    FROM outboxed
+    JOIN webhooks ON webhooks.slugs @> ARRAY[outboxed.slug];
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,3 @@
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS request_id;
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS actor_name;
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS actor;
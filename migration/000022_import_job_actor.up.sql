@@ -0,0 +1,3 @@
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS actor text NOT NULL DEFAULT 'system';
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS actor_name text NOT NULL DEFAULT '';
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS request_id text NOT NULL DEFAULT '';
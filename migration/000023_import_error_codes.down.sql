@@ -0,0 +1,3 @@
+ALTER TABLE import_job_errors DROP COLUMN IF EXISTS user_ids;
+ALTER TABLE import_job_errors DROP COLUMN IF EXISTS slugs;
+ALTER TABLE import_job_errors DROP COLUMN IF EXISTS code;
@@ -0,0 +1,3 @@
+ALTER TABLE import_job_errors ADD COLUMN IF NOT EXISTS code text NOT NULL DEFAULT '';
+ALTER TABLE import_job_errors ADD COLUMN IF NOT EXISTS slugs text[];
+ALTER TABLE import_job_errors ADD COLUMN IF NOT EXISTS user_ids text[];
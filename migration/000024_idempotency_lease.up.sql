@@ -0,0 +1,4 @@
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_until timestamptz;
+
+-- Keys left in progress before the lease existed are free to be claimed again.
+UPDATE idempotency_keys SET locked_until = created_at WHERE status IS NULL AND locked_until IS NULL;
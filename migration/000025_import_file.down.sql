@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS import_job_rows (
+                                               job_id uuid NOT NULL REFERENCES import_jobs (id) ON DELETE CASCADE,
+                                               row_number integer NOT NULL,
+                                               user_id text NOT NULL,
+                                               expire_at timestamptz,
+                                               PRIMARY KEY (job_id, row_number)
+);
@@ -0,0 +1,2 @@
+-- Import jobs read uploaded file from storage instead of rows stored with the job.
+DROP TABLE IF EXISTS import_job_rows;